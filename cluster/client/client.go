@@ -3,7 +3,9 @@ package client
 import (
 	"context"
 	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/code"
 	"github.com/dobyte/due/component"
+	"github.com/dobyte/due/errors"
 	"github.com/dobyte/due/log"
 	"github.com/dobyte/due/network"
 	"github.com/dobyte/due/packet"
@@ -26,6 +28,7 @@ type Client struct {
 	rw                  sync.RWMutex
 	state               cluster.State
 	conn                network.Conn
+	token               []byte // 会话恢复令牌
 }
 
 func NewClient(opts ...Option) *Client {
@@ -51,35 +54,37 @@ func (c *Client) Name() string {
 }
 
 // Init 初始化节点
-func (c *Client) Init() {
+func (c *Client) Init() error {
 	if c.opts.client == nil {
-		log.Fatal("client plugin is not injected")
+		return errors.New("client plugin is not injected")
 	}
 
 	if c.opts.codec == nil {
-		log.Fatal("codec plugin is not injected")
+		return errors.New("codec plugin is not injected")
 	}
 
 	c.state = cluster.Work
+
+	return nil
 }
 
 // Start 启动组件
-func (c *Client) Start() {
+func (c *Client) Start() error {
 	c.opts.client.OnConnect(c.handleConnect)
 	c.opts.client.OnDisconnect(c.handleDisconnect)
 	c.opts.client.OnReceive(c.handleReceive)
 
-	if err := c.dial(); err != nil {
-		log.Fatalf("connect server failed: %v", err)
-	}
+	return c.dial()
 }
 
 // Destroy 销毁组件
-func (c *Client) Destroy() {
+func (c *Client) Destroy() error {
 	c.rw.Lock()
 	c.conn = nil
 	c.state = cluster.Shut
 	c.rw.Unlock()
+
+	return nil
 }
 
 // Proxy 获取节点代理
@@ -115,7 +120,16 @@ func (c *Client) handleConnect(conn network.Conn) {
 }
 
 // 处理断开连接
-func (c *Client) handleDisconnect(_ network.Conn) {
+// 重定向网关时旧连接的断开不会触发断开连接事件
+func (c *Client) handleDisconnect(conn network.Conn) {
+	c.rw.RLock()
+	isCurrent := c.conn == conn
+	c.rw.RUnlock()
+
+	if !isCurrent {
+		return
+	}
+
 	handler, ok := c.events[cluster.Disconnect]
 	if !ok {
 		return
@@ -125,25 +139,69 @@ func (c *Client) handleDisconnect(_ network.Conn) {
 }
 
 // 处理接收到的消息
-func (c *Client) handleReceive(_ network.Conn, data []byte, _ int) {
+// 网关下发的恢复令牌由客户端保存，用于断线重连后恢复会话；
+// 错误响应交由原请求路由的处理器处理，处理器可通过请求的Err方法获取携带错误码的错误
+func (c *Client) handleReceive(conn network.Conn, data []byte, _ int) {
 	message, err := packet.Unpack(data)
 	if err != nil {
 		log.Errorf("unpack message failed: %v", err)
 		return
 	}
 
-	handler, ok := c.routes[message.Route]
+	if message.Route == cluster.RedirectRoute {
+		go c.redirect(conn, string(message.Buffer))
+		return
+	}
+
+	if message.Route == cluster.ResumeRoute {
+		c.rw.Lock()
+		c.token = message.Buffer
+		c.rw.Unlock()
+		return
+	}
+
+	req := &request{client: c, message: message}
+
+	if message.Route == cluster.ErrorRoute {
+		reply := &cluster.ErrorReply{}
+		if err = reply.Unmarshal(message.Buffer); err != nil {
+			log.Errorf("unmarshal error reply failed: %v", err)
+			return
+		}
+
+		req.message = &packet.Message{Seq: message.Seq, Route: reply.Route}
+		req.err = errors.NewError(reply.Message, code.NewCode(int(reply.Code), reply.Message, nil))
+	}
+
+	handler, ok := c.routes[req.message.Route]
 	if ok {
-		handler(&request{client: c, message: message})
+		handler(req)
 	} else if c.defaultRouteHandler != nil {
-		c.defaultRouteHandler(&request{client: c, message: message})
+		c.defaultRouteHandler(req)
 	} else {
-		log.Errorf("the route handler is not registered, route:%v", message.Route)
+		log.Errorf("the route handler is not registered, route:%v", req.message.Route)
+	}
+}
+
+// 重定向到新网关
+// 新连接建立后会触发重连事件，已获得恢复令牌时凭令牌在新网关恢复会话，随后关闭旧连接
+func (c *Client) redirect(old network.Conn, addr string) {
+	if err := c.dial(addr); err != nil {
+		log.Errorf("redirect to gate failed, addr: %s, err: %v", addr, err)
+		return
+	}
+
+	if err := c.proxy.Resume(); err != nil && err != ErrNoResumeToken {
+		log.Warnf("resume session after redirect failed, addr: %s, err: %v", addr, err)
+	}
+
+	if err := old.Close(); err != nil {
+		log.Warnf("close redirected connection failed: %v", err)
 	}
 }
 
 // 拨号
-func (c *Client) dial() error {
+func (c *Client) dial(addr ...string) error {
 	c.rw.RLock()
 	isShut := c.state == cluster.Shut
 	c.rw.RUnlock()
@@ -152,7 +210,7 @@ func (c *Client) dial() error {
 		return ErrClientShut
 	}
 
-	_, err := c.opts.client.Dial()
+	_, err := c.opts.client.Dial(addr...)
 	return err
 }
 
@@ -9,6 +9,7 @@ import (
 var (
 	ErrClientShut       = errors.New("client is shut")
 	ErrConnectionClosed = errors.New("connection closed")
+	ErrNoResumeToken    = errors.New("no resume token")
 )
 
 type Proxy interface {
@@ -28,6 +29,8 @@ type Proxy interface {
 	Push(seq, route int32, message interface{}) error
 	// Reconnect 重新连接
 	Reconnect() error
+	// Resume 恢复会话，重新连接后发送网关下发的恢复令牌，恢复失败时以恢复路由的错误响应通知
+	Resume() error
 	// Disconnect 断开连接
 	Disconnect() error
 }
@@ -147,6 +150,31 @@ func (p *proxy) Reconnect() error {
 	return p.client.dial()
 }
 
+// Resume 恢复会话
+func (p *proxy) Resume() error {
+	p.client.rw.RLock()
+	defer p.client.rw.RUnlock()
+
+	if p.client.state == cluster.Shut {
+		return ErrClientShut
+	}
+
+	if p.client.conn == nil {
+		return ErrConnectionClosed
+	}
+
+	if len(p.client.token) == 0 {
+		return ErrNoResumeToken
+	}
+
+	msg, err := packet.Pack(&packet.Message{Route: cluster.ResumeRoute, Buffer: p.client.token})
+	if err != nil {
+		return err
+	}
+
+	return p.client.conn.Push(msg)
+}
+
 // Disconnect 断开连接
 func (p *proxy) Disconnect() error {
 	p.client.rw.RLock()
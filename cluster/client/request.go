@@ -16,7 +16,9 @@ type Request interface {
 	Route() int32
 	// Data 获取数据
 	Data() interface{}
-	// Parse 解析请求
+	// Err 获取错误，网关或节点以错误响应原请求时返回携带错误码的错误
+	Err() error
+	// Parse 解析请求，错误响应时返回错误
 	Parse(v interface{}) error
 	// Context 获取上线文
 	Context() context.Context
@@ -28,6 +30,7 @@ type Request interface {
 type request struct {
 	client  *Client         // 客户端
 	message *packet.Message // 消息
+	err     error           // 错误
 }
 
 // CID 获取来源连接ID
@@ -55,8 +58,17 @@ func (r *request) Data() interface{} {
 	return r.message.Buffer
 }
 
+// Err 获取错误
+func (r *request) Err() error {
+	return r.err
+}
+
 // Parse 解析消息
 func (r *request) Parse(v interface{}) (err error) {
+	if r.err != nil {
+		return r.err
+	}
+
 	buffer := r.message.Buffer
 
 	if r.client.opts.decryptor != nil {
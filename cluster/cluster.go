@@ -27,3 +27,11 @@ const (
 	Reconnect                   // 断线重连
 	Disconnect                  // 断开连接
 )
+
+// 框架保留路由，用于网关与客户端之间传递控制消息，业务路由请勿使用负数路由
+const (
+	RedirectRoute int32 = -1 // 重定向网关，消息内容为新网关的客户端连接地址，客户端连接新网关后凭恢复令牌恢复会话以保留用户绑定
+	ErrorRoute    int32 = -2 // 错误响应，消息序列号为原请求的序列号，消息内容为ErrorReply编码后的数据
+	ResumeRoute   int32 = -3 // 会话恢复，网关绑定用户后下发恢复令牌，客户端断线重连后以该路由回传令牌恢复会话
+	KickRoute     int32 = -4 // 踢下线，网关以错误响应通知被踢下线的客户端，错误响应的路由为该路由，错误码为踢下线原因
+)
@@ -0,0 +1,181 @@
+package gate
+
+import (
+	"context"
+	"github.com/dobyte/due/code"
+	"github.com/dobyte/due/log"
+	"github.com/dobyte/due/network"
+	"github.com/dobyte/due/packet"
+	"github.com/dobyte/due/session"
+	"sync"
+	"time"
+)
+
+// Authenticator 连接鉴权器
+// 连接鉴权通过前收到的消息均交由鉴权器处理，返回大于0的用户ID时鉴权通过，网关将连接绑定到该用户并在会话中记录鉴权声明；
+// 返回错误或用户ID不大于0时本次鉴权失败，鉴权器可通过会话向客户端回复消息
+type Authenticator func(ctx context.Context, s *session.Session, message *packet.Message) (uid int64, claims map[string]string, err error)
+
+// 连接鉴权状态
+// 启用鉴权时连接在整个生命周期内持有鉴权状态，鉴权通过后停止截止定时器，用户解绑后重新等待鉴权
+type pending struct {
+	mu       sync.Mutex
+	conn     network.Conn // 连接
+	attempts int          // 已尝试次数
+	timer    *time.Timer  // 鉴权截止定时器，为nil时表示未在等待鉴权
+}
+
+// 是否启用鉴权
+func (g *Gate) authEnabled() bool {
+	return g.opts.authenticator != nil || g.opts.loginRoute > 0
+}
+
+// 等待连接鉴权，超过鉴权截止时间仍未鉴权通过则关闭连接
+func (g *Gate) awaitAuth(conn network.Conn) {
+	p := &pending{conn: conn}
+
+	g.pendings.Store(conn.ID(), p)
+
+	g.armAuth(p)
+}
+
+// 重新等待连接鉴权，用于已鉴权的用户解绑后
+func (g *Gate) reawaitAuth(cid int64) {
+	if !g.authEnabled() {
+		return
+	}
+
+	if v, ok := g.pendings.Load(cid); ok {
+		g.armAuth(v.(*pending))
+	}
+}
+
+// 重置鉴权尝试次数并启动鉴权截止定时器
+func (g *Gate) armAuth(p *pending) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(g.opts.authDeadline, func() {
+		p.mu.Lock()
+		expired := p.timer == timer
+		if expired {
+			p.timer = nil
+		}
+		p.mu.Unlock()
+
+		if expired && p.conn.UID() == 0 {
+			log.Debugf("the connection authenticate timeout, cid: %d", p.conn.ID())
+			_ = p.conn.Close(true)
+		}
+	})
+
+	p.attempts = 0
+	p.timer = timer
+}
+
+// 结束等待连接鉴权
+func (g *Gate) finishAuth(cid int64) {
+	if v, ok := g.pendings.Load(cid); ok {
+		p := v.(*pending)
+		p.mu.Lock()
+		if p.timer != nil {
+			p.timer.Stop()
+			p.timer = nil
+		}
+		p.mu.Unlock()
+	}
+}
+
+// 释放连接鉴权状态，用于连接断开时
+func (g *Gate) releaseAuth(cid int64) {
+	if v, ok := g.pendings.LoadAndDelete(cid); ok {
+		p := v.(*pending)
+		p.mu.Lock()
+		if p.timer != nil {
+			p.timer.Stop()
+			p.timer = nil
+		}
+		p.mu.Unlock()
+	}
+}
+
+// 记录一次鉴权尝试，返回已尝试次数；连接未在等待鉴权时ok为false
+func (g *Gate) attemptAuth(cid int64) (attempts int, ok bool) {
+	v, ok := g.pendings.Load(cid)
+	if !ok {
+		return 0, false
+	}
+
+	p := v.(*pending)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.timer == nil {
+		return 0, false
+	}
+
+	p.attempts++
+
+	return p.attempts, true
+}
+
+// 处理鉴权通过前收到的消息
+// 设置鉴权器时消息交由鉴权器处理，否则仅登录路由的消息会投递到节点，由节点绑定网关完成鉴权
+func (g *Gate) authenticate(conn network.Conn, message *packet.Message) {
+	attempts, ok := g.attemptAuth(conn.ID())
+	if !ok {
+		log.Warnf("the connection is not authenticated, cid: %d, route: %d", conn.ID(), message.Route)
+		return
+	}
+
+	if attempts > g.opts.authAttempts {
+		log.Warnf("the connection authenticate attempts exceeded, cid: %d", conn.ID())
+		_ = conn.Close(true)
+		return
+	}
+
+	if g.opts.authenticator == nil {
+		if message.Route != g.opts.loginRoute {
+			log.Warnf("the connection is not authenticated, cid: %d, route: %d", conn.ID(), message.Route)
+			return
+		}
+
+		g.deliver(conn, message)
+		return
+	}
+
+	s, err := g.group.GetSession(session.Conn, conn.ID())
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(g.ctx, g.opts.timeout)
+	defer cancel()
+
+	uid, claims, err := g.opts.authenticator(ctx, s, message)
+	if err != nil || uid <= 0 {
+		log.Debugf("the connection authenticate failed, cid: %d, err: %v", conn.ID(), err)
+		return
+	}
+
+	if err = g.login(ctx, s, uid); err != nil {
+		if err == ErrDuplicateLogin {
+			g.replyError(conn, message, code.DuplicateLogin)
+		} else {
+			log.Errorf("user bind failed, gid: %s, uid: %d, err: %v", g.opts.id, uid, err)
+		}
+		return
+	}
+
+	s.Bind(uid)
+	s.SetClaims(claims)
+
+	g.finishAuth(conn.ID())
+
+	g.issueResumeToken(s, uid)
+}
@@ -0,0 +1,117 @@
+package gate
+
+import (
+	"context"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/errors"
+	"github.com/dobyte/due/packet"
+	"github.com/dobyte/due/session"
+	"testing"
+	"time"
+)
+
+const testLoginRoute = 1
+
+// 仅登录路由的消息鉴权通过
+func testAuthenticator(ctx context.Context, s *session.Session, message *packet.Message) (int64, map[string]string, error) {
+	if message.Route != testLoginRoute {
+		return 0, nil, errors.New("invalid credentials")
+	}
+
+	return 1, map[string]string{"role": "admin"}, nil
+}
+
+func TestGate_AuthDeadline(t *testing.T) {
+	g := newTestGate(WithAuthenticator(testAuthenticator), WithAuthDeadline(50*time.Millisecond))
+	conn := newTestConn(1)
+	g.handleConnect(conn)
+
+	time.Sleep(150 * time.Millisecond)
+
+	if !conn.isClosed() {
+		t.Fatal("the connection should be closed after the authenticate deadline")
+	}
+}
+
+func TestGate_AuthAttempts(t *testing.T) {
+	g := newTestGate(WithAuthenticator(testAuthenticator), WithAuthAttempts(2))
+	conn := newTestConn(1)
+	g.handleConnect(conn)
+	defer g.handleDisconnect(conn)
+
+	for i := 0; i < 2; i++ {
+		receive(t, g, conn, 2)
+		if conn.isClosed() {
+			t.Fatalf("the connection closed after %d attempts", i+1)
+		}
+	}
+
+	receive(t, g, conn, 2)
+	if !conn.isClosed() {
+		t.Fatal("the connection should be closed after the authenticate attempts exceeded")
+	}
+}
+
+func TestGate_LoginRoute(t *testing.T) {
+	g := newTestGate(WithLoginRoute(testLoginRoute))
+	conn := newTestConn(1)
+	g.handleConnect(conn)
+	defer g.handleDisconnect(conn)
+
+	receive(t, g, conn, 2)
+	if message := conn.pop(50 * time.Millisecond); message != nil {
+		t.Fatalf("the message of route 2 should be dropped before authenticated, got route %d", message.Route)
+	}
+
+	// 无可用节点，登录路由的消息投递失败后回复错误
+	receive(t, g, conn, testLoginRoute)
+	if message := conn.pop(time.Second); message == nil || message.Route != cluster.ErrorRoute {
+		t.Fatal("the message of the login route should be delivered")
+	}
+}
+
+func TestGate_ReawaitAuth(t *testing.T) {
+	g := newTestGate(WithAuthenticator(testAuthenticator), WithAuthDeadline(100*time.Millisecond))
+	conn := newTestConn(1)
+	g.handleConnect(conn)
+	defer g.handleDisconnect(conn)
+
+	receive(t, g, conn, testLoginRoute)
+	if conn.UID() != 1 {
+		t.Fatalf("the connection should be bound to user 1, got %d", conn.UID())
+	}
+
+	s, err := g.group.GetSession(session.Conn, conn.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Claims()["role"] != "admin" {
+		t.Fatalf("unexpected claims: %v", s.Claims())
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if conn.isClosed() {
+		t.Fatal("the authenticated connection should not be closed")
+	}
+
+	p := &provider{gate: g}
+	if err = p.Unbind(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// 解绑后重新鉴权
+	receive(t, g, conn, testLoginRoute)
+	if conn.UID() != 1 {
+		t.Fatal("the connection should be authenticated again after unbind")
+	}
+
+	if err = p.Unbind(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if !conn.isClosed() {
+		t.Fatal("the unbound connection should be closed after the authenticate deadline")
+	}
+}
@@ -0,0 +1,180 @@
+package gate
+
+import (
+	"context"
+	"github.com/dobyte/due/locate"
+	"github.com/dobyte/due/log"
+	"sync"
+)
+
+// 频道成员，仅记录绑定到当前网关的用户
+// 本地频道成员由无到有或由有到无时通过登记函数通知，成员变更与登记串行执行，保证登记状态与本地成员一致
+type channels struct {
+	mu       sync.Mutex                    // 串行化成员变更及登记
+	rw       sync.RWMutex                  // 保护频道成员
+	members  map[string]map[int64]struct{} // 频道成员
+	joined   map[int64]map[string]struct{} // 用户已加入的频道
+	register func(channel string, held bool)
+}
+
+func newChannels(register func(channel string, held bool)) *channels {
+	return &channels{
+		members:  make(map[string]map[int64]struct{}),
+		joined:   make(map[int64]map[string]struct{}),
+		register: register,
+	}
+}
+
+// 加入频道
+func (c *channels) join(channel string, uids ...int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rw.Lock()
+	members, ok := c.members[channel]
+	if !ok {
+		members = make(map[int64]struct{}, len(uids))
+		c.members[channel] = members
+	}
+
+	for _, uid := range uids {
+		members[uid] = struct{}{}
+
+		joined, ok := c.joined[uid]
+		if !ok {
+			joined = make(map[string]struct{})
+			c.joined[uid] = joined
+		}
+		joined[channel] = struct{}{}
+	}
+	c.rw.Unlock()
+
+	if !ok {
+		c.notify(channel)
+	}
+}
+
+// 离开频道
+func (c *channels) leave(channel string, uids ...int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rw.Lock()
+	var released []string
+	for _, uid := range uids {
+		if c.doLeave(channel, uid) {
+			released = append(released, channel)
+		}
+	}
+	c.rw.Unlock()
+
+	c.notify(released...)
+}
+
+// 解散频道
+func (c *channels) dismiss(channel string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rw.Lock()
+	var released []string
+	for uid := range c.members[channel] {
+		if c.doLeave(channel, uid) {
+			released = append(released, channel)
+		}
+	}
+	c.rw.Unlock()
+
+	c.notify(released...)
+}
+
+// 离开用户已加入的所有频道
+func (c *channels) quit(uid int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rw.Lock()
+	var released []string
+	for channel := range c.joined[uid] {
+		if c.doLeave(channel, uid) {
+			released = append(released, channel)
+		}
+	}
+	c.rw.Unlock()
+
+	c.notify(released...)
+}
+
+// 离开频道，返回频道是否已无成员
+func (c *channels) doLeave(channel string, uid int64) (released bool) {
+	if members, ok := c.members[channel]; ok {
+		delete(members, uid)
+		if len(members) == 0 {
+			delete(c.members, channel)
+			released = true
+		}
+	}
+
+	if joined, ok := c.joined[uid]; ok {
+		delete(joined, channel)
+		if len(joined) == 0 {
+			delete(c.joined, uid)
+		}
+	}
+
+	return
+}
+
+// 通知登记函数频道成员由无到有，或由有到无
+func (c *channels) notify(channels ...string) {
+	if c.register == nil {
+		return
+	}
+
+	for _, channel := range channels {
+		c.register(channel, c.held(channel))
+	}
+}
+
+// 当前网关是否持有频道成员
+func (c *channels) held(channel string) bool {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	return len(c.members[channel]) > 0
+}
+
+// 获取频道成员
+func (c *channels) targets(channel string) []int64 {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	members := c.members[channel]
+	uids := make([]int64, 0, len(members))
+	for uid := range members {
+		uids = append(uids, uid)
+	}
+
+	return uids
+}
+
+// 登记当前网关是否持有频道成员，定位器未实现locate.ChannelLocator时不登记
+func (g *Gate) registerChannel(channel string, held bool) {
+	cl, ok := g.proxy.link.Locator().(locate.ChannelLocator)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(g.ctx, g.opts.timeout)
+	defer cancel()
+
+	var err error
+	if held {
+		err = cl.AddChannelGate(ctx, channel, g.opts.id)
+	} else {
+		err = cl.RemChannelGate(ctx, channel, g.opts.id)
+	}
+	if err != nil {
+		log.Errorf("register channel gate failed, channel: %s, gid: %s, held: %v, err: %v", channel, g.opts.id, held, err)
+	}
+}
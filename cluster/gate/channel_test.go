@@ -0,0 +1,36 @@
+package gate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChannels_Register(t *testing.T) {
+	var changes []string
+	c := newChannels(func(channel string, held bool) {
+		if held {
+			changes = append(changes, "+"+channel)
+		} else {
+			changes = append(changes, "-"+channel)
+		}
+	})
+
+	c.join("a", 1, 2)
+	c.join("a", 3)
+	c.join("b", 1)
+	c.leave("a", 1, 2)
+	c.quit(1)
+	c.quit(3)
+	c.join("c", 4)
+	c.dismiss("c")
+
+	// 仅在网关的本地频道成员由无到有或由有到无时登记
+	expected := []string{"+a", "+b", "-b", "-a", "+c", "-c"}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected %v, got %v", expected, changes)
+	}
+
+	if len(c.members) != 0 || len(c.joined) != 0 {
+		t.Fatalf("the members should be released, got %v, %v", c.members, c.joined)
+	}
+}
@@ -10,8 +10,13 @@ package gate
 import (
 	"context"
 	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/code"
+	"github.com/dobyte/due/errors"
+	"github.com/dobyte/due/locate"
+	"github.com/dobyte/due/router"
 	"github.com/dobyte/due/transport"
 	"github.com/dobyte/due/utils/xnet"
+	"net"
 	"sync"
 	"time"
 
@@ -26,14 +31,21 @@ import (
 
 type Gate struct {
 	component.Base
-	opts     *options
-	ctx      context.Context
-	cancel   context.CancelFunc
-	group    *session.Group
-	sessions sync.Pool
-	proxy    *proxy
-	instance *registry.ServiceInstance
-	rpc      transport.Server
+	opts       *options
+	ctx        context.Context
+	cancel     context.CancelFunc
+	group      *session.Group
+	channels   *channels
+	pendings   sync.Map // 等待鉴权的连接（连接ID -> *pending）
+	limiter    *limiter
+	routes     map[int32]RouteHandler // 网关本地路由处理器
+	resumer    *resumer               // 会话恢复
+	sessions   sync.Pool
+	proxy      *proxy
+	instance   *registry.ServiceInstance
+	rpc        transport.Server
+	started    bool // 是否已启动
+	restarting bool // 是否正在热重启
 }
 
 func NewGate(opts ...Option) *Gate {
@@ -45,7 +57,11 @@ func NewGate(opts ...Option) *Gate {
 	g := &Gate{}
 	g.opts = o
 	g.group = session.NewGroup()
+	g.limiter = newLimiter()
+	g.routes = make(map[int32]RouteHandler)
+	g.resumer = newResumer()
 	g.proxy = newProxy(g)
+	g.channels = newChannels(g.registerChannel)
 	g.sessions.New = func() interface{} { return session.NewSession() }
 	g.ctx, g.cancel = context.WithCancel(o.ctx)
 
@@ -58,63 +74,155 @@ func (g *Gate) Name() string {
 }
 
 // Init 初始化
-func (g *Gate) Init() {
+func (g *Gate) Init() error {
 	if g.opts.id == "" {
-		log.Fatal("instance id can not be empty")
+		return errors.New("instance id can not be empty")
 	}
 
 	if g.opts.server == nil {
-		log.Fatal("server component is not injected")
+		return errors.New("server component is not injected")
 	}
 
 	if g.opts.locator == nil {
-		log.Fatal("locator component is not injected")
+		return errors.New("locator component is not injected")
+	}
+
+	if _, ok := g.opts.locator.(locate.DeviceLocator); !ok && g.opts.loginPolicy == LoginMulti {
+		return locate.ErrDeviceNotSupported
 	}
 
 	if g.opts.registry == nil {
-		log.Fatal("registry component is not injected")
+		return errors.New("registry component is not injected")
 	}
 
 	if g.opts.transporter == nil {
-		log.Fatal("transporter component is not injected")
+		return errors.New("transporter component is not injected")
 	}
+
+	return nil
 }
 
 // Start 启动组件
-func (g *Gate) Start() {
-	g.startNetworkServer()
+func (g *Gate) Start() error {
+	if err := g.startNetworkServer(); err != nil {
+		return err
+	}
 
-	g.startTransportServer()
+	if err := g.startTransportServer(); err != nil {
+		g.stopNetworkServer()
+		return err
+	}
 
-	g.registerServiceInstance()
+	if err := g.registerServiceInstance(cluster.Work); err != nil {
+		g.stopTransportServer()
+		g.stopNetworkServer()
+		return err
+	}
+
+	if err := g.proxy.watch(g.ctx); err != nil {
+		g.deregisterServiceInstance()
+		g.stopTransportServer()
+		g.stopNetworkServer()
+		return err
+	}
 
-	g.proxy.watch(g.ctx)
+	g.started = true
 
 	g.debugPrint()
+
+	return nil
+}
+
+// ShutdownTimeout 组件销毁超时时间，未设置时为排空超时时间加上解注册等收尾操作的预留时间
+func (g *Gate) ShutdownTimeout() time.Duration {
+	if timeout := g.Base.ShutdownTimeout(); timeout > 0 {
+		return timeout
+	}
+
+	return g.opts.drainTimeout + 10*time.Second
+}
+
+// Restart 重启组件
+// 热重启时停止接收新连接，通知已绑定用户重连到当前地址（由新进程接管），并在排空超时时间内等待存量连接断开
+// 新进程可能以相同实例ID注册，因此销毁时不再以挂起状态更新服务实例；新进程实例ID相同时无法凭恢复令牌接管旧进程的会话，用户须重新登录；
+// KCP网络服务器不支持零停机热重启，存量会话在停止接收新连接时即断开
+func (g *Gate) Restart() error {
+	g.restarting = true
+
+	if err := g.opts.server.StopAccept(); err != nil {
+		return err
+	}
+
+	g.redirect([]string{g.exposedAddr()})
+
+	g.drain()
+
+	return nil
 }
 
 // Destroy 销毁组件
-func (g *Gate) Destroy() {
+// 网关先将服务实例更新为挂起状态并停止接收新连接，通知已绑定用户重连到其他工作状态的网关，
+// 然后在排空超时时间内等待存量连接断开，剩余连接在关闭网络服务器时断开；未启动成功的网关仅释放资源
+func (g *Gate) Destroy() error {
+	if !g.started {
+		g.limiter.close()
+		g.proxy.link.Close()
+		g.cancel()
+		return nil
+	}
+
+	if !g.restarting {
+		if err := g.updateServiceInstance(cluster.Hang); err != nil {
+			log.Errorf("the gate service instance hang failed: %v", err)
+		}
+
+		if err := g.opts.server.StopAccept(); err != nil {
+			log.Errorf("the gate server stop accept failed: %v", err)
+		}
+
+		g.redirect(g.fetchRedirectAddrs())
+
+		g.drain()
+	}
+
 	g.deregisterServiceInstance()
 
 	g.stopNetworkServer()
 
+	g.expireResumes()
+
 	g.stopTransportServer()
 
+	g.limiter.close()
+
+	g.proxy.link.Close()
+
 	g.cancel()
+
+	return nil
 }
 
 // 启动网络服务器
-func (g *Gate) startNetworkServer() {
+// 网络服务器的启动方法为阻塞调用，通过启动回调确认监听成功
+func (g *Gate) startNetworkServer() error {
+	chStart := make(chan error, 1)
+
+	g.opts.server.OnStart(func() { chStart <- nil })
 	g.opts.server.OnConnect(g.handleConnect)
 	g.opts.server.OnDisconnect(g.handleDisconnect)
 	g.opts.server.OnReceive(g.handleReceive)
 
 	go func() {
 		if err := g.opts.server.Start(); err != nil {
-			log.Fatalf("the gate server startup failed: %v", err)
+			select {
+			case chStart <- err:
+			default:
+				log.Warnf("the gate server serve exited: %v", err)
+			}
 		}
 	}()
+
+	return <-chStart
 }
 
 // 停止网关服务器
@@ -124,28 +232,135 @@ func (g *Gate) stopNetworkServer() {
 	}
 }
 
+// 通知已绑定用户重连到指定地址，多个地址时依次轮流分配
+// 启用会话恢复时客户端重连后凭恢复令牌在新网关接管会话，用户绑定、鉴权声明及会话属性随之转移
+func (g *Gate) redirect(addrs []string) {
+	if len(addrs) == 0 {
+		return
+	}
+
+	g.markRedirected()
+
+	msgs := make([][]byte, 0, len(addrs))
+	for _, addr := range addrs {
+		msg, err := packet.Pack(&packet.Message{Route: cluster.RedirectRoute, Buffer: []byte(addr)})
+		if err != nil {
+			log.Errorf("pack redirect message failed: %v", err)
+			return
+		}
+		msgs = append(msgs, msg)
+	}
+
+	i := 0
+	_ = g.group.Range(session.User, func(s *session.Session) bool {
+		if err := s.TryPush(msgs[i%len(msgs)]); err != nil {
+			log.Warnf("push redirect message failed, uid: %d, err: %v", s.UID(), err)
+		}
+		i++
+		return true
+	})
+}
+
+// 拉取其他工作状态网关的客户端连接地址
+func (g *Gate) fetchRedirectAddrs() []string {
+	ctx, cancel := context.WithTimeout(g.ctx, g.opts.timeout)
+	defer cancel()
+
+	services, err := g.proxy.link.FetchServiceList(ctx, cluster.Gate, cluster.Work)
+	if err != nil {
+		log.Errorf("fetch gate list failed: %v", err)
+		return nil
+	}
+
+	addrs := make([]string, 0, len(services))
+	for _, service := range services {
+		if service.ID != g.opts.id && service.Address != "" {
+			addrs = append(addrs, service.Address)
+		}
+	}
+
+	return addrs
+}
+
+// 获取对外暴露的客户端连接地址
+func (g *Gate) exposedAddr() string {
+	if g.opts.addr != "" {
+		return g.opts.addr
+	}
+
+	host, port, err := net.SplitHostPort(g.opts.server.Addr())
+	if err != nil {
+		return g.opts.server.Addr()
+	}
+
+	if host == "" || host == "0.0.0.0" || host == "[::]" || host == "::" {
+		if ip, err := xnet.InternalIP(); err == nil {
+			host = ip
+		}
+	}
+
+	return net.JoinHostPort(host, port)
+}
+
+// 等待存量连接断开
+func (g *Gate) drain() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	timer := time.NewTimer(g.opts.drainTimeout)
+	defer timer.Stop()
+
+	for {
+		if n, _ := g.group.Count(session.Conn); n == 0 {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			n, _ := g.group.Count(session.Conn)
+			log.Warnf("the gate drain timeout after %v, %d connections remaining", g.opts.drainTimeout, n)
+			return
+		}
+	}
+}
+
 // 处理连接打开
 func (g *Gate) handleConnect(conn network.Conn) {
 	s := g.sessions.Get().(*session.Session)
 	s.Init(conn)
 	g.group.AddSession(s)
+
+	if g.authEnabled() {
+		g.awaitAuth(conn)
+	}
 }
 
 // 处理断开连接
+// 启用会话恢复时，已下发恢复令牌的用户挂起会话等待恢复，恢复窗口过期前不解绑用户与网关间的关系
 func (g *Gate) handleDisconnect(conn network.Conn) {
-	s, err := g.group.RemSession(session.Conn, conn.ID())
+	g.releaseAuth(conn.ID())
+
+	g.limiter.release(conn.ID(), conn.UID())
+
+	s, suspended, err := g.detach(conn)
 	if err != nil {
-		log.Errorf("session remove failed, gid: %d, cid: %d, uid: %d, err: %v", g.opts.id, s.CID(), s.UID(), err)
+		// 已重定向的会话在新网关接管时已被移除
+		if err != session.ErrNotFoundSession {
+			log.Errorf("session remove failed, gid: %s, cid: %d, uid: %d, err: %v", g.opts.id, conn.ID(), conn.UID(), err)
+		}
 		return
 	}
 
-	if uid := conn.UID(); uid > 0 {
+	if uid := conn.UID(); uid > 0 && !suspended {
 		ctx, cancel := context.WithTimeout(g.ctx, g.opts.timeout)
-		err = g.proxy.unbindGate(ctx, uid)
+		err = g.logout(ctx, uid, conn.ID())
 		cancel()
 		if err != nil {
 			log.Errorf("user unbind failed, gid: %d, uid: %d, err: %v", g.opts.id, uid, err)
 		}
+
+		g.quitChannels(uid)
 	}
 
 	s.Reset()
@@ -153,6 +368,8 @@ func (g *Gate) handleDisconnect(conn network.Conn) {
 }
 
 // 处理接收到的消息
+// 超过限流的消息按超限处理策略处理，会话恢复及网关本地路由的消息直接在网关内处理；
+// 启用鉴权时，连接绑定用户前的其他消息交由鉴权流程处理
 func (g *Gate) handleReceive(conn network.Conn, data []byte, _ int) {
 	message, err := packet.Unpack(data)
 	if err != nil {
@@ -160,28 +377,93 @@ func (g *Gate) handleReceive(conn network.Conn, data []byte, _ int) {
 		return
 	}
 
+	switch g.limiter.check(conn.ID(), conn.UID(), message.Route) {
+	case limitPass:
+	case limitReply:
+		g.replyError(conn, message, code.RateLimited)
+		return
+	case limitDisconnect:
+		log.Warnf("the connection sends messages too fast, cid: %d, uid: %d", conn.ID(), conn.UID())
+		_ = conn.Close(true)
+		return
+	default:
+		return
+	}
+
+	if message.Route == cluster.ResumeRoute {
+		g.resume(conn, message)
+		return
+	}
+
+	if g.handleLocal(conn, message) {
+		return
+	}
+
+	if g.authEnabled() && conn.UID() == 0 {
+		g.authenticate(conn, message)
+		return
+	}
+
+	g.deliver(conn, message)
+}
+
+// 投递消息到节点，会话属性和鉴权声明随消息一同投递，投递失败时向客户端回复错误
+func (g *Gate) deliver(conn network.Conn, message *packet.Message) {
+	var attrs, claims map[string]string
+	if s, err := g.group.GetSession(session.Conn, conn.ID()); err == nil {
+		attrs, claims = s.Attrs(), s.Claims()
+	}
+
 	ctx, cancel := context.WithTimeout(g.ctx, g.opts.timeout)
-	err = g.proxy.deliver(ctx, conn.ID(), conn.UID(), message)
+	err := g.proxy.deliver(ctx, conn.ID(), conn.UID(), attrs, claims, message)
 	cancel()
 	if err != nil {
 		log.Errorf("deliver message failed: %v", err)
+		g.replyError(conn, message, deliverErrorCode(err))
 	}
 }
 
-// 启动RPC服务器
-func (g *Gate) startTransportServer() {
-	var err error
+// 向客户端回复错误，消息序列号为原请求的序列号
+func (g *Gate) replyError(conn network.Conn, message *packet.Message, c code.Code) {
+	reply := &cluster.ErrorReply{
+		Route:   message.Route,
+		Code:    int32(c.Code()),
+		Message: c.Message(),
+	}
+
+	msg, err := packet.Pack(&packet.Message{Seq: message.Seq, Route: cluster.ErrorRoute, Buffer: reply.Marshal()})
+	if err != nil {
+		log.Errorf("pack error reply failed: %v", err)
+		return
+	}
 
+	if err = conn.Push(msg); err != nil {
+		log.Warnf("push error reply failed, cid: %d, err: %v", conn.ID(), err)
+	}
+}
+
+// 投递失败的错误码，传输层将RPC调用超时的错误转换为可通过errors.Is判断的context.DeadlineExceeded
+func deliverErrorCode(err error) code.Code {
+	switch {
+	case err == router.ErrNotFoundRoute:
+		return code.RouteNotFound
+	case err == router.ErrNotFoundEndpoint, err == ErrNotFoundUserSource:
+		return code.ServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return code.Timeout
+	default:
+		return code.InternalError
+	}
+}
+
+// 启动RPC服务器
+func (g *Gate) startTransportServer() (err error) {
 	g.rpc, err = g.opts.transporter.NewGateServer(&provider{g})
 	if err != nil {
-		log.Fatalf("the transport server build failed: %v", err)
+		return
 	}
 
-	go func() {
-		if err = g.rpc.Start(); err != nil {
-			log.Fatalf("the transport server startup failed: %v", err)
-		}
-	}()
+	return g.rpc.Start()
 }
 
 // 停止RPC服务器
@@ -192,21 +474,36 @@ func (g *Gate) stopTransportServer() {
 }
 
 // 注册服务实例
-func (g *Gate) registerServiceInstance() {
-	g.instance = &registry.ServiceInstance{
+func (g *Gate) registerServiceInstance(state cluster.State) error {
+	g.instance = g.buildServiceInstance(state)
+
+	ctx, cancel := context.WithTimeout(g.ctx, 10*time.Second)
+	defer cancel()
+
+	return g.opts.registry.Register(ctx, g.instance)
+}
+
+// 更新服务实例
+func (g *Gate) updateServiceInstance(state cluster.State) error {
+	g.instance = g.buildServiceInstance(state)
+
+	ctx, cancel := context.WithTimeout(g.ctx, 10*time.Second)
+	defer cancel()
+
+	return g.opts.registry.Update(ctx, g.instance)
+}
+
+// 构建服务实例
+func (g *Gate) buildServiceInstance(state cluster.State) *registry.ServiceInstance {
+	return &registry.ServiceInstance{
 		ID:       g.opts.id,
 		Name:     string(cluster.Gate),
 		Kind:     cluster.Gate,
 		Alias:    g.opts.name,
-		State:    cluster.Work,
+		Zone:     g.opts.zone,
+		State:    state,
 		Endpoint: g.rpc.Endpoint().String(),
-	}
-
-	ctx, cancel := context.WithTimeout(g.ctx, 10*time.Second)
-	err := g.opts.registry.Register(ctx, g.instance)
-	cancel()
-	if err != nil {
-		log.Fatalf("the gate service instance register failed: %v", err)
+		Address:  g.exposedAddr(),
 	}
 }
 
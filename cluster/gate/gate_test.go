@@ -0,0 +1,505 @@
+package gate
+
+import (
+	"context"
+	"fmt"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/code"
+	"github.com/dobyte/due/errors"
+	"github.com/dobyte/due/internal/endpoint"
+	"github.com/dobyte/due/locate"
+	"github.com/dobyte/due/network"
+	"github.com/dobyte/due/packet"
+	"github.com/dobyte/due/registry"
+	"github.com/dobyte/due/router"
+	"github.com/dobyte/due/session"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 内存定位器
+type memLocator struct {
+	mu        sync.Mutex
+	locations map[string]string
+	devices   map[int64][]string
+}
+
+func newMemLocator() *memLocator {
+	return &memLocator{locations: make(map[string]string), devices: make(map[int64][]string)}
+}
+
+func locationKey(uid int64, kind cluster.Kind, group string) string {
+	return fmt.Sprintf("%s:%s:%d", kind, group, uid)
+}
+
+func (l *memLocator) Get(ctx context.Context, uid int64, kind cluster.Kind, group string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.locations[locationKey(uid, kind, group)], nil
+}
+
+func (l *memLocator) Set(ctx context.Context, uid int64, kind cluster.Kind, group string, insID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.locations[locationKey(uid, kind, group)] = insID
+
+	return nil
+}
+
+func (l *memLocator) Rem(ctx context.Context, uid int64, kind cluster.Kind, group string, insID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := locationKey(uid, kind, group)
+	if l.locations[key] == insID {
+		delete(l.locations, key)
+	}
+
+	return nil
+}
+
+func (l *memLocator) Watch(ctx context.Context, kinds ...cluster.Kind) (locate.Watcher, error) {
+	return nil, nil
+}
+
+func (l *memLocator) AddDevice(ctx context.Context, uid int64, device string, limit int) ([]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	devices := append(l.devices[uid], device)
+	if len(devices) <= limit {
+		l.devices[uid] = devices
+		return nil, nil
+	}
+
+	l.devices[uid] = devices[len(devices)-limit:]
+
+	return devices[:len(devices)-limit], nil
+}
+
+func (l *memLocator) RemDevice(ctx context.Context, uid int64, device string) ([]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	remains := make([]string, 0, len(l.devices[uid]))
+	for _, d := range l.devices[uid] {
+		if d != device {
+			remains = append(remains, d)
+		}
+	}
+	l.devices[uid] = remains
+
+	return remains, nil
+}
+
+// 测试连接，推送的消息写入通道
+type testConn struct {
+	id     int64
+	uid    int64
+	closed int32
+	pushes chan []byte
+}
+
+func newTestConn(id int64) *testConn {
+	return &testConn{id: id, pushes: make(chan []byte, 64)}
+}
+
+func (c *testConn) ID() int64 { return c.id }
+
+func (c *testConn) UID() int64 { return atomic.LoadInt64(&c.uid) }
+
+func (c *testConn) Bind(uid int64) { atomic.StoreInt64(&c.uid, uid) }
+
+func (c *testConn) Unbind() { atomic.StoreInt64(&c.uid, 0) }
+
+func (c *testConn) Send(msg []byte, msgType ...int) error { return c.Push(msg, msgType...) }
+
+func (c *testConn) Push(msg []byte, msgType ...int) error {
+	if c.isClosed() {
+		return network.ErrConnectionClosed
+	}
+
+	c.pushes <- msg
+
+	return nil
+}
+
+func (c *testConn) State() network.ConnState {
+	if c.isClosed() {
+		return network.ConnClosed
+	}
+
+	return network.ConnOpened
+}
+
+func (c *testConn) Close(isForce ...bool) error {
+	atomic.StoreInt32(&c.closed, 1)
+	return nil
+}
+
+func (c *testConn) isClosed() bool { return atomic.LoadInt32(&c.closed) == 1 }
+
+func (c *testConn) LocalIP() (string, error) { return "127.0.0.1", nil }
+
+func (c *testConn) LocalAddr() (net.Addr, error) {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}, nil
+}
+
+func (c *testConn) RemoteIP() (string, error) { return "127.0.0.1", nil }
+
+func (c *testConn) RemoteAddr() (net.Addr, error) {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}, nil
+}
+
+// 取出推送给连接的消息，超时未收到消息时返回nil
+func (c *testConn) pop(timeout time.Duration) *packet.Message {
+	select {
+	case msg := <-c.pushes:
+		message, err := packet.Unpack(msg)
+		if err != nil {
+			return nil
+		}
+		return message
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+// 取出推送给连接的指定路由的消息，跳过其他路由的消息
+func (c *testConn) popRoute(route int32, timeout time.Duration) *packet.Message {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		message := c.pop(time.Until(deadline))
+		if message == nil || message.Route == route {
+			return message
+		}
+	}
+}
+
+// 记录服务实例变更的内存注册器
+type memRegistry struct {
+	mu           sync.Mutex
+	registers    int
+	updates      []cluster.State
+	deregistered bool
+	services     []*registry.ServiceInstance
+}
+
+func (r *memRegistry) Register(ctx context.Context, ins *registry.ServiceInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.registers++
+
+	return nil
+}
+
+func (r *memRegistry) Deregister(ctx context.Context, ins *registry.ServiceInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deregistered = true
+
+	return nil
+}
+
+func (r *memRegistry) Update(ctx context.Context, ins *registry.ServiceInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.updates = append(r.updates, ins.State)
+
+	return nil
+}
+
+func (r *memRegistry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	return nil, nil
+}
+
+func (r *memRegistry) Services(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	return r.services, nil
+}
+
+// 测试网络服务器
+type testServer struct {
+	stopAccepted int32
+	stopped      int32
+}
+
+func (s *testServer) Addr() string { return "127.0.0.1:3553" }
+
+func (s *testServer) Start() error { return nil }
+
+func (s *testServer) Stop() error {
+	atomic.StoreInt32(&s.stopped, 1)
+	return nil
+}
+
+func (s *testServer) StopAccept() error {
+	atomic.StoreInt32(&s.stopAccepted, 1)
+	return nil
+}
+
+func (s *testServer) Protocol() string { return "tcp" }
+
+func (s *testServer) OnStart(handler network.StartHandler) {}
+
+func (s *testServer) OnStop(handler network.CloseHandler) {}
+
+func (s *testServer) OnConnect(handler network.ConnectHandler) {}
+
+func (s *testServer) OnReceive(handler network.ReceiveHandler) {}
+
+func (s *testServer) OnDisconnect(handler network.DisconnectHandler) {}
+
+// 空传输服务器
+type testTransportServer struct{}
+
+func (testTransportServer) Addr() string { return "127.0.0.1:0" }
+
+func (testTransportServer) Scheme() string { return "test" }
+
+func (testTransportServer) Endpoint() *endpoint.Endpoint {
+	return endpoint.NewEndpoint("test", "127.0.0.1:0", false)
+}
+
+func (testTransportServer) Start() error { return nil }
+
+func (testTransportServer) Stop() error { return nil }
+
+func newTestGate(opts ...Option) *Gate {
+	return NewGate(append([]Option{WithID("gate-1"), WithLocator(newMemLocator()), WithTimeout(time.Second)}, opts...)...)
+}
+
+// 模拟客户端发送消息
+func receive(t *testing.T, g *Gate, conn network.Conn, route int32) {
+	data, err := packet.Pack(&packet.Message{Seq: 1, Route: route})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.handleReceive(conn, data, 0)
+}
+
+// 超时错误，模拟传输层转换后的错误
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "rpc error: code = DeadlineExceeded" }
+
+func (timeoutError) Is(target error) bool { return target == context.DeadlineExceeded }
+
+func TestDeliverErrorCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		code code.Code
+	}{
+		{router.ErrNotFoundRoute, code.RouteNotFound},
+		{router.ErrNotFoundEndpoint, code.ServiceUnavailable},
+		{ErrNotFoundUserSource, code.ServiceUnavailable},
+		{context.DeadlineExceeded, code.Timeout},
+		{timeoutError{}, code.Timeout},
+		{fmt.Errorf("deliver failed: %w", timeoutError{}), code.Timeout},
+		{errors.New("context deadline exceeded"), code.InternalError},
+	}
+
+	for _, c := range cases {
+		if actual := deliverErrorCode(c.err); actual != c.code {
+			t.Fatalf("unexpected code of %v: %v != %v", c.err, actual, c.code)
+		}
+	}
+}
+
+func TestGate_DestroyBeforeStart(t *testing.T) {
+	if err := newTestGate().Destroy(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGate_Drain(t *testing.T) {
+	g := newTestGate(WithDrainTimeout(2 * time.Second))
+	conn := newTestConn(1)
+	g.handleConnect(conn)
+
+	done := make(chan struct{})
+	go func() {
+		g.drain()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("the drain should wait for the connections to close")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	g.handleDisconnect(conn)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the drain should finish after the connections close")
+	}
+}
+
+func TestGate_DrainTimeout(t *testing.T) {
+	g := newTestGate(WithDrainTimeout(200 * time.Millisecond))
+	conn := newTestConn(1)
+	g.handleConnect(conn)
+	defer g.handleDisconnect(conn)
+
+	start := time.Now()
+	g.drain()
+
+	if time.Since(start) > time.Second {
+		t.Fatal("the drain should give up after the drain timeout")
+	}
+}
+
+func TestGate_Destroy(t *testing.T) {
+	reg := &memRegistry{services: []*registry.ServiceInstance{
+		{ID: "gate-1", Kind: cluster.Gate, State: cluster.Work, Address: "127.0.0.1:3553"},
+		{ID: "gate-2", Kind: cluster.Gate, State: cluster.Work, Address: "127.0.0.1:3554"},
+	}}
+	server := &testServer{}
+	g := newTestGate(WithRegistry(reg), WithServer(server), WithAuthenticator(testAuthenticator), WithDrainTimeout(2*time.Second))
+	g.rpc = testTransportServer{}
+	g.instance = g.buildServiceInstance(cluster.Work)
+	g.started = true
+
+	conn := newTestConn(1)
+	g.handleConnect(conn)
+	receive(t, g, conn, testLoginRoute)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Destroy()
+	}()
+
+	message := conn.popRoute(cluster.RedirectRoute, time.Second)
+	if message == nil || string(message.Buffer) != "127.0.0.1:3554" {
+		t.Fatalf("the user should be redirected to the other working gate, got %v", message)
+	}
+
+	select {
+	case <-done:
+		t.Fatal("the gate should wait for the connections to close")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	reg.mu.Lock()
+	updates, registers, deregistered := reg.updates, reg.registers, reg.deregistered
+	reg.mu.Unlock()
+
+	if len(updates) != 1 || updates[0] != cluster.Hang || registers != 0 {
+		t.Fatalf("the gate should hang by updating the instance, updates: %v, registers: %d", updates, registers)
+	}
+
+	if deregistered || atomic.LoadInt32(&server.stopAccepted) != 1 {
+		t.Fatal("the gate should stop accepting and keep registered while draining")
+	}
+
+	g.handleDisconnect(conn)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the gate should be destroyed after the connections close")
+	}
+
+	reg.mu.Lock()
+	deregistered = reg.deregistered
+	reg.mu.Unlock()
+
+	if !deregistered || atomic.LoadInt32(&server.stopped) != 1 {
+		t.Fatal("the gate should deregister and stop the server after draining")
+	}
+}
+
+func TestGate_RedirectResume(t *testing.T) {
+	locator := newMemLocator()
+	g := newTestGate(WithLocator(locator), WithAuthenticator(testAuthenticator), WithResumeWindow(time.Minute))
+
+	old := newTestConn(1)
+	g.handleConnect(old)
+	receive(t, g, old, testLoginRoute)
+
+	token := old.popRoute(cluster.ResumeRoute, time.Second)
+	if token == nil {
+		t.Fatal("the resume token should be issued after login")
+	}
+
+	s, err := g.group.GetSession(session.User, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Set("level", "10")
+
+	resume := func(conn *testConn) {
+		data, err := packet.Pack(&packet.Message{Seq: 1, Route: cluster.ResumeRoute, Buffer: token.Buffer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.handleReceive(conn, data, 0)
+	}
+
+	// 未重定向时在线会话不可被接管
+	conn := newTestConn(2)
+	g.handleConnect(conn)
+	resume(conn)
+
+	if conn.UID() != 0 || old.isClosed() {
+		t.Fatal("the online session should not be taken over before redirecting")
+	}
+	g.handleDisconnect(conn)
+
+	g.redirect([]string{"127.0.0.1:3554"})
+
+	if message := old.popRoute(cluster.RedirectRoute, time.Second); message == nil {
+		t.Fatal("the user should be redirected")
+	}
+
+	// 客户端先连接新地址并恢复会话，再断开旧连接
+	conn = newTestConn(3)
+	g.handleConnect(conn)
+	resume(conn)
+
+	if conn.UID() != 1 {
+		t.Fatal("the user should be bound to the new connection")
+	}
+
+	if !old.isClosed() {
+		t.Fatal("the redirected connection should be closed")
+	}
+
+	s, err = g.group.GetSession(session.Conn, conn.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Claims()["role"] != "admin" {
+		t.Fatalf("the claims should be carried across the redirect, got %v", s.Claims())
+	}
+
+	if level, _ := s.Get("level"); level != "10" {
+		t.Fatalf("the attrs should be carried across the redirect, got %q", level)
+	}
+
+	g.handleDisconnect(old)
+
+	if gid, _ := locator.Get(context.Background(), 1, cluster.Gate, ""); gid != "gate-1" {
+		t.Fatalf("the old connection closing should not unbind the user, got %q", gid)
+	}
+
+	if n, _ := g.group.Count(session.Conn); n != 1 {
+		t.Fatalf("expected 1 connection, got %d", n)
+	}
+}
@@ -0,0 +1,232 @@
+package gate
+
+import (
+	"github.com/dobyte/due/config"
+	"github.com/dobyte/due/log"
+	"github.com/dobyte/due/utils/xrate"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultLimitKey = "config.network.limit" // 消息限流配置
+
+// LimitPolicy 消息超限处理策略
+type LimitPolicy string
+
+const (
+	LimitDrop       LimitPolicy = "drop"       // 丢弃消息
+	LimitReply      LimitPolicy = "reply"      // 丢弃消息并回复过快错误
+	LimitDisconnect LimitPolicy = "disconnect" // 丢弃消息，累计超限次数达到阈值后断开连接
+)
+
+const (
+	defaultLimitViolations = 10 // 默认断开连接的累计超限次数
+	defaultLimitWindow     = 60 // 默认超限次数的统计窗口（秒）
+)
+
+// 限流处理结果
+type limitAction int
+
+const (
+	limitPass       limitAction = iota // 放行
+	limitDrop                          // 丢弃
+	limitReply                         // 丢弃并回复
+	limitDisconnect                    // 断开连接
+)
+
+// 令牌桶配置
+type limitRule struct {
+	Rate  float64 `json:"rate"`  // 每秒消息数，不大于0时不限制
+	Burst int     `json:"burst"` // 突发消息数，不大于0时为rate向上取整
+}
+
+// 限流配置
+type limitSettings struct {
+	Policy     LimitPolicy         `json:"policy"`     // 超限处理策略
+	Violations int                 `json:"violations"` // 断开连接的累计超限次数
+	Window     int                 `json:"window"`     // 超限次数的统计窗口（秒），窗口内未达到阈值时重新统计
+	Conn       limitRule           `json:"conn"`       // 单个连接的限流
+	User       limitRule           `json:"user"`       // 单个用户的限流
+	Routes     map[int32]limitRule `json:"routes"`     // 单个连接每个路由的限流
+}
+
+// 连接的令牌桶
+type connLimit struct {
+	mu         sync.Mutex
+	settings   *limitSettings
+	bucket     *xrate.Bucket
+	routes     map[int32]*xrate.Bucket
+	violations int       // 统计窗口内的超限次数
+	windowAt   time.Time // 统计窗口的开始时间
+}
+
+// 用户的令牌桶
+type userLimit struct {
+	settings *limitSettings
+	bucket   *xrate.Bucket
+}
+
+// 消息限流器，配置变化时重新加载，已创建的令牌桶在下次使用时按新配置重建
+type limiter struct {
+	value  atomic.Value // *limitSettings
+	conns  sync.Map     // 连接ID -> *connLimit
+	users  sync.Map     // 用户ID -> *userLimit
+	cancel func()       // 取消监听配置变化
+}
+
+func newLimiter() *limiter {
+	l := &limiter{}
+	l.load()
+	l.cancel = config.OnChange(l.load)
+
+	return l
+}
+
+// 关闭限流器，取消监听配置变化
+func (l *limiter) close() {
+	l.cancel()
+}
+
+// 加载配置
+func (l *limiter) load() {
+	settings := &limitSettings{}
+	if err := config.Get(defaultLimitKey).Scan(settings); err != nil {
+		log.Warnf("load network limit failed: %v", err)
+		return
+	}
+
+	switch settings.Policy {
+	case LimitDrop, LimitReply, LimitDisconnect:
+	default:
+		settings.Policy = LimitDrop
+	}
+
+	if settings.Violations <= 0 {
+		settings.Violations = defaultLimitViolations
+	}
+
+	if settings.Window <= 0 {
+		settings.Window = defaultLimitWindow
+	}
+
+	if !settings.enabled() {
+		settings = nil
+	}
+
+	l.value.Store(settings)
+}
+
+// 是否配置了限流
+func (s *limitSettings) enabled() bool {
+	if s.Conn.Rate > 0 || s.User.Rate > 0 {
+		return true
+	}
+
+	for _, rule := range s.Routes {
+		if rule.Rate > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 检测消息是否超限
+func (l *limiter) check(cid, uid int64, route int32) limitAction {
+	settings, _ := l.value.Load().(*limitSettings)
+	if settings == nil {
+		return limitPass
+	}
+
+	v, _ := l.conns.LoadOrStore(cid, &connLimit{})
+	cl := v.(*connLimit)
+
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if cl.settings != settings {
+		cl.settings = settings
+		cl.bucket = xrate.NewBucket(settings.Conn.Rate, settings.Conn.Burst)
+		cl.routes = make(map[int32]*xrate.Bucket, len(settings.Routes))
+		cl.violations = 0
+	}
+
+	if cl.allow(route) && l.allowUser(settings, uid) {
+		return limitPass
+	}
+
+	cl.violate(time.Duration(settings.Window) * time.Second)
+
+	switch settings.Policy {
+	case LimitReply:
+		return limitReply
+	case LimitDisconnect:
+		if cl.violations >= settings.Violations {
+			return limitDisconnect
+		}
+		return limitDrop
+	default:
+		return limitDrop
+	}
+}
+
+// 释放连接和用户的令牌桶
+func (l *limiter) release(cid, uid int64) {
+	l.conns.Delete(cid)
+
+	l.releaseUser(uid)
+}
+
+// 释放用户的令牌桶，用于用户解绑时
+func (l *limiter) releaseUser(uid int64) {
+	if uid > 0 {
+		l.users.Delete(uid)
+	}
+}
+
+// 检测用户是否超限，未绑定用户时不限制
+func (l *limiter) allowUser(settings *limitSettings, uid int64) bool {
+	if uid <= 0 || settings.User.Rate <= 0 {
+		return true
+	}
+
+	if v, ok := l.users.Load(uid); ok && v.(*userLimit).settings == settings {
+		return v.(*userLimit).bucket.Allow()
+	}
+
+	ul := &userLimit{settings: settings, bucket: xrate.NewBucket(settings.User.Rate, settings.User.Burst)}
+	l.users.Store(uid, ul)
+
+	return ul.bucket.Allow()
+}
+
+// 记录一次超限，超过统计窗口时重新统计
+func (cl *connLimit) violate(window time.Duration) {
+	if now := time.Now(); now.Sub(cl.windowAt) > window {
+		cl.violations = 0
+		cl.windowAt = now
+	}
+
+	cl.violations++
+}
+
+// 检测连接及路由是否超限
+func (cl *connLimit) allow(route int32) bool {
+	if !cl.bucket.Allow() {
+		return false
+	}
+
+	rule, ok := cl.settings.Routes[route]
+	if !ok || rule.Rate <= 0 {
+		return true
+	}
+
+	bucket, ok := cl.routes[route]
+	if !ok {
+		bucket = xrate.NewBucket(rule.Rate, rule.Burst)
+		cl.routes[route] = bucket
+	}
+
+	return bucket.Allow()
+}
@@ -0,0 +1,97 @@
+package gate
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(settings *limitSettings) *limiter {
+	l := &limiter{}
+	l.value.Store(settings)
+
+	return l
+}
+
+func TestLimiter_Check(t *testing.T) {
+	l := newTestLimiter(&limitSettings{
+		Policy: LimitReply,
+		Conn:   limitRule{Rate: 0.001, Burst: 3},
+		Routes: map[int32]limitRule{2: {Rate: 0.001, Burst: 1}},
+	})
+
+	if l.check(1, 0, 2) != limitPass {
+		t.Fatal("the first message of route 2 should pass")
+	}
+
+	if l.check(1, 0, 2) != limitReply {
+		t.Fatal("the route limit should be exceeded")
+	}
+
+	if l.check(1, 0, 1) != limitPass {
+		t.Fatal("the message of route 1 should pass")
+	}
+
+	if l.check(1, 0, 1) != limitReply {
+		t.Fatal("the connection limit should be exceeded")
+	}
+
+	if l.check(2, 0, 1) != limitPass {
+		t.Fatal("the limit of other connections should not be affected")
+	}
+}
+
+func TestLimiter_ViolationsWindow(t *testing.T) {
+	l := newTestLimiter(&limitSettings{
+		Policy:     LimitDisconnect,
+		Violations: 3,
+		Window:     defaultLimitWindow,
+		Conn:       limitRule{Rate: 0.001, Burst: 1},
+	})
+
+	l.check(1, 0, 1)
+
+	for i := 0; i < 2; i++ {
+		if l.check(1, 0, 1) != limitDrop {
+			t.Fatalf("violation %d should be dropped", i+1)
+		}
+	}
+
+	// 统计窗口过期后重新统计
+	v, _ := l.conns.Load(int64(1))
+	v.(*connLimit).windowAt = time.Now().Add(-2 * defaultLimitWindow * time.Second)
+
+	for i := 0; i < 2; i++ {
+		if l.check(1, 0, 1) != limitDrop {
+			t.Fatalf("violation %d in the new window should be dropped", i+1)
+		}
+	}
+
+	if l.check(1, 0, 1) != limitDisconnect {
+		t.Fatal("the connection should be disconnected when the violations reach the threshold in the window")
+	}
+}
+
+func TestLimiter_ReleaseUser(t *testing.T) {
+	g := newTestGate(WithAuthenticator(testAuthenticator))
+	g.limiter = newTestLimiter(&limitSettings{Policy: LimitDrop, User: limitRule{Rate: 100}})
+
+	conn := newTestConn(1)
+	g.handleConnect(conn)
+	defer g.handleDisconnect(conn)
+
+	receive(t, g, conn, testLoginRoute)
+	receive(t, g, conn, 2)
+
+	if _, ok := g.limiter.users.Load(int64(1)); !ok {
+		t.Fatal("the user bucket should be created")
+	}
+
+	if err := (&provider{gate: g}).Unbind(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := g.limiter.users.Load(int64(1)); ok {
+		t.Fatal("the user bucket should be released after unbind")
+	}
+}
@@ -0,0 +1,210 @@
+package gate
+
+import (
+	"context"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/code"
+	"github.com/dobyte/due/errors"
+	"github.com/dobyte/due/log"
+	"github.com/dobyte/due/packet"
+	"github.com/dobyte/due/session"
+)
+
+var ErrDuplicateLogin = errors.New("duplicate login")
+
+// LoginPolicy 重复登录策略
+type LoginPolicy string
+
+const (
+	LoginKick   LoginPolicy = "kick"   // 踢掉已登录的会话
+	LoginReject LoginPolicy = "reject" // 拒绝新的登录
+	LoginMulti  LoginPolicy = "multi"  // 允许多端同时连接，设备数超过上限时踢掉最早登录的设备；按用户推送的消息仅送达最近登录的设备
+)
+
+// 登录设备
+type device struct {
+	GID string `json:"gid"` // 网关ID
+	CID int64  `json:"cid"` // 连接ID
+}
+
+// 绑定用户与网关间的关系，绑定前按重复登录策略处理该用户在集群中已登录的会话
+// 多端登录仅限制用户同时在线的连接数，用户定位指向最近登录的设备所在网关，节点按用户推送的消息仅送达该设备，
+// 其他设备须按连接推送；多端登录要求定位器实现locate.DeviceLocator
+func (g *Gate) login(ctx context.Context, s *session.Session, uid int64) error {
+	var err error
+
+	switch g.opts.loginPolicy {
+	case LoginReject:
+		err = g.rejectLogin(ctx, s, uid)
+	case LoginMulti:
+		err = g.addDevice(ctx, s, uid)
+	default:
+		g.kickLogin(ctx, s, uid)
+	}
+	if err != nil {
+		return err
+	}
+
+	return g.proxy.bindGate(ctx, uid)
+}
+
+// 踢掉用户已登录的会话
+func (g *Gate) kickLogin(ctx context.Context, s *session.Session, uid int64) {
+	reason := int32(code.DuplicateLogin.Code())
+
+	if old, err := g.group.GetSession(session.User, uid); err != nil || old != s {
+		_ = g.kick(session.User, uid, reason)
+	}
+
+	gid, err := g.proxy.locateGate(ctx, uid)
+	if err != nil {
+		log.Warnf("locate user gate failed, uid: %d, err: %v", uid, err)
+		return
+	}
+
+	if gid == "" || gid == g.opts.id {
+		return
+	}
+
+	if err = g.proxy.kick(ctx, gid, session.User, uid, reason); err != nil {
+		log.Warnf("kick user failed, gid: %s, uid: %d, err: %v", gid, uid, err)
+	}
+}
+
+// 用户已有会话在线时拒绝登录，其他网关上断线等待恢复的会话不视为在线，登录时将其丢弃
+func (g *Gate) rejectLogin(ctx context.Context, s *session.Session, uid int64) error {
+	if old, err := g.group.GetSession(session.User, uid); err == nil && old != s {
+		return ErrDuplicateLogin
+	}
+
+	gid, err := g.proxy.locateGate(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	if gid == "" || gid == g.opts.id {
+		return nil
+	}
+
+	if g.proxy.online(ctx, gid, uid) {
+		return ErrDuplicateLogin
+	}
+
+	_ = g.proxy.kick(ctx, gid, session.User, uid, int32(code.DuplicateLogin.Code()))
+
+	return nil
+}
+
+// 添加登录设备，设备数超过上限时踢掉最早登录的设备
+func (g *Gate) addDevice(ctx context.Context, s *session.Session, uid int64) error {
+	evicted, err := g.proxy.addDevice(ctx, uid, device{GID: g.opts.id, CID: s.CID()}, g.opts.loginDevices)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range evicted {
+		g.kickDevice(ctx, d)
+	}
+
+	return nil
+}
+
+// 踢下线登录设备
+func (g *Gate) kickDevice(ctx context.Context, d device) {
+	var (
+		err    error
+		reason = int32(code.DuplicateLogin.Code())
+	)
+
+	if d.GID == g.opts.id {
+		err = g.kick(session.Conn, d.CID, reason)
+	} else {
+		err = g.proxy.kick(ctx, d.GID, session.Conn, d.CID, reason)
+	}
+	if err != nil {
+		log.Warnf("kick login device failed, gid: %s, cid: %d, err: %v", d.GID, d.CID, err)
+	}
+}
+
+// 用户会话下线，解绑用户与网关间的关系并触发断开连接事件
+// 多端登录时仍有其他设备在线则不解绑，用户定位转移到最近登录的设备所在网关
+func (g *Gate) logout(ctx context.Context, uid, cid int64) error {
+	if g.opts.loginPolicy != LoginMulti {
+		return g.proxy.unbindGate(ctx, uid)
+	}
+
+	list, err := g.proxy.remDevice(ctx, uid, device{GID: g.opts.id, CID: cid})
+	if err != nil {
+		return err
+	}
+
+	if len(list) == 0 {
+		return g.proxy.unbindGate(ctx, uid)
+	}
+
+	latest := list[len(list)-1]
+	if latest.GID == g.opts.id {
+		if s, err := g.group.GetSession(session.Conn, latest.CID); err == nil {
+			s.Bind(uid)
+		}
+	}
+
+	gid, err := g.proxy.locateGate(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	if gid == g.opts.id && latest.GID != g.opts.id {
+		return g.proxy.transferGate(ctx, uid, latest.GID)
+	}
+
+	return nil
+}
+
+// 踢下线，向客户端推送踢下线通知后关闭连接
+// 会话在关闭连接前解绑用户，连接断开时不再解绑用户与网关间的关系，也不会触发断开连接事件；
+// 断线等待恢复的用户会话直接丢弃
+func (g *Gate) kick(kind session.Kind, target int64, reason int32) error {
+	if kind == session.User && g.dropSuspended(target) {
+		return nil
+	}
+
+	s, err := g.group.GetSession(kind, target)
+	if err != nil {
+		return err
+	}
+
+	reply := &cluster.ErrorReply{
+		Route:   cluster.KickRoute,
+		Code:    reason,
+		Message: "kicked",
+	}
+
+	msg, err := packet.Pack(&packet.Message{Route: cluster.ErrorRoute, Buffer: reply.Marshal()})
+	if err != nil {
+		return err
+	}
+
+	if err = s.Push(msg); err != nil {
+		log.Warnf("push kick message failed, cid: %d, err: %v", s.CID(), err)
+	}
+
+	if uid := s.UID(); uid > 0 {
+		s.Unbind(uid)
+
+		g.revokeResumeToken(uid)
+
+		g.limiter.release(s.CID(), uid)
+
+		g.quitChannels(uid)
+	}
+
+	return s.Close()
+}
+
+// 用户在当前网关没有会话时退出所有频道
+func (g *Gate) quitChannels(uid int64) {
+	if _, err := g.group.GetSession(session.User, uid); err != nil {
+		g.channels.quit(uid)
+	}
+}
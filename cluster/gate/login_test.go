@@ -0,0 +1,47 @@
+package gate
+
+import (
+	"github.com/dobyte/due/session"
+	"testing"
+)
+
+func TestGate_LoginMulti(t *testing.T) {
+	g := newTestGate(WithAuthenticator(testAuthenticator), WithLoginPolicy(LoginMulti), WithLoginDevices(2))
+
+	conns := make([]*testConn, 0, 3)
+	for i := 1; i <= 3; i++ {
+		conn := newTestConn(int64(i))
+		g.handleConnect(conn)
+		receive(t, g, conn, testLoginRoute)
+		conns = append(conns, conn)
+	}
+
+	if !conns[0].isClosed() {
+		t.Fatal("the earliest device should be kicked")
+	}
+
+	for _, conn := range conns[1:] {
+		if conn.isClosed() || conn.UID() != 1 {
+			t.Fatalf("the device %d should be online", conn.ID())
+		}
+	}
+
+	g.handleDisconnect(conns[0])
+	g.handleDisconnect(conns[2])
+
+	// 最近登录的设备下线后，按用户推送的消息送达剩余的设备
+	s, err := g.group.GetSession(session.User, 1)
+	if err != nil || s.CID() != conns[1].ID() {
+		t.Fatalf("the user session should be moved to the remaining device: %v", err)
+	}
+
+	if gid, _ := g.proxy.locateGate(g.ctx, 1); gid != g.opts.id {
+		t.Fatalf("the user should be still located at the gate, got %q", gid)
+	}
+
+	g.handleDisconnect(conns[1])
+
+	if gid, _ := g.proxy.locateGate(g.ctx, 1); gid != "" {
+		t.Fatalf("the user should be unbound after all devices offline, got %q", gid)
+	}
+}
@@ -20,34 +20,67 @@ import (
 )
 
 const (
-	defaultName    = "gate"          // 默认名称
-	defaultTimeout = 3 * time.Second // 默认超时时间
+	defaultName         = "gate"           // 默认名称
+	defaultTimeout      = 3 * time.Second  // 默认超时时间
+	defaultDrainTimeout = 30 * time.Second // 默认排空超时时间
+	defaultAuthDeadline = 10 * time.Second // 默认鉴权截止时间
+	defaultAuthAttempts = 3                // 默认鉴权尝试次数
+	defaultResumeBuffer = 256              // 默认会话恢复的消息缓存数
+	defaultLoginPolicy  = LoginKick        // 默认重复登录策略
+	defaultLoginDevices = 3                // 默认多端登录的设备数
 )
 
 const (
-	defaultIDKey      = "config.cluster.gate.id"
-	defaultNameKey    = "config.cluster.gate.name"
-	defaultTimeoutKey = "config.cluster.gate.timeout"
+	defaultIDKey           = "config.cluster.gate.id"
+	defaultAddrKey         = "config.cluster.gate.addr"
+	defaultNameKey         = "config.cluster.gate.name"
+	defaultZoneKey         = "config.cluster.gate.zone"
+	defaultTimeoutKey      = "config.cluster.gate.timeout"
+	defaultDrainTimeoutKey = "config.cluster.gate.drainTimeout"
+	defaultLoginRouteKey   = "config.cluster.gate.loginRoute"
+	defaultAuthDeadlineKey = "config.cluster.gate.authDeadline"
+	defaultAuthAttemptsKey = "config.cluster.gate.authAttempts"
+	defaultResumeWindowKey = "config.cluster.gate.resumeWindow"
+	defaultResumeBufferKey = "config.cluster.gate.resumeBufferSize"
+	defaultLoginPolicyKey  = "config.cluster.gate.loginPolicy"
+	defaultLoginDevicesKey = "config.cluster.gate.loginDevices"
 )
 
 type Option func(o *options)
 
 type options struct {
-	id          string                // 实例ID
-	name        string                // 实例名称
-	ctx         context.Context       // 上下文
-	timeout     time.Duration         // RPC调用超时时间
-	server      network.Server        // 网关服务器
-	locator     locate.Locator        // 用户定位器
-	registry    registry.Registry     // 服务注册器
-	transporter transport.Transporter // 消息传输器
+	id            string                // 实例ID
+	name          string                // 实例名称
+	zone          string                // 所在游戏区，不同游戏区的实例互不可见，为空时为默认游戏区
+	addr          string                // 对外暴露的客户端连接地址
+	ctx           context.Context       // 上下文
+	timeout       time.Duration         // RPC调用超时时间
+	drainTimeout  time.Duration         // 排空超时时间
+	server        network.Server        // 网关服务器
+	locator       locate.Locator        // 用户定位器
+	registry      registry.Registry     // 服务注册器
+	transporter   transport.Transporter // 消息传输器
+	authenticator Authenticator         // 连接鉴权器
+	loginRoute    int32                 // 登录路由，未设置鉴权器时，鉴权通过前仅该路由的消息会投递到节点
+	authDeadline  time.Duration         // 鉴权截止时间，连接建立后超过该时间仍未鉴权通过则关闭连接
+	authAttempts  int                   // 鉴权尝试次数，鉴权通过前收到的消息数超过该值则关闭连接
+	resumeWindow  time.Duration         // 会话恢复窗口，用户断线后在该时间内可凭恢复令牌恢复会话，为0时不启用
+	resumeBuffer  int                   // 会话恢复的消息缓存数，断线期间缓存的消息超过该值时立即断开会话
+	loginPolicy   LoginPolicy           // 重复登录策略
+	loginDevices  int                   // 多端登录的设备数，超过该值时踢掉最早登录的设备
 }
 
 func defaultOptions() *options {
 	opts := &options{
-		ctx:     context.Background(),
-		name:    defaultName,
-		timeout: defaultTimeout,
+		ctx:          context.Background(),
+		name:         defaultName,
+		timeout:      defaultTimeout,
+		drainTimeout: defaultDrainTimeout,
+		authDeadline: defaultAuthDeadline,
+		authAttempts: defaultAuthAttempts,
+		resumeBuffer: defaultResumeBuffer,
+		loginPolicy:  defaultLoginPolicy,
+		loginDevices: defaultLoginDevices,
 	}
 
 	if id := config.Get(defaultIDKey).String(); id != "" {
@@ -60,10 +93,51 @@ func defaultOptions() *options {
 		opts.name = name
 	}
 
+	if zone := config.Get(defaultZoneKey).String(); zone != "" {
+		opts.zone = zone
+	}
+
+	if addr := config.Get(defaultAddrKey).String(); addr != "" {
+		opts.addr = addr
+	}
+
 	if timeout := config.Get(defaultTimeoutKey).Int64(); timeout > 0 {
 		opts.timeout = time.Duration(timeout) * time.Second
 	}
 
+	if timeout := config.Get(defaultDrainTimeoutKey).Int64(); timeout > 0 {
+		opts.drainTimeout = time.Duration(timeout) * time.Second
+	}
+
+	if route := config.Get(defaultLoginRouteKey).Int32(); route > 0 {
+		opts.loginRoute = route
+	}
+
+	if deadline := config.Get(defaultAuthDeadlineKey).Int64(); deadline > 0 {
+		opts.authDeadline = time.Duration(deadline) * time.Second
+	}
+
+	if attempts := config.Get(defaultAuthAttemptsKey).Int(); attempts > 0 {
+		opts.authAttempts = attempts
+	}
+
+	if window := config.Get(defaultResumeWindowKey).Int64(); window > 0 {
+		opts.resumeWindow = time.Duration(window) * time.Second
+	}
+
+	if size := config.Get(defaultResumeBufferKey).Int(); size > 0 {
+		opts.resumeBuffer = size
+	}
+
+	switch policy := LoginPolicy(config.Get(defaultLoginPolicyKey).String()); policy {
+	case LoginKick, LoginReject, LoginMulti:
+		opts.loginPolicy = policy
+	}
+
+	if devices := config.Get(defaultLoginDevicesKey).Int(); devices > 0 {
+		opts.loginDevices = devices
+	}
+
 	return opts
 }
 
@@ -77,6 +151,17 @@ func WithName(name string) Option {
 	return func(o *options) { o.name = name }
 }
 
+// WithZone 设置所在游戏区
+func WithZone(zone string) Option {
+	return func(o *options) { o.zone = zone }
+}
+
+// WithAddr 设置对外暴露的客户端连接地址，网关排空时会将客户端重定向到其他网关的该地址
+// 不设置时默认使用内网IP与网络服务器监听端口组合的地址
+func WithAddr(addr string) Option {
+	return func(o *options) { o.addr = addr }
+}
+
 // WithContext 设置上下文
 func WithContext(ctx context.Context) Option {
 	return func(o *options) { o.ctx = ctx }
@@ -92,6 +177,11 @@ func WithTimeout(timeout time.Duration) Option {
 	return func(o *options) { o.timeout = timeout }
 }
 
+// WithDrainTimeout 设置排空超时时间，网关关闭或热重启时最多等待该时间让存量连接断开
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.drainTimeout = timeout }
+}
+
 // WithLocator 设置用户定位器
 func WithLocator(locator locate.Locator) Option {
 	return func(o *options) { o.locator = locator }
@@ -106,3 +196,44 @@ func WithRegistry(r registry.Registry) Option {
 func WithTransporter(transporter transport.Transporter) Option {
 	return func(o *options) { o.transporter = transporter }
 }
+
+// WithAuthenticator 设置连接鉴权器，鉴权通过前收到的消息均交由鉴权器处理
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(o *options) { o.authenticator = authenticator }
+}
+
+// WithLoginRoute 设置登录路由，未设置鉴权器时，鉴权通过前仅该路由的消息会投递到节点，节点绑定网关后鉴权通过
+func WithLoginRoute(route int32) Option {
+	return func(o *options) { o.loginRoute = route }
+}
+
+// WithAuthDeadline 设置鉴权截止时间，连接建立后超过该时间仍未鉴权通过则关闭连接
+func WithAuthDeadline(deadline time.Duration) Option {
+	return func(o *options) { o.authDeadline = deadline }
+}
+
+// WithAuthAttempts 设置鉴权尝试次数，鉴权通过前收到的消息数超过该值则关闭连接
+func WithAuthAttempts(attempts int) Option {
+	return func(o *options) { o.authAttempts = attempts }
+}
+
+// WithResumeWindow 设置会话恢复窗口，用户断线后在该时间内可凭恢复令牌在任意网关恢复会话，窗口过期后才会触发断开连接事件
+func WithResumeWindow(window time.Duration) Option {
+	return func(o *options) { o.resumeWindow = window }
+}
+
+// WithResumeBufferSize 设置会话恢复的消息缓存数，断线期间缓存的消息超过该值时立即断开会话
+func WithResumeBufferSize(size int) Option {
+	return func(o *options) { o.resumeBuffer = size }
+}
+
+// WithLoginPolicy 设置重复登录策略，用户绑定时在整个集群范围内处理该用户已登录的会话
+// 多端登录仅限制同时在线的连接数，按用户推送的消息仅送达最近登录的设备，且不启用会话恢复
+func WithLoginPolicy(policy LoginPolicy) Option {
+	return func(o *options) { o.loginPolicy = policy }
+}
+
+// WithLoginDevices 设置多端登录的设备数，超过该值时踢掉最早登录的设备
+func WithLoginDevices(devices int) Option {
+	return func(o *options) { o.loginDevices = devices }
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/dobyte/due/packet"
 	"github.com/dobyte/due/session"
+	"github.com/dobyte/due/transport"
 )
 
 type provider struct {
@@ -21,13 +22,17 @@ func (p *provider) Bind(ctx context.Context, cid, uid int64) error {
 		return err
 	}
 
-	err = p.gate.proxy.bindGate(ctx, uid)
+	err = p.gate.login(ctx, s, uid)
 	if err != nil {
 		return err
 	}
 
 	s.Bind(uid)
 
+	p.gate.finishAuth(cid)
+
+	p.gate.issueResumeToken(s, uid)
+
 	return nil
 }
 
@@ -42,16 +47,33 @@ func (p *provider) Unbind(ctx context.Context, uid int64) error {
 		return err
 	}
 
-	err = p.gate.proxy.unbindGate(ctx, uid)
+	err = p.gate.logout(ctx, uid, s.CID())
 	if err != nil {
 		return err
 	}
 
+	p.gate.reawaitAuth(s.CID())
+
 	s.Unbind(uid)
 
+	p.gate.revokeResumeToken(uid)
+
+	p.gate.limiter.releaseUser(uid)
+
+	p.gate.quitChannels(uid)
+
 	return nil
 }
 
+// Resume 移交断线的用户会话给恢复会话的网关
+func (p *provider) Resume(ctx context.Context, uid int64, nonce string, gid string) (*transport.ResumeReply, error) {
+	if uid <= 0 || nonce == "" || gid == "" {
+		return nil, ErrInvalidArgument
+	}
+
+	return p.gate.handover(ctx, uid, nonce, gid)
+}
+
 // GetIP 获取客户端IP地址
 func (p *provider) GetIP(kind session.Kind, target int64) (string, error) {
 	s, err := p.gate.group.GetSession(kind, target)
@@ -62,6 +84,44 @@ func (p *provider) GetIP(kind session.Kind, target int64) (string, error) {
 	return s.RemoteIP()
 }
 
+// GetAttrs 获取会话属性
+func (p *provider) GetAttrs(kind session.Kind, target int64) (map[string]string, error) {
+	s, err := p.gate.group.GetSession(kind, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Attrs(), nil
+}
+
+// SetAttrs 设置会话属性
+func (p *provider) SetAttrs(kind session.Kind, target int64, attrs map[string]string) error {
+	s, err := p.gate.group.GetSession(kind, target)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range attrs {
+		s.Set(key, value)
+	}
+
+	return nil
+}
+
+// DelAttrs 删除会话属性
+func (p *provider) DelAttrs(kind session.Kind, target int64, keys []string) error {
+	s, err := p.gate.group.GetSession(kind, target)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		s.Delete(key)
+	}
+
+	return nil
+}
+
 // Push 发送消息
 func (p *provider) Push(kind session.Kind, target int64, message *packet.Message) error {
 	msg, err := packet.Pack(message)
@@ -69,6 +129,10 @@ func (p *provider) Push(kind session.Kind, target int64, message *packet.Message
 		return err
 	}
 
+	if kind == session.User {
+		return p.gate.pushUser(target, message, msg)
+	}
+
 	return p.gate.group.Push(kind, target, msg)
 }
 
@@ -79,6 +143,11 @@ func (p *provider) Multicast(kind session.Kind, targets []int64, message *packet
 		return 0, err
 	}
 
+	if kind == session.User {
+		total, err := p.gate.multicastUsers(targets, message, msg)
+		return int64(total), err
+	}
+
 	total, err := p.gate.group.Multicast(kind, targets, msg)
 
 	return int64(total), err
@@ -91,7 +160,7 @@ func (p *provider) Broadcast(kind session.Kind, message *packet.Message) (int64,
 		return 0, err
 	}
 
-	total, err := p.gate.group.Broadcast(kind, msg)
+	total, err := p.gate.broadcast(kind, message, msg)
 
 	return int64(total), err
 }
@@ -105,3 +174,47 @@ func (p *provider) Disconnect(kind session.Kind, target int64, isForce bool) err
 
 	return s.Close(isForce)
 }
+
+// Kick 踢下线
+func (p *provider) Kick(kind session.Kind, target int64, reason int32) error {
+	return p.gate.kick(kind, target, reason)
+}
+
+// JoinChannel 加入频道，仅绑定到当前网关的用户可以加入
+func (p *provider) JoinChannel(channel string, uids []int64) error {
+	targets := make([]int64, 0, len(uids))
+	for _, uid := range uids {
+		if _, err := p.gate.group.GetSession(session.User, uid); err == nil {
+			targets = append(targets, uid)
+		}
+	}
+
+	if len(targets) == 0 {
+		return session.ErrNotFoundSession
+	}
+
+	p.gate.channels.join(channel, targets...)
+
+	return nil
+}
+
+// LeaveChannel 离开频道，用户为空时解散频道
+func (p *provider) LeaveChannel(channel string, uids []int64) error {
+	if len(uids) == 0 {
+		p.gate.channels.dismiss(channel)
+	} else {
+		p.gate.channels.leave(channel, uids...)
+	}
+
+	return nil
+}
+
+// PublishChannel 发布频道消息
+func (p *provider) PublishChannel(channel string, message *packet.Message) (int64, error) {
+	targets := p.gate.channels.targets(channel)
+	if len(targets) == 0 {
+		return 0, nil
+	}
+
+	return p.Multicast(session.User, targets, message)
+}
@@ -2,12 +2,16 @@ package gate
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/dobyte/due/cluster"
 	"github.com/dobyte/due/internal/link"
+	"github.com/dobyte/due/locate"
+	"github.com/dobyte/due/session"
 
 	"github.com/dobyte/due/log"
 	"github.com/dobyte/due/packet"
 	"github.com/dobyte/due/router"
+	"github.com/dobyte/due/transport"
 )
 
 var (
@@ -27,6 +31,7 @@ type proxy struct {
 
 func newProxy(gate *Gate) *proxy {
 	return &proxy{gate: gate, link: link.NewLink(&link.Options{
+		Zone:        gate.opts.zone,
 		GID:         gate.opts.id,
 		Locator:     gate.opts.locator,
 		Registry:    gate.opts.registry,
@@ -36,7 +41,7 @@ func newProxy(gate *Gate) *proxy {
 
 // 绑定用户与网关间的关系
 func (p *proxy) bindGate(ctx context.Context, uid int64) error {
-	err := p.gate.opts.locator.Set(ctx, uid, cluster.Gate, p.gate.opts.id)
+	err := p.link.Locator().Set(ctx, uid, cluster.Gate, "", p.gate.opts.id)
 	if err != nil {
 		return err
 	}
@@ -51,7 +56,7 @@ func (p *proxy) bindGate(ctx context.Context, uid int64) error {
 
 // 解绑用户与网关间的关系
 func (p *proxy) unbindGate(ctx context.Context, uid int64) error {
-	err := p.gate.opts.locator.Rem(ctx, uid, cluster.Gate, p.gate.opts.id)
+	err := p.link.Locator().Rem(ctx, uid, cluster.Gate, "", p.gate.opts.id)
 	if err != nil {
 		return err
 	}
@@ -64,18 +69,113 @@ func (p *proxy) unbindGate(ctx context.Context, uid int64) error {
 	return nil
 }
 
+// 转移用户绑定的网关，不触发重连事件
+func (p *proxy) transferGate(ctx context.Context, uid int64, gid string) error {
+	return p.link.Locator().Set(ctx, uid, cluster.Gate, "", gid)
+}
+
+// 查询用户绑定的网关
+func (p *proxy) locateGate(ctx context.Context, uid int64) (string, error) {
+	return p.link.Locator().Get(ctx, uid, cluster.Gate, "")
+}
+
+// 从原网关恢复断线的用户会话
+func (p *proxy) resume(ctx context.Context, gid string, uid int64, nonce string) (*transport.ResumeReply, error) {
+	return p.link.Resume(ctx, gid, uid, nonce)
+}
+
+// 踢下线其他网关的会话
+func (p *proxy) kick(ctx context.Context, gid string, kind session.Kind, target int64, reason int32) error {
+	return p.link.Kick(ctx, gid, kind, target, reason)
+}
+
+// 查询用户是否在指定网关在线，网关不可用时视为离线
+func (p *proxy) online(ctx context.Context, gid string, uid int64) bool {
+	_, err := p.link.GetIP(ctx, &link.GetIPArgs{GID: gid, Kind: session.User, Target: uid})
+	return err == nil
+}
+
+// 获取登录设备定位器
+func (p *proxy) deviceLocator() (locate.DeviceLocator, error) {
+	if dl, ok := p.link.Locator().(locate.DeviceLocator); ok {
+		return dl, nil
+	}
+
+	return nil, locate.ErrDeviceNotSupported
+}
+
+// 添加用户的登录设备，返回超过上限被移除的设备
+func (p *proxy) addDevice(ctx context.Context, uid int64, d device, limit int) ([]device, error) {
+	dl, err := p.deviceLocator()
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	evicted, err := dl.AddDevice(ctx, uid, string(buf), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.toDevices(uid, evicted), nil
+}
+
+// 移除用户的登录设备，返回剩余的登录设备，最近登录的设备在最后
+func (p *proxy) remDevice(ctx context.Context, uid int64, d device) ([]device, error) {
+	dl, err := p.deviceLocator()
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	remains, err := dl.RemDevice(ctx, uid, string(buf))
+	if err != nil {
+		return nil, err
+	}
+
+	return p.toDevices(uid, remains), nil
+}
+
+// 解析登录设备
+func (p *proxy) toDevices(uid int64, items []string) []device {
+	devices := make([]device, 0, len(items))
+	for _, item := range items {
+		d := device{}
+		if err := json.Unmarshal([]byte(item), &d); err != nil {
+			log.Warnf("unmarshal login device failed, uid: %d, err: %v", uid, err)
+			continue
+		}
+		devices = append(devices, d)
+	}
+
+	return devices
+}
+
 // 投递消息
-func (p *proxy) deliver(ctx context.Context, cid, uid int64, message *packet.Message) error {
+func (p *proxy) deliver(ctx context.Context, cid, uid int64, attrs, claims map[string]string, message *packet.Message) error {
 	return p.link.Deliver(ctx, &link.DeliverArgs{
 		CID:     cid,
 		UID:     uid,
+		Attrs:   attrs,
+		Claims:  claims,
 		Message: message,
 	})
 }
 
 // 启动监听
-func (p *proxy) watch(ctx context.Context) {
-	p.link.WatchUserLocate(ctx, cluster.Node)
+// 恢复会话时需调用其他网关，因此同时监听网关服务实例
+func (p *proxy) watch(ctx context.Context) error {
+	if err := p.link.WatchUserLocate(ctx, cluster.Node); err != nil {
+		return err
+	}
 
-	p.link.WatchServiceInstance(ctx, cluster.Node)
+	return p.link.WatchServiceInstance(ctx, cluster.Node, cluster.Gate)
 }
@@ -0,0 +1,570 @@
+package gate
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/code"
+	"github.com/dobyte/due/errors"
+	"github.com/dobyte/due/log"
+	"github.com/dobyte/due/network"
+	"github.com/dobyte/due/packet"
+	"github.com/dobyte/due/session"
+	"github.com/dobyte/due/transport"
+	"github.com/dobyte/due/utils/xuuid"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalidResumeToken = errors.New("invalid resume token")
+
+// 断线等待恢复或恢复中的用户会话
+type resumption struct {
+	mu       sync.Mutex
+	nonce    string            // 恢复令牌随机串
+	timer    *time.Timer       // 恢复窗口定时器
+	handing  bool              // 是否移交中，调用方须持有写锁
+	expired  bool              // 移交中恢复窗口是否已过期，调用方须持有写锁
+	attrs    map[string]string // 会话属性
+	claims   map[string]string // 鉴权声明
+	messages []*packet.Message // 缓存的消息
+}
+
+// 会话恢复
+// 用户绑定后网关下发恢复令牌，断线后在恢复窗口内缓存推送给该用户的消息；
+// 客户端凭令牌在任意网关重连后，原网关将用户绑定的网关转移给新网关并移交缓存的消息，全程不触发断开及重连事件
+type resumer struct {
+	rw         sync.RWMutex
+	tokens     map[int64]string      // 已绑定用户的恢复令牌随机串
+	suspends   map[int64]*resumption // 断线等待恢复的用户会话
+	resumings  map[int64]*resumption // 恢复中的用户会话，缓存恢复完成前推送的消息
+	redirected bool                  // 是否已通知已绑定用户重定向到其他网关
+}
+
+func newResumer() *resumer {
+	return &resumer{
+		tokens:    make(map[int64]string),
+		suspends:  make(map[int64]*resumption),
+		resumings: make(map[int64]*resumption),
+	}
+}
+
+// 缓存消息，调用方须持有读锁
+func (r *resumption) append(message *packet.Message) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages = append(r.messages, message)
+
+	return len(r.messages)
+}
+
+// 取出缓存的消息
+func (r *resumption) drain() []*packet.Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	messages := r.messages
+	r.messages = nil
+
+	return messages
+}
+
+// 是否启用会话恢复，多端登录时同一用户存在多个会话，不启用会话恢复
+func (g *Gate) resumeEnabled() bool {
+	return g.opts.resumeWindow > 0 && g.opts.loginPolicy != LoginMulti
+}
+
+// 下发恢复令牌
+func (g *Gate) issueResumeToken(s *session.Session, uid int64) {
+	if !g.resumeEnabled() {
+		return
+	}
+
+	g.resumer.rw.Lock()
+	nonce, err := g.renewResumeToken(uid)
+	g.resumer.rw.Unlock()
+	if err != nil {
+		log.Errorf("generate resume token failed, uid: %d, err: %v", uid, err)
+		return
+	}
+
+	g.pushResumeToken(s, uid, nonce)
+}
+
+// 生成新的恢复令牌，用户重新绑定时丢弃未恢复的断线会话，调用方须持有写锁
+func (g *Gate) renewResumeToken(uid int64) (string, error) {
+	nonce, err := xuuid.UUID()
+	if err != nil {
+		return "", err
+	}
+
+	g.resumer.tokens[uid] = nonce
+
+	if r, ok := g.resumer.suspends[uid]; ok {
+		r.timer.Stop()
+		delete(g.resumer.suspends, uid)
+	}
+
+	return nonce, nil
+}
+
+// 推送恢复令牌
+func (g *Gate) pushResumeToken(s *session.Session, uid int64, nonce string) {
+	msg, err := packet.Pack(&packet.Message{Route: cluster.ResumeRoute, Buffer: []byte(makeResumeToken(uid, g.opts.id, nonce))})
+	if err != nil {
+		log.Errorf("pack resume token failed: %v", err)
+		return
+	}
+
+	if err = s.Push(msg); err != nil {
+		log.Warnf("push resume token failed, uid: %d, err: %v", uid, err)
+	}
+}
+
+// 作废恢复令牌，用户主动解绑后不再恢复会话
+func (g *Gate) revokeResumeToken(uid int64) {
+	if !g.resumeEnabled() {
+		return
+	}
+
+	g.resumer.rw.Lock()
+	delete(g.resumer.tokens, uid)
+	g.resumer.rw.Unlock()
+}
+
+// 移除断开连接的会话，已下发恢复令牌的用户挂起会话等待恢复
+func (g *Gate) detach(conn network.Conn) (s *session.Session, suspended bool, err error) {
+	if !g.resumeEnabled() {
+		s, err = g.group.RemSession(session.Conn, conn.ID())
+		return
+	}
+
+	g.resumer.rw.Lock()
+	defer g.resumer.rw.Unlock()
+
+	if s, err = g.group.RemSession(session.Conn, conn.ID()); err != nil {
+		return
+	}
+
+	uid := conn.UID()
+	nonce, ok := g.resumer.tokens[uid]
+	if uid <= 0 || !ok {
+		return
+	}
+
+	g.suspend(uid, nonce, s)
+
+	return s, true, nil
+}
+
+// 挂起用户会话等待恢复，保留会话属性及鉴权声明，调用方须持有写锁
+func (g *Gate) suspend(uid int64, nonce string, s *session.Session) *resumption {
+	delete(g.resumer.tokens, uid)
+
+	r := &resumption{nonce: nonce, attrs: s.Attrs(), claims: s.Claims()}
+	r.timer = time.AfterFunc(g.opts.resumeWindow, func() { g.expireResume(uid, r) })
+	g.resumer.suspends[uid] = r
+
+	return r
+}
+
+// 挂起已重定向的在线用户会话
+// 重定向后客户端先连接新网关再断开旧连接，新网关凭恢复令牌接管会话时旧连接可能尚未断开，
+// 校验令牌后立即移除并挂起会话，旧连接随后断开时不再触发解绑，调用方须持有写锁
+func (g *Gate) suspendRedirected(uid int64, nonce string) (*resumption, *session.Session, bool) {
+	if !g.resumer.redirected || g.resumer.tokens[uid] != nonce {
+		return nil, nil, false
+	}
+
+	s, err := g.group.GetSession(session.User, uid)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	if _, err = g.group.RemSession(session.Conn, s.CID()); err != nil {
+		return nil, nil, false
+	}
+
+	return g.suspend(uid, nonce, s), s, true
+}
+
+// 标记已通知已绑定用户重定向，此后用户可凭恢复令牌在新网关接管仍在线的会话
+func (g *Gate) markRedirected() {
+	g.resumer.rw.Lock()
+	g.resumer.redirected = true
+	g.resumer.rw.Unlock()
+}
+
+// 丢弃断线等待恢复的用户会话，不触发断开连接事件
+func (g *Gate) dropSuspended(uid int64) bool {
+	if !g.resumeEnabled() {
+		return false
+	}
+
+	g.resumer.rw.Lock()
+	r, ok := g.resumer.suspends[uid]
+	if ok {
+		r.timer.Stop()
+		delete(g.resumer.suspends, uid)
+	}
+	g.resumer.rw.Unlock()
+
+	if ok {
+		g.channels.quit(uid)
+	}
+
+	return ok
+}
+
+// 恢复窗口过期，断开用户会话；会话移交中时待移交失败后再断开
+func (g *Gate) expireResume(uid int64, r *resumption) {
+	g.resumer.rw.Lock()
+	if g.resumer.suspends[uid] != r {
+		g.resumer.rw.Unlock()
+		return
+	}
+	if r.handing {
+		r.expired = true
+		g.resumer.rw.Unlock()
+		return
+	}
+	delete(g.resumer.suspends, uid)
+	g.resumer.rw.Unlock()
+
+	r.timer.Stop()
+
+	g.disconnectUser(uid)
+}
+
+// 断开所有等待恢复的用户会话
+func (g *Gate) expireResumes() {
+	g.resumer.rw.Lock()
+	suspends := g.resumer.suspends
+	g.resumer.suspends = make(map[int64]*resumption)
+	g.resumer.rw.Unlock()
+
+	for uid, r := range suspends {
+		r.timer.Stop()
+		g.disconnectUser(uid)
+	}
+}
+
+// 断开用户会话，解绑用户与网关间的关系并触发断开连接事件
+// 用户已绑定到其他网关时仅清理本地状态
+func (g *Gate) disconnectUser(uid int64) {
+	g.channels.quit(uid)
+
+	ctx, cancel := context.WithTimeout(g.ctx, g.opts.timeout)
+	defer cancel()
+
+	if gid, err := g.proxy.locateGate(ctx, uid); err == nil && gid != "" && gid != g.opts.id {
+		return
+	}
+
+	if err := g.proxy.unbindGate(ctx, uid); err != nil {
+		log.Errorf("user unbind failed, gid: %s, uid: %d, err: %v", g.opts.id, uid, err)
+	}
+}
+
+// 缓存推送给断线或恢复中用户的消息，调用方须持有读锁
+// 断线期间缓存的消息超过上限时立即断开用户会话
+func (g *Gate) bufferResume(uid int64, message *packet.Message) bool {
+	if r, ok := g.resumer.suspends[uid]; ok {
+		if n := r.append(message); n == g.opts.resumeBuffer+1 {
+			log.Warnf("the resume buffer overflow, uid: %d", uid)
+			go g.expireResume(uid, r)
+		}
+		return true
+	}
+
+	if r, ok := g.resumer.resumings[uid]; ok {
+		r.append(message)
+		return true
+	}
+
+	return false
+}
+
+// 推送消息给用户，用户断线或恢复中时缓存消息
+func (g *Gate) pushUser(uid int64, message *packet.Message, msg []byte) error {
+	if !g.resumeEnabled() {
+		return g.group.Push(session.User, uid, msg)
+	}
+
+	g.resumer.rw.RLock()
+	defer g.resumer.rw.RUnlock()
+
+	if g.bufferResume(uid, message) {
+		return nil
+	}
+
+	return g.group.Push(session.User, uid, msg)
+}
+
+// 推送组播消息给用户，断线或恢复中的用户缓存消息
+func (g *Gate) multicastUsers(uids []int64, message *packet.Message, msg []byte) (int, error) {
+	if !g.resumeEnabled() {
+		return g.group.Multicast(session.User, uids, msg)
+	}
+
+	g.resumer.rw.RLock()
+	defer g.resumer.rw.RUnlock()
+
+	targets := make([]int64, 0, len(uids))
+	for _, uid := range uids {
+		if !g.bufferResume(uid, message) {
+			targets = append(targets, uid)
+		}
+	}
+
+	n, err := g.group.Multicast(session.User, targets, msg)
+
+	return n + len(uids) - len(targets), err
+}
+
+// 推送广播消息，断线的用户缓存消息，按用户广播时恢复中的用户同样缓存消息
+func (g *Gate) broadcast(kind session.Kind, message *packet.Message, msg []byte) (int, error) {
+	if !g.resumeEnabled() {
+		return g.group.Broadcast(kind, msg)
+	}
+
+	g.resumer.rw.RLock()
+	defer g.resumer.rw.RUnlock()
+
+	n, err := g.group.Broadcast(kind, msg)
+	if err != nil {
+		return n, err
+	}
+
+	for uid := range g.resumer.suspends {
+		g.bufferResume(uid, message)
+		n++
+	}
+
+	if kind == session.User {
+		for _, r := range g.resumer.resumings {
+			r.append(message)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// 移交断线或已重定向的用户会话，校验恢复令牌后将用户绑定的网关转移给指定网关，并返回缓存的消息及会话数据
+// 转移网关期间不持有锁，会话标记为移交中，推送给用户的消息继续缓存
+func (g *Gate) handover(ctx context.Context, uid int64, nonce, gid string) (*transport.ResumeReply, error) {
+	if !g.resumeEnabled() {
+		return nil, ErrInvalidResumeToken
+	}
+
+	var redirected *session.Session
+
+	g.resumer.rw.Lock()
+	r, ok := g.resumer.suspends[uid]
+	if !ok {
+		r, redirected, ok = g.suspendRedirected(uid, nonce)
+	}
+	if !ok || r.nonce != nonce || r.handing {
+		g.resumer.rw.Unlock()
+		return nil, ErrInvalidResumeToken
+	}
+	r.handing = true
+	g.resumer.rw.Unlock()
+
+	if redirected != nil {
+		if err := redirected.Close(true); err != nil {
+			log.Warnf("close redirected connection failed, uid: %d, err: %v", uid, err)
+		}
+	}
+
+	err := g.proxy.transferGate(ctx, uid, gid)
+
+	g.resumer.rw.Lock()
+	r.handing = false
+	expired := r.expired
+	if err == nil && g.resumer.suspends[uid] == r {
+		delete(g.resumer.suspends, uid)
+	}
+	g.resumer.rw.Unlock()
+
+	if err != nil {
+		if expired {
+			g.expireResume(uid, r)
+		}
+		return nil, err
+	}
+
+	r.timer.Stop()
+
+	if gid != g.opts.id {
+		g.channels.quit(uid)
+	}
+
+	messages := r.drain()
+
+	reply := &transport.ResumeReply{
+		Messages: make([]*transport.Message, 0, len(messages)),
+		Attrs:    r.attrs,
+		Claims:   r.claims,
+	}
+
+	for _, message := range messages {
+		reply.Messages = append(reply.Messages, &transport.Message{
+			Seq:    message.Seq,
+			Route:  message.Route,
+			Buffer: message.Buffer,
+		})
+	}
+
+	return reply, nil
+}
+
+// 处理客户端的会话恢复请求
+// 从原网关接管用户会话后，按顺序重放断线期间及恢复过程中缓存的消息，绑定用户并下发新的恢复令牌
+func (g *Gate) resume(conn network.Conn, message *packet.Message) {
+	uid, gid, nonce, err := parseResumeToken(message.Buffer)
+	if err == nil && (!g.resumeEnabled() || conn.UID() > 0) {
+		err = ErrInvalidResumeToken
+	}
+	if err != nil {
+		log.Debugf("the session resume failed, cid: %d, err: %v", conn.ID(), err)
+		g.replyError(conn, message, code.ResumeFailed)
+		return
+	}
+
+	r := &resumption{}
+
+	g.resumer.rw.Lock()
+	if _, ok := g.resumer.resumings[uid]; ok {
+		g.resumer.rw.Unlock()
+		g.replyError(conn, message, code.ResumeFailed)
+		return
+	}
+	g.resumer.resumings[uid] = r
+	g.resumer.rw.Unlock()
+
+	ctx, cancel := context.WithTimeout(g.ctx, g.opts.timeout)
+	var reply *transport.ResumeReply
+	if gid == g.opts.id {
+		reply, err = g.handover(ctx, uid, nonce, gid)
+	} else {
+		reply, err = g.proxy.resume(ctx, gid, uid, nonce)
+	}
+	cancel()
+
+	if err == nil {
+		err = g.restore(conn, uid, reply, r)
+	}
+
+	if err != nil {
+		g.resumer.rw.Lock()
+		delete(g.resumer.resumings, uid)
+		g.resumer.rw.Unlock()
+
+		log.Debugf("the session resume failed, cid: %d, uid: %d, err: %v", conn.ID(), uid, err)
+		g.replyError(conn, message, code.ResumeFailed)
+
+		// 已接管用户会话但连接已断开
+		if reply != nil {
+			go g.disconnectUser(uid)
+		}
+		return
+	}
+
+	g.finishAuth(conn.ID())
+}
+
+// 恢复用户会话
+// 重放消息时不持有锁，重放期间推送的消息继续缓存；持有写锁确认已无待重放的消息后再绑定用户，保证消息顺序
+func (g *Gate) restore(conn network.Conn, uid int64, reply *transport.ResumeReply, r *resumption) error {
+	s, err := g.group.GetSession(session.Conn, conn.ID())
+	if err != nil {
+		return err
+	}
+
+	messages := make([]*packet.Message, 0, len(reply.Messages))
+	for _, message := range reply.Messages {
+		messages = append(messages, &packet.Message{Seq: message.Seq, Route: message.Route, Buffer: message.Buffer})
+	}
+
+	for {
+		if err = g.replay(s, append(messages, r.drain()...)); err != nil {
+			return err
+		}
+
+		g.resumer.rw.Lock()
+		if messages = r.drain(); len(messages) == 0 {
+			break
+		}
+		g.resumer.rw.Unlock()
+	}
+
+	delete(g.resumer.resumings, uid)
+
+	s.Bind(uid)
+	s.SetClaims(reply.Claims)
+	for key, value := range reply.Attrs {
+		s.Set(key, value)
+	}
+
+	nonce, err := g.renewResumeToken(uid)
+	g.resumer.rw.Unlock()
+	if err != nil {
+		log.Errorf("generate resume token failed, uid: %d, err: %v", uid, err)
+		return nil
+	}
+
+	g.pushResumeToken(s, uid, nonce)
+
+	return nil
+}
+
+// 重放缓存的消息
+func (g *Gate) replay(s *session.Session, messages []*packet.Message) error {
+	for _, message := range messages {
+		msg, err := packet.Pack(message)
+		if err != nil {
+			log.Errorf("pack resume message failed: %v", err)
+			continue
+		}
+
+		if err = s.Push(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 生成恢复令牌，令牌为“用户ID:网关ID:随机串”的base64编码
+func makeResumeToken(uid int64, gid, nonce string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s:%s", uid, gid, nonce)))
+}
+
+// 解析恢复令牌
+func parseResumeToken(token []byte) (uid int64, gid, nonce string, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(string(token))
+	if err != nil {
+		err = ErrInvalidResumeToken
+		return
+	}
+
+	str := string(data)
+	i, j := strings.Index(str, ":"), strings.LastIndex(str, ":")
+	if i <= 0 || j <= i+1 || j == len(str)-1 {
+		err = ErrInvalidResumeToken
+		return
+	}
+
+	if uid, err = strconv.ParseInt(str[:i], 10, 64); err != nil || uid <= 0 {
+		err = ErrInvalidResumeToken
+		return
+	}
+
+	return uid, str[i+1 : j], str[j+1:], nil
+}
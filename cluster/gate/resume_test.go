@@ -0,0 +1,155 @@
+package gate
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/packet"
+	"testing"
+	"time"
+)
+
+func TestResumeToken(t *testing.T) {
+	token := makeResumeToken(1, "gate:1", "nonce")
+
+	uid, gid, nonce, err := parseResumeToken([]byte(token))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uid != 1 || gid != "gate:1" || nonce != "nonce" {
+		t.Fatalf("unexpected token: %d, %s, %s", uid, gid, nonce)
+	}
+
+	invalids := []string{
+		"!@#",
+		base64.RawURLEncoding.EncodeToString([]byte("1")),
+		base64.RawURLEncoding.EncodeToString([]byte("1:gate")),
+		base64.RawURLEncoding.EncodeToString([]byte("1::nonce")),
+		base64.RawURLEncoding.EncodeToString([]byte("1:gate:")),
+		base64.RawURLEncoding.EncodeToString([]byte(":gate:nonce")),
+		base64.RawURLEncoding.EncodeToString([]byte("0:gate:nonce")),
+		base64.RawURLEncoding.EncodeToString([]byte("a:gate:nonce")),
+	}
+
+	for _, token := range invalids {
+		if _, _, _, err = parseResumeToken([]byte(token)); err != ErrInvalidResumeToken {
+			t.Fatalf("the token %q should be invalid", token)
+		}
+	}
+}
+
+// 转移网关时阻塞的定位器
+type blockingLocator struct {
+	*memLocator
+	block chan struct{}
+}
+
+func (l *blockingLocator) Set(ctx context.Context, uid int64, kind cluster.Kind, group string, insID string) error {
+	if l.block != nil {
+		<-l.block
+	}
+
+	return l.memLocator.Set(ctx, uid, kind, group, insID)
+}
+
+// 创建断线等待恢复的用户会话
+func newSuspendedGate(t *testing.T, opts ...Option) *Gate {
+	g := newTestGate(append([]Option{WithAuthenticator(testAuthenticator), WithResumeWindow(time.Minute)}, opts...)...)
+	conn := newTestConn(1)
+	g.handleConnect(conn)
+
+	receive(t, g, conn, testLoginRoute)
+	if conn.UID() != 1 {
+		t.Fatal("the connection should be bound to user 1")
+	}
+
+	g.handleDisconnect(conn)
+
+	g.resumer.rw.RLock()
+	_, ok := g.resumer.suspends[1]
+	g.resumer.rw.RUnlock()
+
+	if !ok {
+		t.Fatal("the session should be suspended")
+	}
+
+	return g
+}
+
+func TestGate_ResumeBufferOverflow(t *testing.T) {
+	g := newSuspendedGate(t, WithResumeBufferSize(2))
+
+	suspended := func() bool {
+		g.resumer.rw.RLock()
+		defer g.resumer.rw.RUnlock()
+		_, ok := g.resumer.suspends[1]
+		return ok
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := g.pushUser(1, &packet.Message{Route: 2}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if !suspended() {
+		t.Fatal("the session should be suspended before the buffer overflow")
+	}
+
+	if err := g.pushUser(1, &packet.Message{Route: 2}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if suspended() {
+		t.Fatal("the session should be dropped after the buffer overflow")
+	}
+}
+
+func TestGate_HandoverUnlocked(t *testing.T) {
+	locator := &blockingLocator{memLocator: newMemLocator()}
+	g := newSuspendedGate(t, WithLocator(locator))
+
+	g.resumer.rw.RLock()
+	nonce := g.resumer.suspends[1].nonce
+	g.resumer.rw.RUnlock()
+
+	locator.block = make(chan struct{})
+
+	type result struct {
+		messages int
+		err      error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		reply, err := g.handover(context.Background(), 1, nonce, "gate-2")
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		done <- result{messages: len(reply.Messages)}
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	pushed := make(chan error, 1)
+	go func() { pushed <- g.pushUser(1, &packet.Message{Route: 2}, nil) }()
+
+	select {
+	case err := <-pushed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the push should not be blocked by the handover")
+	}
+
+	close(locator.block)
+
+	if r := <-done; r.err != nil || r.messages != 1 {
+		t.Fatalf("unexpected handover result: %d, %v", r.messages, r.err)
+	}
+}
@@ -0,0 +1,57 @@
+package gate
+
+import (
+	"context"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/log"
+	"github.com/dobyte/due/network"
+	"github.com/dobyte/due/packet"
+	"github.com/dobyte/due/session"
+)
+
+// RouteHandler 网关本地路由处理器，处理器可通过会话向客户端回复消息
+type RouteHandler func(ctx context.Context, s *session.Session, message *packet.Message)
+
+// AddRouteHandler 添加网关本地路由处理器，须在网关启动前添加
+// 本地路由的消息直接在网关内处理，不会投递到节点，节点也无需注册该路由；
+// 本地路由在鉴权通过前同样可用，需要鉴权的处理器可通过会话的用户ID自行判断；
+// 框架保留的重定向、错误响应、会话恢复及踢下线路由不能添加为本地路由
+func (g *Gate) AddRouteHandler(route int32, handler RouteHandler) {
+	if g.started {
+		log.Warnf("the gate server is working, can't add route handler")
+		return
+	}
+
+	if handler == nil {
+		log.Warnf("the route handler is nil, route: %d", route)
+		return
+	}
+
+	switch route {
+	case cluster.RedirectRoute, cluster.ErrorRoute, cluster.ResumeRoute, cluster.KickRoute:
+		log.Warnf("the route is reserved, can't add route handler, route: %d", route)
+		return
+	}
+
+	g.routes[route] = handler
+}
+
+// 处理网关本地路由的消息，非本地路由时返回false
+func (g *Gate) handleLocal(conn network.Conn, message *packet.Message) bool {
+	handler, ok := g.routes[message.Route]
+	if !ok {
+		return false
+	}
+
+	s, err := g.group.GetSession(session.Conn, conn.ID())
+	if err != nil {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(g.ctx, g.opts.timeout)
+	defer cancel()
+
+	handler(ctx, s, message)
+
+	return true
+}
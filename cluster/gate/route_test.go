@@ -0,0 +1,80 @@
+package gate
+
+import (
+	"context"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/code"
+	"github.com/dobyte/due/packet"
+	"github.com/dobyte/due/session"
+	"testing"
+	"time"
+)
+
+const testLocalRoute = 100
+
+func TestGate_AddRouteHandler(t *testing.T) {
+	g := newTestGate()
+	handler := func(ctx context.Context, s *session.Session, message *packet.Message) {}
+
+	for _, route := range []int32{cluster.RedirectRoute, cluster.ErrorRoute, cluster.ResumeRoute, cluster.KickRoute} {
+		g.AddRouteHandler(route, handler)
+	}
+	g.AddRouteHandler(testLocalRoute, nil)
+
+	if len(g.routes) != 0 {
+		t.Fatalf("the reserved routes and nil handlers should be rejected, got %d routes", len(g.routes))
+	}
+
+	g.AddRouteHandler(testLocalRoute, handler)
+
+	if _, ok := g.routes[testLocalRoute]; !ok {
+		t.Fatal("the local route should be added")
+	}
+}
+
+func TestGate_HandleLocal(t *testing.T) {
+	g := newTestGate()
+	g.AddRouteHandler(testLocalRoute, func(ctx context.Context, s *session.Session, message *packet.Message) {
+		msg, err := packet.Pack(&packet.Message{Seq: message.Seq, Route: message.Route, Buffer: []byte("pong")})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if err = s.Push(msg); err != nil {
+			t.Error(err)
+		}
+	})
+
+	conn := newTestConn(1)
+	g.handleConnect(conn)
+
+	// 本地路由在网关内处理，不会投递到节点
+	receive(t, g, conn, testLocalRoute)
+
+	message := conn.pop(time.Second)
+	if message == nil || message.Route != testLocalRoute || string(message.Buffer) != "pong" {
+		t.Fatalf("the local route should be handled by the gate, got %+v", message)
+	}
+
+	if message = conn.pop(100 * time.Millisecond); message != nil {
+		t.Fatalf("the local route should not be delivered to a node, got %+v", message)
+	}
+
+	// 其他路由仍投递到节点，无节点注册该路由时回复路由不存在
+	receive(t, g, conn, testLocalRoute+1)
+
+	message = conn.popRoute(cluster.ErrorRoute, time.Second)
+	if message == nil {
+		t.Fatal("the unknown route should be delivered to the nodes")
+	}
+
+	reply := &cluster.ErrorReply{}
+	if err := reply.Unmarshal(message.Buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	if reply.Route != testLocalRoute+1 || reply.Code != int32(code.RouteNotFound.Code()) {
+		t.Fatalf("unexpected error reply: %+v", reply)
+	}
+}
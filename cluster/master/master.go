@@ -8,6 +8,7 @@ import (
 	_ "github.com/dobyte/due/encoding/json"
 	_ "github.com/dobyte/due/encoding/proto"
 	_ "github.com/dobyte/due/encoding/xml"
+	"github.com/dobyte/due/errors"
 	"github.com/dobyte/due/log"
 )
 
@@ -39,34 +40,43 @@ func (m *Master) Name() string {
 }
 
 // Init 初始化组件
-func (m *Master) Init() {
+func (m *Master) Init() error {
 	if m.opts.codec == nil {
-		log.Fatal("codec component is not injected")
+		return errors.New("codec component is not injected")
 	}
 
 	if m.opts.locator == nil {
-		log.Fatal("locator component is not injected")
+		return errors.New("locator component is not injected")
 	}
 
 	if m.opts.registry == nil {
-		log.Fatal("registry component is not injected")
+		return errors.New("registry component is not injected")
 	}
 
 	if m.opts.transporter == nil {
-		log.Fatal("transporter component is not injected")
+		return errors.New("transporter component is not injected")
 	}
+
+	return nil
 }
 
 // Start 启动组件
-func (m *Master) Start() {
-	m.proxy.watch(m.ctx)
+func (m *Master) Start() error {
+	if err := m.proxy.watch(m.ctx); err != nil {
+		return err
+	}
 
 	m.debugPrint()
+
+	return nil
 }
 
 // Destroy 销毁组件
-func (m *Master) Destroy() {
+func (m *Master) Destroy() error {
+	m.proxy.link.Close()
 	m.cancel()
+
+	return nil
 }
 
 // Proxy 获取管理服代理
@@ -21,6 +21,7 @@ const (
 const (
 	defaultIDKey        = "config.cluster.master.id"
 	defaultNameKey      = "config.cluster.master.name"
+	defaultZoneKey      = "config.cluster.master.zone"
 	defaultCodecKey     = "config.cluster.master.codec"
 	defaultTimeoutKey   = "config.cluster.master.timeout"
 	defaultEncryptorKey = "config.cluster.master.encryptor"
@@ -32,6 +33,7 @@ type Option func(o *options)
 type options struct {
 	id          string                // 实例ID
 	name        string                // 实例名称
+	zone        string                // 所在游戏区，不同游戏区的实例互不可见，为空时为默认游戏区
 	ctx         context.Context       // 上下文
 	codec       encoding.Codec        // 编解码器
 	timeout     time.Duration         // RPC调用超时时间
@@ -58,6 +60,10 @@ func defaultOptions() *options {
 		opts.name = name
 	}
 
+	if zone := config.Get(defaultZoneKey).String(); zone != "" {
+		opts.zone = zone
+	}
+
 	if codec := config.Get(defaultCodecKey).String(); codec != "" {
 		opts.codec = encoding.Invoke(codec)
 	} else {
@@ -89,6 +95,11 @@ func WithName(name string) Option {
 	return func(o *options) { o.name = name }
 }
 
+// WithZone 设置所在游戏区
+func WithZone(zone string) Option {
+	return func(o *options) { o.zone = zone }
+}
+
 // WithCodec 设置编解码器
 func WithCodec(codec encoding.Codec) Option {
 	return func(o *options) { o.codec = codec }
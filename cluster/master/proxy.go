@@ -29,8 +29,8 @@ type Proxy interface {
 	GetName() string
 	// LocateGate 定位用户所在网关
 	LocateGate(ctx context.Context, uid int64) (string, error)
-	// LocateNode 定位用户所在节点
-	LocateNode(ctx context.Context, uid int64) (string, error)
+	// LocateNode 定位用户在节点分组中所在的节点，group为空时为默认分组
+	LocateNode(ctx context.Context, uid int64, group string) (string, error)
 	// FetchGateList 拉取网关列表
 	FetchGateList(ctx context.Context, states ...cluster.State) ([]*registry.ServiceInstance, error)
 	// FetchNodeList 拉取节点列表
@@ -56,6 +56,7 @@ type proxy struct {
 
 func newProxy(master *Master) *proxy {
 	return &proxy{master: master, link: link.NewLink(&link.Options{
+		Zone:        master.opts.zone,
 		Codec:       master.opts.codec,
 		Locator:     master.opts.locator,
 		Registry:    master.opts.registry,
@@ -79,9 +80,9 @@ func (p *proxy) LocateGate(ctx context.Context, uid int64) (string, error) {
 	return p.link.LocateGate(ctx, uid)
 }
 
-// LocateNode 定位用户所在节点
-func (p *proxy) LocateNode(ctx context.Context, uid int64) (string, error) {
-	return p.link.LocateNode(ctx, uid)
+// LocateNode 定位用户在节点分组中所在的节点
+func (p *proxy) LocateNode(ctx context.Context, uid int64, group string) (string, error) {
+	return p.link.LocateNode(ctx, uid, group)
 }
 
 // FetchGateList 拉取网关列表
@@ -146,8 +147,10 @@ func (p *proxy) Disconnect(ctx context.Context, uid int64, isForce bool) error {
 }
 
 // 启动监听
-func (p *proxy) watch(ctx context.Context) {
-	p.link.WatchUserLocate(ctx, cluster.Gate, cluster.Node)
+func (p *proxy) watch(ctx context.Context) error {
+	if err := p.link.WatchUserLocate(ctx, cluster.Gate, cluster.Node); err != nil {
+		return err
+	}
 
-	p.link.WatchServiceInstance(ctx, cluster.Gate, cluster.Node)
+	return p.link.WatchServiceInstance(ctx, cluster.Gate, cluster.Node)
 }
@@ -0,0 +1,93 @@
+package node
+
+import "context"
+
+// Channel 集群频道
+// 频道成员由用户所在网关维护，用户断开连接或解绑网关时自动离开频道；
+// 节点不保存频道成员，任一节点均可向频道发布消息，消息仅发布到持有频道成员的网关，由网关按本地的频道成员推送；
+// 定位器未实现locate.ChannelLocator时，消息发布到所有网关
+type Channel interface {
+	// Name 获取频道名称
+	Name() string
+	// Join 加入频道
+	Join(ctx context.Context, uids ...int64) error
+	// Leave 离开频道
+	Leave(ctx context.Context, uids ...int64) error
+	// Publish 发布频道消息，返回推送数量；部分网关发布失败时同时返回推送数量及失败网关的错误
+	Publish(ctx context.Context, message *Message) (int64, error)
+	// Dismiss 解散频道
+	Dismiss(ctx context.Context) error
+}
+
+type channel struct {
+	name string
+	node *Node
+}
+
+func newChannel(node *Node, name string) *channel {
+	return &channel{
+		name: name,
+		node: node,
+	}
+}
+
+// Name 获取频道名称
+func (c *channel) Name() string {
+	return c.name
+}
+
+// Join 加入频道
+func (c *channel) Join(ctx context.Context, uids ...int64) error {
+	groups := make(map[string][]int64)
+	for _, uid := range uids {
+		gid, err := c.node.proxy.LocateGate(ctx, uid)
+		if err != nil {
+			return err
+		}
+		groups[gid] = append(groups[gid], uid)
+	}
+
+	for gid, members := range groups {
+		if err := c.node.proxy.link.JoinChannel(ctx, gid, c.name, members); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Leave 离开频道
+// 未绑定网关的用户已自动离开频道，直接忽略
+func (c *channel) Leave(ctx context.Context, uids ...int64) error {
+	groups := make(map[string][]int64)
+	for _, uid := range uids {
+		gid, err := c.node.proxy.LocateGate(ctx, uid)
+		if err != nil {
+			if err == ErrNotFoundUserSource {
+				continue
+			}
+			return err
+		}
+		groups[gid] = append(groups[gid], uid)
+	}
+
+	for gid, members := range groups {
+		if err := c.node.proxy.link.LeaveChannel(ctx, gid, c.name, members); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Publish 发布频道消息
+func (c *channel) Publish(ctx context.Context, message *Message) (int64, error) {
+	return c.node.proxy.link.PublishChannel(ctx, c.name, message)
+}
+
+// Dismiss 解散频道
+func (c *channel) Dismiss(ctx context.Context) error {
+	c.node.channels.Delete(c.name)
+
+	return c.node.proxy.link.DismissChannel(ctx, c.name)
+}
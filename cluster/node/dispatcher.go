@@ -0,0 +1,129 @@
+package node
+
+import (
+	"github.com/dobyte/due/log"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// DispatchMode 路由分发模式
+type DispatchMode int
+
+const (
+	ShardDispatch    DispatchMode = iota // 分片分发，按用户ID分配到固定分片，同一用户的消息按顺序处理
+	ParallelDispatch                     // 并行分发，消息由并行池中任意协程处理，不保证顺序，仅适用于无状态路由
+)
+
+type dispatcher struct {
+	pending  int64 // 待处理任务数
+	opts     *options
+	shards   []chan func() // 分片任务队列
+	parallel chan func()   // 并行任务队列
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	stopped  bool           // 是否已停止，停止后不再接收任务
+	senders  sync.WaitGroup // 正在入队的任务
+}
+
+func newDispatcher(opts *options) *dispatcher {
+	d := &dispatcher{opts: opts}
+	d.shards = make([]chan func(), opts.shardNum)
+	for i := range d.shards {
+		d.shards[i] = make(chan func(), opts.shardQueueSize)
+	}
+	d.parallel = make(chan func(), opts.parallelQueueSize)
+
+	return d
+}
+
+// 启动分发协程
+func (d *dispatcher) start() {
+	for _, ch := range d.shards {
+		d.wg.Add(1)
+		go d.work(ch)
+	}
+
+	for i := 0; i < d.opts.parallelNum; i++ {
+		d.wg.Add(1)
+		go d.work(d.parallel)
+	}
+}
+
+// 停止分发协程，已入队的任务会在协程退出前处理完毕
+// 先拒绝新的任务并等待正在入队的任务入队完成，再关闭任务队列
+func (d *dispatcher) stop() {
+	d.mu.Lock()
+	d.stopped = true
+	d.mu.Unlock()
+
+	d.senders.Wait()
+
+	for _, ch := range d.shards {
+		close(ch)
+	}
+	close(d.parallel)
+
+	d.wg.Wait()
+}
+
+// 分发任务
+// 分片分发时按key选择分片，并行池未启用时并行分发退化为分片分发；分发器停止后返回ErrNodeNotWorking
+// 入队时不持有锁，队列已满时阻塞等待分发协程处理，避免阻塞分发协程自身的重入分发
+func (d *dispatcher) dispatch(key int64, mode DispatchMode, task func()) error {
+	d.mu.Lock()
+	if d.stopped {
+		d.mu.Unlock()
+		return ErrNodeNotWorking
+	}
+	d.senders.Add(1)
+	d.mu.Unlock()
+
+	defer d.senders.Done()
+
+	atomic.AddInt64(&d.pending, 1)
+
+	if i := d.locate(key, mode); i >= 0 {
+		d.shards[i] <- task
+	} else {
+		d.parallel <- task
+	}
+
+	return nil
+}
+
+// 定位任务所在分片，分发到并行池时返回-1
+func (d *dispatcher) locate(key int64, mode DispatchMode) int {
+	if mode == ParallelDispatch && d.opts.parallelNum > 0 {
+		return -1
+	}
+
+	return int(uint64(key) % uint64(len(d.shards)))
+}
+
+// 待处理任务数
+func (d *dispatcher) pendingNum() int64 {
+	return atomic.LoadInt64(&d.pending)
+}
+
+// 处理任务
+func (d *dispatcher) work(ch chan func()) {
+	defer d.wg.Done()
+
+	for task := range ch {
+		d.run(task)
+	}
+}
+
+// 执行任务，捕获任务中的panic，避免单个任务导致分发协程退出
+func (d *dispatcher) run(task func()) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("dispatch task panic, err: %v\n%s", err, debug.Stack())
+		}
+
+		atomic.AddInt64(&d.pending, -1)
+	}()
+
+	task()
+}
@@ -0,0 +1,61 @@
+package node
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDispatcher_Recover(t *testing.T) {
+	d := newDispatcher(&options{shardNum: 1, shardQueueSize: 2})
+	d.start()
+
+	done := make(chan struct{})
+	d.dispatch(1, ShardDispatch, func() { panic("handler panic") })
+	d.dispatch(1, ShardDispatch, func() { close(done) })
+
+	<-done
+	d.stop()
+
+	if n := d.pendingNum(); n != 0 {
+		t.Fatalf("pending tasks: %d", n)
+	}
+}
+
+func TestDispatcher_DispatchAfterStop(t *testing.T) {
+	d := newDispatcher(&options{shardNum: 1, shardQueueSize: 1})
+	d.start()
+	d.stop()
+
+	if err := d.dispatch(1, ShardDispatch, func() {}); err != ErrNodeNotWorking {
+		t.Fatalf("expected ErrNodeNotWorking after stop, got %v", err)
+	}
+
+	if n := d.pendingNum(); n != 0 {
+		t.Fatalf("pending tasks: %d", n)
+	}
+}
+
+func TestDispatcher_StopWhileDispatching(t *testing.T) {
+	d := newDispatcher(&options{shardNum: 1, shardQueueSize: 1})
+	d.start()
+
+	var handled int32
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if err := d.dispatch(1, ShardDispatch, func() { atomic.AddInt32(&handled, 1) }); err != nil {
+				return
+			}
+		}
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	d.stop()
+	<-done
+
+	if n := d.pendingNum(); n != 0 {
+		t.Fatalf("the dispatched tasks should be handled before stop returns, pending: %d", n)
+	}
+}
@@ -0,0 +1,25 @@
+package node
+
+// Middleware 路由中间件，包装下一个路由处理器并返回新的路由处理器
+type Middleware func(next RouteHandler) RouteHandler
+
+// EventMiddleware 事件中间件，包装下一个事件处理器并返回新的事件处理器
+type EventMiddleware func(next EventHandler) EventHandler
+
+// 按照添加顺序组装路由中间件，先添加的中间件位于外层
+func chainMiddlewares(handler RouteHandler, middlewares ...Middleware) RouteHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// 按照添加顺序组装事件中间件，先添加的中间件位于外层
+func chainEventMiddlewares(handler EventHandler, middlewares ...EventMiddleware) EventHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"github.com/dobyte/due/cluster/node"
+	"github.com/dobyte/due/log"
+	"time"
+)
+
+// AccessLog 记录每个请求的来源及处理耗时
+func AccessLog() node.Middleware {
+	return func(next node.RouteHandler) node.RouteHandler {
+		return func(req node.Request) {
+			start := time.Now()
+
+			next(req)
+
+			log.Infof("route: %d, seq: %d, gid: %s, nid: %s, cid: %d, uid: %d, cost: %v", req.Route(), req.Seq(), req.GID(), req.NID(), req.CID(), req.UID(), time.Since(start))
+		}
+	}
+}
+
+// SlowHandler 记录处理耗时超过阈值的请求
+func SlowHandler(threshold time.Duration) node.Middleware {
+	return func(next node.RouteHandler) node.RouteHandler {
+		return func(req node.Request) {
+			start := time.Now()
+
+			next(req)
+
+			if cost := time.Since(start); cost > threshold {
+				log.Warnf("slow route handler, route: %d, seq: %d, uid: %d, cost: %v, threshold: %v", req.Route(), req.Seq(), req.UID(), cost, threshold)
+			}
+		}
+	}
+}
+
+// SlowEventHandler 记录处理耗时超过阈值的事件
+func SlowEventHandler(threshold time.Duration) node.EventMiddleware {
+	return func(next node.EventHandler) node.EventHandler {
+		return func(gid string, uid int64) {
+			start := time.Now()
+
+			next(gid, uid)
+
+			if cost := time.Since(start); cost > threshold {
+				log.Warnf("slow event handler, gid: %s, uid: %d, cost: %v, threshold: %v", gid, uid, cost, threshold)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package middleware_test
+
+import (
+	"github.com/dobyte/due/cluster/node"
+	"github.com/dobyte/due/cluster/node/middleware"
+	"testing"
+	"time"
+)
+
+func TestAccessLog(t *testing.T) {
+	calls := 0
+	handler := middleware.AccessLog()(func(req node.Request) {
+		calls++
+	})
+
+	handler(testRequest{})
+
+	if calls != 1 {
+		t.Fatalf("the next handler should be called once, got %d", calls)
+	}
+}
+
+func TestSlowHandler(t *testing.T) {
+	calls := 0
+	handler := middleware.SlowHandler(10 * time.Millisecond)(func(req node.Request) {
+		calls++
+		if calls == 2 {
+			time.Sleep(20 * time.Millisecond)
+		}
+	})
+
+	handler(testRequest{})
+	handler(testRequest{})
+
+	if calls != 2 {
+		t.Fatalf("the next handler should be called twice, got %d", calls)
+	}
+}
+
+func TestSlowEventHandler(t *testing.T) {
+	calls := 0
+	handler := middleware.SlowEventHandler(10 * time.Millisecond)(func(gid string, uid int64) {
+		calls++
+	})
+
+	handler("gate", 1)
+
+	if calls != 1 {
+		t.Fatalf("the next handler should be called once, got %d", calls)
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/dobyte/due/cluster/node"
+	"github.com/dobyte/due/log"
+	"runtime/debug"
+)
+
+// Recover 捕获路由处理器中的panic，避免单个请求导致节点崩溃
+func Recover() node.Middleware {
+	return func(next node.RouteHandler) node.RouteHandler {
+		return func(req node.Request) {
+			defer func() {
+				if err := recover(); err != nil {
+					log.Errorf("route handler panic, route: %d, uid: %d, err: %v\n%s", req.Route(), req.UID(), err, debug.Stack())
+				}
+			}()
+
+			next(req)
+		}
+	}
+}
+
+// RecoverEvent 捕获事件处理器中的panic，避免单个事件导致节点崩溃
+func RecoverEvent() node.EventMiddleware {
+	return func(next node.EventHandler) node.EventHandler {
+		return func(gid string, uid int64) {
+			defer func() {
+				if err := recover(); err != nil {
+					log.Errorf("event handler panic, gid: %s, uid: %d, err: %v\n%s", gid, uid, err, debug.Stack())
+				}
+			}()
+
+			next(gid, uid)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package middleware_test
+
+import (
+	"github.com/dobyte/due/cluster/node"
+	"github.com/dobyte/due/cluster/node/middleware"
+	"testing"
+)
+
+// 测试请求，仅实现中间件使用的方法
+type testRequest struct {
+	node.Request
+}
+
+func (testRequest) GID() string { return "gate" }
+
+func (testRequest) NID() string { return "node" }
+
+func (testRequest) CID() int64 { return 1 }
+
+func (testRequest) UID() int64 { return 1 }
+
+func (testRequest) Seq() int32 { return 1 }
+
+func (testRequest) Route() int32 { return 1 }
+
+func TestRecover(t *testing.T) {
+	handler := middleware.Recover()(func(req node.Request) {
+		panic("handler panic")
+	})
+
+	handler(testRequest{})
+}
+
+func TestRecoverEvent(t *testing.T) {
+	handler := middleware.RecoverEvent()(func(gid string, uid int64) {
+		panic("handler panic")
+	})
+
+	handler("gate", 1)
+}
@@ -0,0 +1,128 @@
+package node
+
+import (
+	"github.com/dobyte/due/cluster"
+	"reflect"
+	"testing"
+)
+
+// 记录执行顺序的路由中间件
+func recordMiddleware(name string, trace *[]string) Middleware {
+	return func(next RouteHandler) RouteHandler {
+		return func(req Request) {
+			*trace = append(*trace, name+">")
+			next(req)
+			*trace = append(*trace, name+"<")
+		}
+	}
+}
+
+// 记录执行顺序的事件中间件
+func recordEventMiddleware(name string, trace *[]string) EventMiddleware {
+	return func(next EventHandler) EventHandler {
+		return func(gid string, uid int64) {
+			*trace = append(*trace, name+">")
+			next(gid, uid)
+			*trace = append(*trace, name+"<")
+		}
+	}
+}
+
+func TestMiddleware_Order(t *testing.T) {
+	var trace []string
+
+	n := NewNode(WithID("node"))
+	n.proxy.Use(recordMiddleware("g1", &trace), recordMiddleware("g2", &trace))
+	n.proxy.AddRouteHandler(1, false, func(req Request) {
+		trace = append(trace, "handler")
+	}, recordMiddleware("r1", &trace), recordMiddleware("r2", &trace))
+	n.proxy.SetDefaultRouteHandler(func(req Request) {
+		trace = append(trace, "default")
+	})
+	n.applyMiddlewares()
+
+	n.handleRequest(&request{uid: 1, message: &Message{Route: 1}})
+
+	expected := []string{"g1>", "g2>", "r1>", "r2>", "handler", "r2<", "r1<", "g2<", "g1<"}
+	if !reflect.DeepEqual(trace, expected) {
+		t.Fatalf("unexpected route middleware order: %v", trace)
+	}
+
+	// 默认路由处理器仅应用全局中间件
+	trace = nil
+	n.handleRequest(&request{uid: 1, message: &Message{Route: 2}})
+
+	expected = []string{"g1>", "g2>", "default", "g2<", "g1<"}
+	if !reflect.DeepEqual(trace, expected) {
+		t.Fatalf("unexpected default route middleware order: %v", trace)
+	}
+}
+
+func TestMiddleware_ShortCircuit(t *testing.T) {
+	var trace []string
+
+	// 未绑定用户的请求不再继续处理
+	guard := func(next RouteHandler) RouteHandler {
+		return func(req Request) {
+			if req.UID() == 0 {
+				trace = append(trace, "rejected")
+				return
+			}
+			next(req)
+		}
+	}
+
+	n := NewNode(WithID("node"))
+	n.proxy.Use(recordMiddleware("g1", &trace), guard)
+	n.proxy.AddRouteHandler(1, false, func(req Request) {
+		trace = append(trace, "handler")
+	}, recordMiddleware("r1", &trace))
+	n.applyMiddlewares()
+
+	n.handleRequest(&request{message: &Message{Route: 1}})
+
+	expected := []string{"g1>", "rejected", "g1<"}
+	if !reflect.DeepEqual(trace, expected) {
+		t.Fatalf("the inner middlewares and handler should be skipped: %v", trace)
+	}
+}
+
+func TestEventMiddleware_Order(t *testing.T) {
+	var trace []string
+
+	n := NewNode(WithID("node"))
+	n.proxy.UseEvent(recordEventMiddleware("e1", &trace), recordEventMiddleware("e2", &trace))
+	n.proxy.AddEventListener(cluster.Connect, func(gid string, uid int64) {
+		trace = append(trace, "handler")
+	})
+	n.applyMiddlewares()
+
+	n.handleEvent(&eventEntity{event: cluster.Connect, gid: "gate", uid: 1})
+
+	expected := []string{"e1>", "e2>", "handler", "e2<", "e1<"}
+	if !reflect.DeepEqual(trace, expected) {
+		t.Fatalf("unexpected event middleware order: %v", trace)
+	}
+}
+
+func TestEventMiddleware_ShortCircuit(t *testing.T) {
+	var trace []string
+
+	n := NewNode(WithID("node"))
+	n.proxy.UseEvent(recordEventMiddleware("e1", &trace), func(next EventHandler) EventHandler {
+		return func(gid string, uid int64) {
+			trace = append(trace, "rejected")
+		}
+	})
+	n.proxy.AddEventListener(cluster.Disconnect, func(gid string, uid int64) {
+		trace = append(trace, "handler")
+	})
+	n.applyMiddlewares()
+
+	n.handleEvent(&eventEntity{event: cluster.Disconnect, gid: "gate", uid: 1})
+
+	expected := []string{"e1>", "rejected", "e1<"}
+	if !reflect.DeepEqual(trace, expected) {
+		t.Fatalf("the event handler should be skipped: %v", trace)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"github.com/dobyte/due/cluster"
 	"github.com/dobyte/due/component"
+	"github.com/dobyte/due/errors"
 	"github.com/dobyte/due/log"
 	"github.com/dobyte/due/registry"
 	"github.com/dobyte/due/transport"
 	"github.com/dobyte/due/utils/xnet"
+	"sync"
 	"time"
 )
 
@@ -16,11 +18,17 @@ type RouteHandler func(req Request)
 type EventHandler func(gid string, uid int64)
 
 type routeEntity struct {
-	route    int32        // 路由
-	stateful bool         // 是否有状态
-	handler  RouteHandler // 路由处理器
+	route       int32        // 路由
+	stateful    bool         // 是否有状态
+	sharding    bool         // 是否按用户ID一致性哈希分片
+	mode        DispatchMode // 分发模式
+	handler     RouteHandler // 路由处理器
+	middlewares []Middleware // 路由中间件
 }
 
+// 请求上下文中记录处理分片的键
+type shardContextKey struct{}
+
 type eventEntity struct {
 	event cluster.Event
 	gid   string
@@ -32,15 +40,25 @@ type Node struct {
 	opts                *options
 	ctx                 context.Context
 	cancel              context.CancelFunc
-	chEvent             chan *eventEntity
-	chRequest           chan *request
+	dispatcher          *dispatcher
+	scheduler           *scheduler
+	channels            sync.Map
+	shards              *shards
+	migrationHandler    MigrationHandler
 	routes              map[int32]routeEntity
 	events              map[cluster.Event]EventHandler
 	defaultRouteHandler RouteHandler
+	middlewares         []Middleware
+	eventMiddlewares    []EventMiddleware
 	proxy               *proxy
 	instance            *registry.ServiceInstance
 	rpc                 transport.Server
+	rw                  sync.RWMutex
 	state               cluster.State
+	metadata            map[string]string // 实例元数据，变更时整体替换
+	autoBusy            bool              // 是否因待处理消息过多自动切换为繁忙状态
+	started             bool              // 是否已启动
+	restarting          bool              // 是否正在热重启
 }
 
 func NewNode(opts ...Option) *Node {
@@ -53,10 +71,12 @@ func NewNode(opts ...Option) *Node {
 	n.opts = o
 	n.routes = make(map[int32]routeEntity)
 	n.events = make(map[cluster.Event]EventHandler, 3)
-	n.chEvent = make(chan *eventEntity, 4096)
-	n.chRequest = make(chan *request, 4096)
+	n.dispatcher = newDispatcher(o)
+	n.scheduler = newScheduler(n)
+	n.shards = newShards()
 	n.proxy = newProxy(n)
 	n.state = cluster.Shut
+	n.metadata = o.metadata
 	n.ctx, n.cancel = context.WithCancel(o.ctx)
 
 	return n
@@ -68,52 +88,123 @@ func (n *Node) Name() string {
 }
 
 // Init 初始化节点
-func (n *Node) Init() {
+func (n *Node) Init() error {
 	if n.opts.id == "" {
-		log.Fatal("instance id can not be empty")
+		return errors.New("instance id can not be empty")
 	}
 
 	if n.opts.codec == nil {
-		log.Fatal("codec component is not injected")
+		return errors.New("codec component is not injected")
 	}
 
 	if n.opts.locator == nil {
-		log.Fatal("locator component is not injected")
+		return errors.New("locator component is not injected")
 	}
 
 	if n.opts.registry == nil {
-		log.Fatal("registry component is not injected")
+		return errors.New("registry component is not injected")
 	}
 
 	if n.opts.transporter == nil {
-		log.Fatal("rpc component is not injected")
+		return errors.New("rpc component is not injected")
 	}
+
+	return nil
 }
 
 // Start 启动节点
-func (n *Node) Start() {
+func (n *Node) Start() error {
+	n.applyMiddlewares()
+
 	n.state = cluster.Work
 
-	n.startTransportServer()
+	if err := n.startTransportServer(); err != nil {
+		n.state = cluster.Shut
+		return err
+	}
 
-	n.registerServiceInstance()
+	if err := n.registerServiceInstance(); err != nil {
+		n.stopTransportServer()
+		n.state = cluster.Shut
+		return err
+	}
+
+	n.proxy.link.OnRebalance(n.rebalance)
 
-	n.proxy.watch(n.ctx)
+	if err := n.proxy.watch(n.ctx); err != nil {
+		n.deregisterServiceInstance()
+		n.stopTransportServer()
+		n.state = cluster.Shut
+		return err
+	}
 
-	go n.dispatch()
+	n.dispatcher.start()
+
+	n.started = true
+
+	if n.opts.busyThreshold > 0 {
+		go n.monitor()
+	}
+
+	if n.opts.shardIdleTimeout > 0 {
+		go n.sweep()
+	}
 
 	n.debugPrint()
+
+	return nil
 }
 
-// Destroy 销毁网关服务器
-func (n *Node) Destroy() {
+// ShutdownTimeout 组件销毁超时时间，未设置时为排空超时时间加上解注册等收尾操作的预留时间
+func (n *Node) ShutdownTimeout() time.Duration {
+	if timeout := n.Base.ShutdownTimeout(); timeout > 0 {
+		return timeout
+	}
+
+	return n.opts.drainTimeout + 10*time.Second
+}
+
+// Restart 重启组件
+// 热重启时新进程可能以相同实例ID注册，销毁时不再以挂起状态重新注册，也不再等待有状态用户解绑
+func (n *Node) Restart() error {
+	n.restarting = true
+
+	return nil
+}
+
+// Destroy 销毁节点服务器
+// 节点先将服务实例更新为挂起状态，使无状态路由不再分配到当前节点，并停止所有定时器，
+// 然后在排空超时时间内等待有状态用户解绑及待处理消息处理完毕，最后解注册并关闭传输服务器；未启动成功的节点仅释放资源
+func (n *Node) Destroy() error {
+	if !n.started {
+		n.proxy.link.Close()
+		n.cancel()
+		return nil
+	}
+
+	if !n.restarting {
+		if err := n.setState(cluster.Hang); err != nil {
+			log.Errorf("the node service instance hang failed: %v", err)
+		}
+	}
+
+	n.scheduler.close()
+
+	n.drain()
+
 	n.deregisterServiceInstance()
 
 	n.stopTransportServer()
 
-	close(n.chEvent)
-	close(n.chRequest)
+	n.rw.Lock()
+	n.state = cluster.Shut
+	n.rw.Unlock()
+
+	n.dispatcher.stop()
+	n.proxy.link.Close()
 	n.cancel()
+
+	return nil
 }
 
 // Proxy 获取节点代理
@@ -121,53 +212,217 @@ func (n *Node) Proxy() Proxy {
 	return n.proxy
 }
 
-// 分发处理消息
-func (n *Node) dispatch() {
+// 处理事件，用户断开连接后移出分片用户记录
+func (n *Node) handleEvent(entity *eventEntity) {
+	if entity.event == cluster.Disconnect {
+		n.shards.remove(entity.uid)
+	}
+
+	handler, ok := n.events[entity.event]
+	if !ok {
+		log.Warnf("event does not register handler function, event: %v", entity.event)
+		return
+	}
+
+	handler(entity.gid, entity.uid)
+}
+
+// 处理请求
+func (n *Node) handleRequest(req *request) {
+	route, ok := n.routes[req.Route()]
+	if ok {
+		route.handler(req)
+	} else if n.defaultRouteHandler != nil {
+		n.defaultRouteHandler(req)
+	} else {
+		log.Warnf("message routing does not register handler function, route: %v", req.Route())
+	}
+}
+
+// 等待有状态用户解绑及待处理消息处理完毕
+// 热重启时有状态用户由新进程继续服务，仅等待待处理消息处理完毕
+func (n *Node) drain() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	timer := time.NewTimer(n.opts.drainTimeout)
+	defer timer.Stop()
+
 	for {
-		select {
-		case entity, ok := <-n.chEvent:
-			if !ok {
-				return
-			}
+		var (
+			users int
+			err   error
+		)
 
-			handler, ok := n.events[entity.event]
-			if !ok {
-				log.Warnf("event does not register handler function, event: %v", entity.event)
-				continue
-			}
+		if !n.restarting {
+			users, err = n.proxy.link.CountNodeUsers(n.ctx, n.opts.id)
+		}
 
-			handler(entity.gid, entity.uid)
-		case req, ok := <-n.chRequest:
-			if !ok {
-				return
-			}
+		pending := n.dispatcher.pendingNum()
+		if err == nil && users == 0 && pending == 0 {
+			return
+		}
 
-			route, ok := n.routes[req.Route()]
-			if ok {
-				route.handler(req)
-			} else if n.defaultRouteHandler != nil {
-				n.defaultRouteHandler(req)
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			if err != nil {
+				log.Warnf("the node drain timeout after %v, count bound users failed: %v, %d messages pending", n.opts.drainTimeout, err, pending)
 			} else {
-				log.Warnf("message routing does not register handler function, route: %v", req.Route())
+				log.Warnf("the node drain timeout after %v, %d users bound and %d messages pending", n.opts.drainTimeout, users, pending)
 			}
+			return
 		}
 	}
 }
 
-// 启动传输服务器
-func (n *Node) startTransportServer() {
-	var err error
+// 监控待处理消息数，超过繁忙阈值时自动切换为繁忙状态，回落到阈值一半以下时恢复为工作状态
+func (n *Node) monitor() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-ticker.C:
+			n.checkBusy(int(n.dispatcher.pendingNum()))
+		}
+	}
+}
+
+// 定期移出空闲超时的分片用户
+func (n *Node) sweep() {
+	ticker := time.NewTicker(n.opts.shardIdleTimeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-ticker.C:
+			n.shards.expire(time.Now().Add(-n.opts.shardIdleTimeout))
+		}
+	}
+}
+
+// 检测节点繁忙状态
+func (n *Node) checkBusy(pending int) {
+	n.rw.Lock()
+	defer n.rw.Unlock()
+
+	switch {
+	case n.state == cluster.Work && pending > n.opts.busyThreshold:
+		if err := n.doSetState(cluster.Busy); err != nil {
+			log.Errorf("the node switch to busy state failed: %v", err)
+			return
+		}
+		n.autoBusy = true
+	case n.state == cluster.Busy && n.autoBusy && pending <= n.opts.busyThreshold/2:
+		if err := n.doSetState(cluster.Work); err != nil {
+			log.Errorf("the node switch to work state failed: %v", err)
+			return
+		}
+		n.autoBusy = false
+	}
+}
+
+// 获取节点状态
+func (n *Node) getState() cluster.State {
+	n.rw.RLock()
+	defer n.rw.RUnlock()
+
+	return n.state
+}
+
+// 设置节点状态
+func (n *Node) setState(state cluster.State) error {
+	switch state {
+	case cluster.Work, cluster.Busy, cluster.Hang:
+	default:
+		return ErrInvalidState
+	}
+
+	n.rw.Lock()
+	defer n.rw.Unlock()
+
+	if n.state == cluster.Shut {
+		return ErrNodeNotWorking
+	}
+
+	n.autoBusy = false
+
+	return n.doSetState(state)
+}
+
+// 设置节点状态并更新服务实例
+func (n *Node) doSetState(state cluster.State) error {
+	if n.state == state {
+		return nil
+	}
+
+	prev := n.state
+	n.state = state
+
+	if err := n.updateServiceInstance(); err != nil {
+		n.state = prev
+		return err
+	}
+
+	return nil
+}
+
+// 设置节点元数据，值为空时删除对应的键
+func (n *Node) setMetadata(metadata map[string]string) error {
+	n.rw.Lock()
+	defer n.rw.Unlock()
+
+	if n.state == cluster.Shut {
+		return ErrNodeNotWorking
+	}
+
+	prev := n.metadata
+	n.metadata = make(map[string]string, len(prev)+len(metadata))
+	for k, v := range prev {
+		n.metadata[k] = v
+	}
+	for k, v := range metadata {
+		if v == "" {
+			delete(n.metadata, k)
+		} else {
+			n.metadata[k] = v
+		}
+	}
+
+	if err := n.updateServiceInstance(); err != nil {
+		n.metadata = prev
+		return err
+	}
+
+	return nil
+}
+
+// 获取节点元数据
+func (n *Node) getMetadata() map[string]string {
+	n.rw.RLock()
+	defer n.rw.RUnlock()
 
+	metadata := make(map[string]string, len(n.metadata))
+	for k, v := range n.metadata {
+		metadata[k] = v
+	}
+
+	return metadata
+}
+
+// 启动传输服务器
+func (n *Node) startTransportServer() (err error) {
 	n.rpc, err = n.opts.transporter.NewNodeServer(&provider{n})
 	if err != nil {
-		log.Fatalf("the transport server build failed: %v", err)
+		return
 	}
 
-	go func() {
-		if err = n.rpc.Start(); err != nil {
-			log.Fatalf("the transport server startup failed: %v", err)
-		}
-	}()
+	return n.rpc.Start()
 }
 
 // 停止RPC服务器
@@ -178,30 +433,49 @@ func (n *Node) stopTransportServer() {
 }
 
 // 注册服务实例
-func (n *Node) registerServiceInstance() {
+func (n *Node) registerServiceInstance() error {
+	n.instance = n.buildServiceInstance()
+
+	ctx, cancel := context.WithTimeout(n.ctx, 10*time.Second)
+	defer cancel()
+
+	return n.opts.registry.Register(ctx, n.instance)
+}
+
+// 更新服务实例
+func (n *Node) updateServiceInstance() error {
+	n.instance = n.buildServiceInstance()
+
+	ctx, cancel := context.WithTimeout(n.ctx, 10*time.Second)
+	defer cancel()
+
+	return n.opts.registry.Update(ctx, n.instance)
+}
+
+// 构建服务实例
+func (n *Node) buildServiceInstance() *registry.ServiceInstance {
 	routes := make([]registry.Route, 0, len(n.routes))
 	for _, entity := range n.routes {
 		routes = append(routes, registry.Route{
 			ID:       entity.route,
 			Stateful: entity.stateful,
+			Sharding: entity.sharding,
 		})
 	}
 
-	n.instance = &registry.ServiceInstance{
+	return &registry.ServiceInstance{
 		ID:       n.opts.id,
 		Name:     string(cluster.Node),
 		Kind:     cluster.Node,
 		Alias:    n.opts.name,
+		Zone:     n.opts.zone,
+		Group:    n.opts.group,
 		State:    n.state,
 		Routes:   routes,
 		Endpoint: n.rpc.Endpoint().String(),
-	}
-
-	ctx, cancel := context.WithTimeout(n.ctx, 10*time.Second)
-	err := n.opts.registry.Register(ctx, n.instance)
-	cancel()
-	if err != nil {
-		log.Fatalf("the node service instance register failed: %v", err)
+		Weight:   n.opts.weight,
+		Version:  n.opts.version,
+		Metadata: n.metadata,
 	}
 }
 
@@ -215,13 +489,34 @@ func (n *Node) deregisterServiceInstance() {
 	}
 }
 
+// 组装中间件
+// 全局中间件位于路由中间件外层，默认路由处理器仅应用全局中间件
+func (n *Node) applyMiddlewares() {
+	for route, entity := range n.routes {
+		middlewares := make([]Middleware, 0, len(n.middlewares)+len(entity.middlewares))
+		middlewares = append(middlewares, n.middlewares...)
+		middlewares = append(middlewares, entity.middlewares...)
+		entity.handler = chainMiddlewares(entity.handler, middlewares...)
+		n.routes[route] = entity
+	}
+
+	if n.defaultRouteHandler != nil {
+		n.defaultRouteHandler = chainMiddlewares(n.defaultRouteHandler, n.middlewares...)
+	}
+
+	for event, handler := range n.events {
+		n.events[event] = chainEventMiddlewares(handler, n.eventMiddlewares...)
+	}
+}
+
 // 添加路由处理器
-func (n *Node) addRouteHandler(route int32, stateful bool, handler RouteHandler) {
+func (n *Node) addRouteHandler(route int32, stateful bool, handler RouteHandler, middlewares ...Middleware) {
 	if n.state == cluster.Shut {
 		n.routes[route] = routeEntity{
-			route:    route,
-			stateful: stateful,
-			handler:  handler,
+			route:       route,
+			stateful:    stateful,
+			handler:     handler,
+			middlewares: middlewares,
 		}
 	} else {
 		log.Warnf("the node server is working, can't add route handler")
@@ -237,6 +532,118 @@ func (n *Node) setDefaultRouteHandler(handler RouteHandler) {
 	}
 }
 
+// 设置路由分发模式
+func (n *Node) setRouteDispatchMode(route int32, mode DispatchMode) {
+	if n.state != cluster.Shut {
+		log.Warnf("the node server is working, can't set route dispatch mode")
+		return
+	}
+
+	entity, ok := n.routes[route]
+	if !ok {
+		log.Warnf("the route handler is not registered, route: %v", route)
+		return
+	}
+
+	if entity.stateful && mode == ParallelDispatch {
+		log.Warnf("the stateful route can't use parallel dispatch mode, route: %v", route)
+		return
+	}
+
+	entity.mode = mode
+	n.routes[route] = entity
+}
+
+// 设置有状态路由按用户ID一致性哈希分片
+func (n *Node) setRouteSharding(route int32) {
+	if n.state != cluster.Shut {
+		log.Warnf("the node server is working, can't set route sharding")
+		return
+	}
+
+	entity, ok := n.routes[route]
+	if !ok {
+		log.Warnf("the route handler is not registered, route: %v", route)
+		return
+	}
+
+	if !entity.stateful {
+		log.Warnf("the stateless route can't use sharding, route: %v", route)
+		return
+	}
+
+	entity.sharding = true
+	n.routes[route] = entity
+}
+
+// 设置分片迁移处理器
+func (n *Node) setMigrationHandler(handler MigrationHandler) {
+	if n.state == cluster.Shut {
+		n.migrationHandler = handler
+	} else {
+		log.Warnf("the node server is working, can't set migration handler")
+	}
+}
+
+// 是否为分片路由
+func (n *Node) checkRouteSharding(route int32) bool {
+	entity, ok := n.routes[route]
+
+	return ok && entity.sharding
+}
+
+// 分片路由的哈希环变化时，将不再归属当前节点的用户迁出
+// 迁移处理器在用户所在分片中执行，与该用户的消息按顺序处理
+func (n *Node) rebalance(route int32) {
+	if !n.checkRouteSharding(route) {
+		return
+	}
+
+	migrated := n.shards.migrate(route, func(uid int64) (string, bool) {
+		nid, err := n.proxy.link.FindShardOwner(route, uid)
+		return nid, err == nil && nid != n.opts.id
+	})
+
+	if len(migrated) == 0 || n.migrationHandler == nil {
+		return
+	}
+
+	n.rw.RLock()
+	defer n.rw.RUnlock()
+
+	if n.state == cluster.Shut {
+		return
+	}
+
+	for uid, nid := range migrated {
+		uid, nid := uid, nid
+		if err := n.dispatcher.dispatch(uid, ShardDispatch, func() {
+			n.migrationHandler(route, uid, nid)
+		}); err != nil {
+			log.Warnf("dispatch migration failed, route: %d, uid: %d, err: %v", route, uid, err)
+			return
+		}
+	}
+}
+
+// 添加全局路由中间件
+func (n *Node) use(middlewares ...Middleware) {
+	if n.state == cluster.Shut {
+		n.middlewares = append(n.middlewares, middlewares...)
+	} else {
+		log.Warnf("the node server is working, can't add middleware")
+	}
+}
+
+// 添加事件中间件
+func (n *Node) useEvent(middlewares ...EventMiddleware) {
+	if n.state == cluster.Shut {
+		n.eventMiddlewares = append(n.eventMiddlewares, middlewares...)
+	} else {
+		log.Warnf("the node server is working, can't add event middleware")
+	}
+}
+
 // 是否为有状态路由
 func (n *Node) checkRouteStateful(route int32) (bool, bool) {
 	if entity, ok := n.routes[route]; ok {
@@ -256,19 +663,73 @@ func (n *Node) addEventListener(event cluster.Event, handler EventHandler) {
 }
 
 // 触发事件
-func (n *Node) trigger(event cluster.Event, gid string, uid int64) {
-	n.chEvent <- &eventEntity{
+// 事件分发到用户所在分片，与该用户的请求按顺序处理，节点停止后返回ErrNodeNotWorking
+func (n *Node) trigger(event cluster.Event, gid string, uid int64) error {
+	entity := &eventEntity{
 		event: event,
 		gid:   gid,
 		uid:   uid,
 	}
+
+	return n.dispatcher.dispatch(uid, ShardDispatch, func() { n.handleEvent(entity) })
 }
 
 // 投递消息给当前节点处理
-func (n *Node) deliver(req *request) {
-	req.ctx = context.Background()
+// 请求上下文中记录处理请求的分片，用于识别路由处理器对当前节点的重入调用，节点停止后返回ErrNodeNotWorking
+func (n *Node) deliver(req *request) error {
+	if req.ctx == nil {
+		req.ctx = context.Background()
+	}
 	req.node = n
-	n.chRequest <- req
+
+	key, mode := n.dispatchKey(req)
+	shard := n.dispatcher.locate(key, mode)
+	req.ctx = context.WithValue(req.ctx, shardContextKey{}, shard)
+
+	return n.dispatcher.dispatch(key, mode, func() { n.handleRequest(req) })
+}
+
+// 获取请求的分发键及分发模式
+// 未绑定用户的请求按连接ID分片，保证同一连接的请求按顺序处理
+func (n *Node) dispatchKey(req *request) (int64, DispatchMode) {
+	mode := ShardDispatch
+	if entity, ok := n.routes[req.Route()]; ok {
+		mode = entity.mode
+	}
+
+	key := req.uid
+	if key == 0 {
+		key = req.cid
+	}
+
+	return key, mode
+}
+
+// 投递同步调用请求并等待路由处理器响应
+// 路由处理器须通过Response或ResponseError响应请求，否则调用方将等待至超时；
+// 路由处理器以请求上下文调用当前节点上同一分片的路由时，请求在当前协程中直接处理，避免分片协程等待自身而死锁
+func (n *Node) invoke(ctx context.Context, req *request) (*transport.Message, error) {
+	if _, ok := n.checkRouteStateful(req.Route()); !ok {
+		return nil, ErrNotFoundRoute
+	}
+
+	req.ctx = ctx
+	req.chReply = make(chan *transport.Message, 1)
+
+	key, mode := n.dispatchKey(req)
+	if shard, ok := ctx.Value(shardContextKey{}).(int); ok && shard >= 0 && shard == n.dispatcher.locate(key, mode) {
+		req.node = n
+		n.handleRequest(req)
+	} else if err := n.deliver(req); err != nil {
+		return nil, err
+	}
+
+	select {
+	case reply := <-req.chReply:
+		return reply, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 func (n *Node) debugPrint() {
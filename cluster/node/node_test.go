@@ -0,0 +1,294 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/encoding/json"
+	"github.com/dobyte/due/internal/endpoint"
+	"github.com/dobyte/due/locate"
+	"github.com/dobyte/due/registry"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 支持统计实例用户数量的内存定位器
+type memLocator struct {
+	mu        sync.Mutex
+	locations map[string]string
+}
+
+func newMemLocator() *memLocator {
+	return &memLocator{locations: make(map[string]string)}
+}
+
+func locationKey(uid int64, kind cluster.Kind, group string) string {
+	return fmt.Sprintf("%s:%s:%d", kind, group, uid)
+}
+
+func (l *memLocator) Get(ctx context.Context, uid int64, kind cluster.Kind, group string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.locations[locationKey(uid, kind, group)], nil
+}
+
+func (l *memLocator) Set(ctx context.Context, uid int64, kind cluster.Kind, group string, insID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.locations[locationKey(uid, kind, group)] = insID
+
+	return nil
+}
+
+func (l *memLocator) Rem(ctx context.Context, uid int64, kind cluster.Kind, group string, insID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := locationKey(uid, kind, group)
+	if l.locations[key] == insID {
+		delete(l.locations, key)
+	}
+
+	return nil
+}
+
+func (l *memLocator) Watch(ctx context.Context, kinds ...cluster.Kind) (locate.Watcher, error) {
+	return nil, nil
+}
+
+func (l *memLocator) Count(ctx context.Context, kind cluster.Kind, insID string) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := int64(0)
+	for key, id := range l.locations {
+		if id == insID && strings.HasPrefix(key, string(kind)+":") {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// 记录服务实例状态变更的内存注册器
+type memRegistry struct {
+	mu           sync.Mutex
+	states       []cluster.State
+	deregistered bool
+}
+
+func (r *memRegistry) Register(ctx context.Context, ins *registry.ServiceInstance) error {
+	return nil
+}
+
+func (r *memRegistry) Deregister(ctx context.Context, ins *registry.ServiceInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deregistered = true
+
+	return nil
+}
+
+func (r *memRegistry) Update(ctx context.Context, ins *registry.ServiceInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.states = append(r.states, ins.State)
+
+	return nil
+}
+
+func (r *memRegistry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	return nil, nil
+}
+
+func (r *memRegistry) Services(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	return nil, nil
+}
+
+func (r *memRegistry) snapshot() ([]cluster.State, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]cluster.State(nil), r.states...), r.deregistered
+}
+
+// 空传输服务器
+type testServer struct{}
+
+func (testServer) Addr() string { return "127.0.0.1:0" }
+
+func (testServer) Scheme() string { return "test" }
+
+func (testServer) Endpoint() *endpoint.Endpoint {
+	return endpoint.NewEndpoint("test", "127.0.0.1:0", false)
+}
+
+func (testServer) Start() error { return nil }
+
+func (testServer) Stop() error { return nil }
+
+// 创建一个已处于工作状态的节点，跳过传输服务器及注册中心的启动流程
+func newWorkingNode(locator *memLocator, reg *memRegistry, opts ...Option) *Node {
+	opts = append([]Option{
+		WithID("node"),
+		WithCodec(json.NewCodec()),
+		WithLocator(locator),
+		WithRegistry(reg),
+	}, opts...)
+
+	n := NewNode(opts...)
+	n.rpc = testServer{}
+	n.state = cluster.Work
+	n.instance = n.buildServiceInstance()
+	n.dispatcher.start()
+	n.started = true
+
+	return n
+}
+
+func TestNode_DestroyBeforeStart(t *testing.T) {
+	if err := NewNode(WithID("node")).Destroy(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNode_DestroyDrain(t *testing.T) {
+	ctx := context.Background()
+	locator := newMemLocator()
+	reg := &memRegistry{}
+	n := newWorkingNode(locator, reg, WithDrainTimeout(5*time.Second))
+
+	// 其他节点实例绑定的有状态用户同样须等待解绑
+	if err := locator.Set(ctx, 1, cluster.Node, "", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- n.Destroy()
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("the node should wait for the bound users to unbind")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	states, deregistered := reg.snapshot()
+	if len(states) != 1 || states[0] != cluster.Hang {
+		t.Fatalf("the node should hang before draining, got %v", states)
+	}
+	if deregistered {
+		t.Fatal("the node should not deregister before draining")
+	}
+
+	if err := locator.Rem(ctx, 1, cluster.Node, "", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the node should finish draining after the users unbind")
+	}
+
+	if _, deregistered = reg.snapshot(); !deregistered {
+		t.Fatal("the node should deregister after draining")
+	}
+}
+
+func TestNode_DrainTimeout(t *testing.T) {
+	ctx := context.Background()
+	locator := newMemLocator()
+	n := newWorkingNode(locator, &memRegistry{}, WithDrainTimeout(200*time.Millisecond))
+
+	if err := locator.Set(ctx, 1, cluster.Node, "", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+
+	if err := n.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+
+	if time.Since(start) > time.Second {
+		t.Fatal("the node should give up draining after the drain timeout")
+	}
+}
+
+func TestNode_BusyThreshold(t *testing.T) {
+	reg := &memRegistry{}
+	n := NewNode(
+		WithID("node"),
+		WithCodec(json.NewCodec()),
+		WithLocator(newMemLocator()),
+		WithRegistry(reg),
+		WithBusyThreshold(4),
+		WithShardNum(1),
+		WithShardQueueSize(8),
+	)
+	n.rpc = testServer{}
+	n.state = cluster.Work
+
+	// 分发协程未启动，待处理消息堆积在队列中
+	for i := 0; i < 5; i++ {
+		n.dispatcher.dispatch(1, ShardDispatch, func() {})
+	}
+
+	n.checkBusy(int(n.dispatcher.pendingNum()))
+
+	if state := n.getState(); state != cluster.Busy {
+		t.Fatalf("the node should be busy when the queue depth exceeds the threshold, got %v", state)
+	}
+
+	n.checkBusy(3)
+
+	if state := n.getState(); state != cluster.Busy {
+		t.Fatalf("the node should stay busy above half of the threshold, got %v", state)
+	}
+
+	n.dispatcher.start()
+	n.dispatcher.stop()
+
+	n.checkBusy(int(n.dispatcher.pendingNum()))
+
+	if state := n.getState(); state != cluster.Work {
+		t.Fatalf("the node should resume working after the queue drains, got %v", state)
+	}
+
+	if states, _ := reg.snapshot(); len(states) != 2 || states[0] != cluster.Busy || states[1] != cluster.Work {
+		t.Fatalf("unexpected state updates: %v", states)
+	}
+}
+
+func TestNode_BusyThreshold_Manual(t *testing.T) {
+	n := NewNode(
+		WithID("node"),
+		WithCodec(json.NewCodec()),
+		WithLocator(newMemLocator()),
+		WithRegistry(&memRegistry{}),
+		WithBusyThreshold(4),
+	)
+	n.rpc = testServer{}
+	n.state = cluster.Work
+
+	if err := n.setState(cluster.Busy); err != nil {
+		t.Fatal(err)
+	}
+
+	n.checkBusy(0)
+
+	if state := n.getState(); state != cluster.Busy {
+		t.Fatalf("the manually set busy state should be kept, got %v", state)
+	}
+}
@@ -13,46 +13,83 @@ import (
 	"github.com/dobyte/due/locate"
 	"github.com/dobyte/due/registry"
 	"github.com/dobyte/due/transport"
+	"github.com/dobyte/due/utils/xconv"
 	"github.com/dobyte/due/utils/xuuid"
+	"runtime"
 	"time"
 )
 
 const (
-	defaultName    = "node"          // 默认节点名称
-	defaultCodec   = "proto"         // 默认编解码器名称
-	defaultTimeout = 3 * time.Second // 默认超时时间
+	defaultName              = "node"           // 默认节点名称
+	defaultCodec             = "proto"          // 默认编解码器名称
+	defaultTimeout           = 3 * time.Second  // 默认超时时间
+	defaultDrainTimeout      = 30 * time.Second // 默认排空超时时间
+	defaultShardNum          = 1                // 默认分片数
+	defaultShardQueueSize    = 4096             // 默认分片队列长度
+	defaultShardIdleTimeout  = 10 * time.Minute // 默认分片用户空闲超时时间
+	defaultParallelQueueSize = 4096             // 默认并行队列长度
 )
 
 const (
-	defaultIDKey        = "config.cluster.node.id"
-	defaultNameKey      = "config.cluster.node.name"
-	defaultCodecKey     = "config.cluster.node.codec"
-	defaultTimeoutKey   = "config.cluster.node.timeout"
-	defaultEncryptorKey = "config.cluster.node.encryptor"
-	defaultDecryptorKey = "config.cluster.node.decryptor"
+	defaultIDKey                = "config.cluster.node.id"
+	defaultNameKey              = "config.cluster.node.name"
+	defaultZoneKey              = "config.cluster.node.zone"
+	defaultGroupKey             = "config.cluster.node.group"
+	defaultCodecKey             = "config.cluster.node.codec"
+	defaultTimeoutKey           = "config.cluster.node.timeout"
+	defaultEncryptorKey         = "config.cluster.node.encryptor"
+	defaultDecryptorKey         = "config.cluster.node.decryptor"
+	defaultDrainTimeoutKey      = "config.cluster.node.drainTimeout"
+	defaultBusyThresholdKey     = "config.cluster.node.busyThreshold"
+	defaultShardNumKey          = "config.cluster.node.shardNum"
+	defaultShardQueueSizeKey    = "config.cluster.node.shardQueueSize"
+	defaultShardIdleTimeoutKey  = "config.cluster.node.shardIdleTimeout"
+	defaultParallelNumKey       = "config.cluster.node.parallelNum"
+	defaultParallelQueueSizeKey = "config.cluster.node.parallelQueueSize"
+	defaultWeightKey            = "config.cluster.node.weight"
+	defaultVersionKey           = "config.cluster.node.version"
+	defaultMetadataKey          = "config.cluster.node.metadata"
 )
 
 type Option func(o *options)
 
 type options struct {
-	id          string                // 实例ID
-	name        string                // 实例名称
-	ctx         context.Context       // 上下文
-	codec       encoding.Codec        // 编解码器
-	timeout     time.Duration         // RPC调用超时时间
-	locator     locate.Locator        // 用户定位器
-	registry    registry.Registry     // 服务注册器
-	transporter transport.Transporter // 消息传输器
-	encryptor   crypto.Encryptor      // 消息加密器
-	decryptor   crypto.Decryptor      // 消息解密器
+	id                string                // 实例ID
+	name              string                // 实例名称
+	zone              string                // 所在游戏区，不同游戏区的实例互不可见，为空时为默认游戏区
+	group             string                // 实例分组，用户在每个分组中绑定一个节点，为空时为默认分组
+	ctx               context.Context       // 上下文
+	codec             encoding.Codec        // 编解码器
+	timeout           time.Duration         // RPC调用超时时间
+	locator           locate.Locator        // 用户定位器
+	registry          registry.Registry     // 服务注册器
+	transporter       transport.Transporter // 消息传输器
+	encryptor         crypto.Encryptor      // 消息加密器
+	decryptor         crypto.Decryptor      // 消息解密器
+	drainTimeout      time.Duration         // 排空超时时间
+	busyThreshold     int                   // 繁忙阈值，待处理消息数超过该值时节点自动切换为繁忙状态，小于等于0时不启用
+	shardNum          int                   // 分片数，每个分片由一个协程按顺序处理
+	shardQueueSize    int                   // 分片队列长度
+	shardIdleTimeout  time.Duration         // 分片用户空闲超时时间，小于等于0时不按空闲时间移出
+	parallelNum       int                   // 并行池协程数，为0时不启用并行池
+	parallelQueueSize int                   // 并行队列长度
+	weight            int                   // 实例权重，用于加权轮询负载均衡
+	version           string                // 实例版本
+	metadata          map[string]string     // 实例元数据
 }
 
 func defaultOptions() *options {
 	opts := &options{
-		ctx:     context.Background(),
-		name:    defaultName,
-		codec:   encoding.Invoke(defaultCodec),
-		timeout: defaultTimeout,
+		ctx:               context.Background(),
+		name:              defaultName,
+		codec:             encoding.Invoke(defaultCodec),
+		timeout:           defaultTimeout,
+		drainTimeout:      defaultDrainTimeout,
+		shardNum:          defaultShardNum,
+		shardQueueSize:    defaultShardQueueSize,
+		shardIdleTimeout:  defaultShardIdleTimeout,
+		parallelNum:       runtime.NumCPU(),
+		parallelQueueSize: defaultParallelQueueSize,
 	}
 
 	if id := config.Get(defaultIDKey).String(); id != "" {
@@ -65,6 +102,14 @@ func defaultOptions() *options {
 		opts.name = name
 	}
 
+	if zone := config.Get(defaultZoneKey).String(); zone != "" {
+		opts.zone = zone
+	}
+
+	if group := config.Get(defaultGroupKey).String(); group != "" {
+		opts.group = group
+	}
+
 	if codec := config.Get(defaultCodecKey).String(); codec != "" {
 		opts.codec = encoding.Invoke(codec)
 	}
@@ -81,6 +126,49 @@ func defaultOptions() *options {
 		opts.decryptor = crypto.InvokeDecryptor(decryptor)
 	}
 
+	if timeout := config.Get(defaultDrainTimeoutKey).Int64(); timeout > 0 {
+		opts.drainTimeout = time.Duration(timeout) * time.Second
+	}
+
+	if threshold := config.Get(defaultBusyThresholdKey).Int(); threshold > 0 {
+		opts.busyThreshold = threshold
+	}
+
+	if num := config.Get(defaultShardNumKey).Int(); num > 0 {
+		opts.shardNum = num
+	}
+
+	if size := config.Get(defaultShardQueueSizeKey).Int(); size > 0 {
+		opts.shardQueueSize = size
+	}
+
+	if timeout := config.Get(defaultShardIdleTimeoutKey).Int64(); timeout > 0 {
+		opts.shardIdleTimeout = time.Duration(timeout) * time.Second
+	}
+
+	if num := config.Get(defaultParallelNumKey, -1).Int(); num >= 0 {
+		opts.parallelNum = num
+	}
+
+	if size := config.Get(defaultParallelQueueSizeKey).Int(); size > 0 {
+		opts.parallelQueueSize = size
+	}
+
+	if weight := config.Get(defaultWeightKey).Int(); weight > 0 {
+		opts.weight = weight
+	}
+
+	if version := config.Get(defaultVersionKey).String(); version != "" {
+		opts.version = version
+	}
+
+	if metadata := config.Get(defaultMetadataKey).Map(); len(metadata) > 0 {
+		opts.metadata = make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			opts.metadata[k] = xconv.String(v)
+		}
+	}
+
 	return opts
 }
 
@@ -94,6 +182,16 @@ func WithName(name string) Option {
 	return func(o *options) { o.name = name }
 }
 
+// WithZone 设置所在游戏区
+func WithZone(zone string) Option {
+	return func(o *options) { o.zone = zone }
+}
+
+// WithGroup 设置实例分组
+func WithGroup(group string) Option {
+	return func(o *options) { o.group = group }
+}
+
 // WithCodec 设置编解码器
 func WithCodec(codec encoding.Codec) Option {
 	return func(o *options) { o.codec = codec }
@@ -133,3 +231,57 @@ func WithEncryptor(encryptor crypto.Encryptor) Option {
 func WithDecryptor(decryptor crypto.Decryptor) Option {
 	return func(o *options) { o.decryptor = decryptor }
 }
+
+// WithDrainTimeout 设置排空超时时间，节点关闭时最多等待该时间让有状态用户解绑及待处理消息处理完毕
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.drainTimeout = timeout }
+}
+
+// WithBusyThreshold 设置繁忙阈值，待处理消息数超过该值时节点自动切换为繁忙状态，回落到阈值一半以下时恢复为工作状态
+func WithBusyThreshold(threshold int) Option {
+	return func(o *options) { o.busyThreshold = threshold }
+}
+
+// WithShardNum 设置分片数，同一用户的请求和事件总是由同一分片按顺序处理
+func WithShardNum(num int) Option {
+	return func(o *options) {
+		if num > 0 {
+			o.shardNum = num
+		}
+	}
+}
+
+// WithShardQueueSize 设置分片队列长度
+func WithShardQueueSize(size int) Option {
+	return func(o *options) { o.shardQueueSize = size }
+}
+
+// WithShardIdleTimeout 设置分片用户空闲超时时间，经由分片路由投递的用户超过该时间无消息时不再接收迁移通知，小于等于0时不按空闲时间移出
+func WithShardIdleTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.shardIdleTimeout = timeout }
+}
+
+// WithParallelNum 设置并行池协程数，为0时不启用并行池，并行分发的路由退化为分片分发
+func WithParallelNum(num int) Option {
+	return func(o *options) { o.parallelNum = num }
+}
+
+// WithParallelQueueSize 设置并行队列长度
+func WithParallelQueueSize(size int) Option {
+	return func(o *options) { o.parallelQueueSize = size }
+}
+
+// WithWeight 设置实例权重，用于加权轮询负载均衡
+func WithWeight(weight int) Option {
+	return func(o *options) { o.weight = weight }
+}
+
+// WithVersion 设置实例版本
+func WithVersion(version string) Option {
+	return func(o *options) { o.version = version }
+}
+
+// WithMetadata 设置实例元数据
+func WithMetadata(metadata map[string]string) Option {
+	return func(o *options) { o.metadata = metadata }
+}
@@ -9,9 +9,9 @@ type provider struct {
 	node *Node
 }
 
-// LocateNode 定位用户所在节点
+// LocateNode 定位用户在当前节点分组中所在的节点
 func (p *provider) LocateNode(ctx context.Context, uid int64) (nid string, miss bool, err error) {
-	nid, err = p.node.proxy.LocateNode(ctx, uid)
+	nid, err = p.node.proxy.LocateNode(ctx, uid, p.node.opts.group)
 	if err != nil && err != ErrNotFoundUserSource {
 		return
 	}
@@ -41,9 +41,7 @@ func (p *provider) Trigger(ctx context.Context, args *transport.TriggerArgs) (bo
 		return miss, err
 	}
 
-	p.node.trigger(args.Event, args.GID, args.UID)
-
-	return false, nil
+	return false, p.node.trigger(args.Event, args.GID, args.UID)
 }
 
 // Deliver 投递消息
@@ -54,21 +52,46 @@ func (p *provider) Deliver(ctx context.Context, args *transport.DeliverArgs) (bo
 	}
 
 	if stateful {
-		if args.UID <= 0 {
-			return false, ErrInvalidArgument
+		if miss, err := p.checkSource(ctx, args.Message.Route, args.UID); err != nil {
+			return miss, err
 		}
+	}
 
-		_, miss, err := p.LocateNode(ctx, args.UID)
-		if err != nil {
-			return miss, err
+	return false, p.node.deliver(&request{
+		gid:    args.GID,
+		nid:    args.NID,
+		cid:    args.CID,
+		uid:    args.UID,
+		attrs:  args.Attrs,
+		claims: args.Claims,
+		message: &Message{
+			Seq:   args.Message.Seq,
+			Route: args.Message.Route,
+			Data:  args.Message.Buffer,
+		},
+	})
+}
+
+// Invoke 调用路由并等待响应
+func (p *provider) Invoke(ctx context.Context, args *transport.DeliverArgs) (*transport.Message, bool, error) {
+	stateful, ok := p.CheckRouteStateful(args.Message.Route)
+	if !ok {
+		return nil, false, ErrNotFoundRoute
+	}
+
+	if stateful {
+		if miss, err := p.checkSource(ctx, args.Message.Route, args.UID); err != nil {
+			return nil, miss, err
 		}
 	}
 
-	p.node.deliver(&request{
-		gid: args.GID,
-		nid: args.NID,
-		cid: args.CID,
-		uid: args.UID,
+	reply, err := p.node.invoke(ctx, &request{
+		gid:    args.GID,
+		nid:    args.NID,
+		cid:    args.CID,
+		uid:    args.UID,
+		attrs:  args.Attrs,
+		claims: args.Claims,
 		message: &Message{
 			Seq:   args.Message.Seq,
 			Route: args.Message.Route,
@@ -76,5 +99,31 @@ func (p *provider) Deliver(ctx context.Context, args *transport.DeliverArgs) (bo
 		},
 	})
 
+	return reply, false, err
+}
+
+// 检测有状态路由的用户是否归属当前节点
+// 分片路由按哈希环判断归属，其他有状态路由按用户绑定的节点判断归属
+func (p *provider) checkSource(ctx context.Context, route int32, uid int64) (bool, error) {
+	if uid <= 0 {
+		return false, ErrInvalidArgument
+	}
+
+	if !p.node.checkRouteSharding(route) {
+		_, miss, err := p.LocateNode(ctx, uid)
+		return miss, err
+	}
+
+	nid, err := p.node.proxy.link.FindShardOwner(route, uid)
+	if err != nil {
+		return false, err
+	}
+
+	if nid != p.node.opts.id {
+		return true, ErrNotFoundUserSource
+	}
+
+	p.node.shards.track(route, uid)
+
 	return false, nil
 }
@@ -3,9 +3,14 @@ package node
 import (
 	"context"
 	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/code"
+	"github.com/dobyte/due/errors"
 	"github.com/dobyte/due/internal/link"
 	"github.com/dobyte/due/registry"
+	"github.com/dobyte/due/router"
 	"github.com/dobyte/due/session"
+	"github.com/dobyte/due/transport"
+	"time"
 )
 
 var (
@@ -16,10 +21,17 @@ var (
 	ErrInvalidSessionKind = link.ErrInvalidSessionKind
 	ErrNotFoundUserSource = link.ErrNotFoundUserSource
 	ErrReceiveTargetEmpty = link.ErrReceiveTargetEmpty
+	ErrInvalidState       = errors.New("invalid node state")
+	ErrNodeNotWorking     = errors.New("the node is not working")
+	ErrNotFoundRoute      = router.ErrNotFoundRoute
+	ErrRepeatedReply      = errors.New("the request has been replied")
 )
 
 type (
 	GetIPArgs      = link.GetIPArgs
+	GetAttrsArgs   = link.GetAttrsArgs
+	SetAttrsArgs   = link.SetAttrsArgs
+	DelAttrsArgs   = link.DelAttrsArgs
 	PushArgs       = link.PushArgs
 	MulticastArgs  = link.MulticastArgs
 	BroadcastArgs  = link.BroadcastArgs
@@ -31,6 +43,7 @@ type DeliverArgs struct {
 	NID     string   // 接收节点。存在接收节点时，消息会直接投递给接收节点；不存在接收节点时，系统定位用户所在节点，然后投递。
 	UID     int64    // 用户ID
 	Message *Message // 消息
+	Zone    string   // 游戏区，为空时为当前游戏区，用于跨区投递
 }
 
 type Proxy interface {
@@ -38,38 +51,79 @@ type Proxy interface {
 	GetID() string
 	// GetName 获取当前节点名称
 	GetName() string
-	// AddRouteHandler 添加路由处理器
-	AddRouteHandler(route int32, stateful bool, handler RouteHandler)
+	// GetGroup 获取当前节点分组
+	GetGroup() string
+	// GetState 获取当前节点状态
+	GetState() cluster.State
+	// SetState 设置当前节点状态，仅支持工作、繁忙、挂起三种状态
+	SetState(state cluster.State) error
+	// GetMetadata 获取当前节点元数据
+	GetMetadata() map[string]string
+	// SetMetadata 设置当前节点元数据并更新到注册中心，值为空时删除对应的键，可用于发布节点的实时负载等信息
+	SetMetadata(metadata map[string]string) error
+	// AddRouteHandler 添加路由处理器，middlewares 为仅作用于该路由的中间件
+	AddRouteHandler(route int32, stateful bool, handler RouteHandler, middlewares ...Middleware)
+	// SetRouteDispatchMode 设置路由分发模式，仅无状态路由可以使用并行分发模式
+	SetRouteDispatchMode(route int32, mode DispatchMode)
+	// SetRouteSharding 设置有状态路由按用户ID在工作节点间一致性哈希分片，分片路由的用户无需绑定节点
+	SetRouteSharding(route int32)
+	// SetMigrationHandler 设置分片迁移处理器，节点加入或离开导致用户归属节点变化时调用，用于迁移用户状态
+	SetMigrationHandler(handler MigrationHandler)
 	// SetDefaultRouteHandler 设置默认路由处理器，所有未注册的路由均走默认路由处理器
 	SetDefaultRouteHandler(handler RouteHandler)
+	// AddTypedRouteHandler 添加类型化路由处理器，handler 须形如 func(ctx context.Context, req Request, in *In) (*Out, error)
+	AddTypedRouteHandler(route int32, stateful bool, handler interface{}, middlewares ...Middleware) error
 	// AddEventListener 添加事件监听器
 	AddEventListener(event cluster.Event, handler EventHandler)
+	// Use 添加全局路由中间件，作用于所有路由处理器及默认路由处理器
+	Use(middlewares ...Middleware)
+	// UseEvent 添加事件中间件，作用于所有事件处理器
+	UseEvent(middlewares ...EventMiddleware)
 	// BindGate 绑定网关
 	BindGate(ctx context.Context, gid string, cid, uid int64) error
 	// UnbindGate 绑定网关
 	UnbindGate(ctx context.Context, uid int64) error
-	// BindNode 绑定节点
+	// BindNode 绑定节点，绑定关系存储在节点所在的分组中，默认绑定当前节点
 	BindNode(ctx context.Context, uid int64, nid ...string) error
-	// UnbindNode 解绑节点
+	// UnbindNode 解绑节点，默认解绑当前节点
 	UnbindNode(ctx context.Context, uid int64, nid ...string) error
 	// LocateGate 定位用户所在网关
 	LocateGate(ctx context.Context, uid int64) (string, error)
-	// LocateNode 定位用户所在节点
-	LocateNode(ctx context.Context, uid int64) (string, error)
+	// LocateNode 定位用户在节点分组中所在的节点，group为空时为默认分组
+	LocateNode(ctx context.Context, uid int64, group string) (string, error)
 	// FetchGateList 拉取网关列表
 	FetchGateList(ctx context.Context, states ...cluster.State) ([]*registry.ServiceInstance, error)
 	// FetchNodeList 拉取节点列表
 	FetchNodeList(ctx context.Context, states ...cluster.State) ([]*registry.ServiceInstance, error)
 	// GetIP 获取客户端IP
 	GetIP(ctx context.Context, args *GetIPArgs) (string, error)
+	// GetAttrs 获取会话属性
+	GetAttrs(ctx context.Context, args *GetAttrsArgs) (map[string]string, error)
+	// SetAttrs 设置会话属性
+	SetAttrs(ctx context.Context, args *SetAttrsArgs) error
+	// DelAttrs 删除会话属性
+	DelAttrs(ctx context.Context, args *DelAttrsArgs) error
 	// Push 推送消息
 	Push(ctx context.Context, args *PushArgs) error
 	// Multicast 推送组播消息
 	Multicast(ctx context.Context, args *MulticastArgs) (int64, error)
 	// Broadcast 推送广播消息
 	Broadcast(ctx context.Context, args *BroadcastArgs) (int64, error)
-	// Deliver 投递消息给节点处理
+	// Deliver 投递消息给节点处理，当前节点已停止时返回ErrNodeNotWorking
 	Deliver(ctx context.Context, args *DeliverArgs) error
+	// AfterFunc 延迟执行回调，指定用户ID时回调投递到该用户的分发分片，与该用户的消息按顺序执行
+	AfterFunc(d time.Duration, fn func(), uid ...int64) Timer
+	// Every 按固定间隔执行回调，指定用户ID时回调投递到该用户的分发分片，与该用户的消息按顺序执行
+	Every(d time.Duration, fn func(), uid ...int64) Timer
+	// Cron 按cron表达式执行回调，指定用户ID时回调投递到该用户的分发分片，与该用户的消息按顺序执行
+	Cron(spec string, fn func(), uid ...int64) (Timer, error)
+	// Channel 获取集群频道，频道不存在时创建
+	Channel(name string) Channel
+	// Call 调用节点路由并等待响应，响应消息将解析到reply中
+	// 未设置截止时间时使用节点的RPC调用超时时间；路由处理器以ResponseError响应时，返回携带对应错误码的错误
+	// 路由处理器调用当前节点时须传入请求上下文或其派生上下文，同一分片的请求将在当前协程中直接处理；
+	// 仅由默认路由处理器处理的路由未注册到服务发现中，须指定接收节点调用；调用已停止的当前节点时返回ErrNodeNotWorking
+	Call(ctx context.Context, args *DeliverArgs, reply interface{}) error
 	// Response 响应消息
 	Response(ctx context.Context, req Request, message interface{}) error
 	// Disconnect 断开连接
@@ -83,6 +137,7 @@ type proxy struct {
 
 func newProxy(node *Node) *proxy {
 	return &proxy{node: node, link: link.NewLink(&link.Options{
+		Zone:        node.opts.zone,
 		NID:         node.opts.id,
 		Codec:       node.opts.codec,
 		Locator:     node.opts.locator,
@@ -102,9 +157,65 @@ func (p *proxy) GetName() string {
 	return p.node.opts.name
 }
 
-// AddRouteHandler 添加路由处理器
-func (p *proxy) AddRouteHandler(route int32, stateful bool, handler RouteHandler) {
-	p.node.addRouteHandler(route, stateful, handler)
+// GetGroup 获取当前节点分组
+func (p *proxy) GetGroup() string {
+	return p.node.opts.group
+}
+
+// GetState 获取当前节点状态
+func (p *proxy) GetState() cluster.State {
+	return p.node.getState()
+}
+
+// SetState 设置当前节点状态，仅支持工作、繁忙、挂起三种状态
+// 状态变更会重新注册服务实例，手动设置状态后将不再自动恢复因繁忙阈值切换的状态
+func (p *proxy) SetState(state cluster.State) error {
+	return p.node.setState(state)
+}
+
+// GetMetadata 获取当前节点元数据
+func (p *proxy) GetMetadata() map[string]string {
+	return p.node.getMetadata()
+}
+
+// SetMetadata 设置当前节点元数据并更新到注册中心
+func (p *proxy) SetMetadata(metadata map[string]string) error {
+	return p.node.setMetadata(metadata)
+}
+
+// AddRouteHandler 添加路由处理器，middlewares 为仅作用于该路由的中间件
+func (p *proxy) AddRouteHandler(route int32, stateful bool, handler RouteHandler, middlewares ...Middleware) {
+	p.node.addRouteHandler(route, stateful, handler, middlewares...)
+}
+
+// AddTypedRouteHandler 添加类型化路由处理器，handler 须形如 func(ctx context.Context, req Request, in *In) (*Out, error)
+// 框架使用节点的解密器及编解码器解析请求消息，调用处理器后将返回的消息作为响应
+// 处理器返回错误时，以错误中携带的错误码向请求方响应错误，未携带错误码时使用code.InternalError
+func (p *proxy) AddTypedRouteHandler(route int32, stateful bool, handler interface{}, middlewares ...Middleware) error {
+	h, err := newTypedRouteHandler(handler)
+	if err != nil {
+		return err
+	}
+
+	p.node.addRouteHandler(route, stateful, h, middlewares...)
+
+	return nil
+}
+
+// SetRouteDispatchMode 设置路由分发模式，仅无状态路由可以使用并行分发模式
+// 路由默认使用分片分发模式，需在添加路由处理器之后设置
+func (p *proxy) SetRouteDispatchMode(route int32, mode DispatchMode) {
+	p.node.setRouteDispatchMode(route, mode)
+}
+
+// SetRouteSharding 设置有状态路由按用户ID一致性哈希分片
+func (p *proxy) SetRouteSharding(route int32) {
+	p.node.setRouteSharding(route)
+}
+
+// SetMigrationHandler 设置分片迁移处理器
+func (p *proxy) SetMigrationHandler(handler MigrationHandler) {
+	p.node.setMigrationHandler(handler)
 }
 
 // SetDefaultRouteHandler 设置默认路由处理器，所有未注册的路由均走默认路由处理器
@@ -117,6 +228,17 @@ func (p *proxy) AddEventListener(event cluster.Event, handler EventHandler) {
 	p.node.addEventListener(event, handler)
 }
 
+// Use 添加全局路由中间件，作用于所有路由处理器及默认路由处理器
+// 中间件按照添加顺序由外向内执行，全局中间件位于路由中间件外层
+func (p *proxy) Use(middlewares ...Middleware) {
+	p.node.use(middlewares...)
+}
+
+// UseEvent 添加事件中间件，作用于所有事件处理器
+func (p *proxy) UseEvent(middlewares ...EventMiddleware) {
+	p.node.useEvent(middlewares...)
+}
+
 // BindGate 绑定网关
 func (p *proxy) BindGate(ctx context.Context, gid string, cid, uid int64) error {
 	return p.link.BindGate(ctx, gid, cid, uid)
@@ -128,15 +250,16 @@ func (p *proxy) UnbindGate(ctx context.Context, uid int64) error {
 }
 
 // BindNode 绑定节点
-// 单个用户只能被绑定到某一台节点服务器上，多次绑定会直接覆盖上次绑定
+// 单个用户在每个节点分组中只能被绑定到某一台节点服务器上，同一分组中多次绑定会直接覆盖上次绑定
 // 绑定操作会通过发布订阅方式同步到网关服务器和其他相关节点服务器上
-// nid 为需要绑定的节点ID，默认绑定到当前节点上
+// nid 为需要绑定的节点ID，默认绑定到当前节点上，绑定关系存储在该节点所在的分组中
 func (p *proxy) BindNode(ctx context.Context, uid int64, nid ...string) error {
-	if len(nid) == 0 || nid[0] == "" {
-		return p.link.BindNode(ctx, uid, p.node.opts.id)
-	} else {
-		return p.link.BindNode(ctx, uid, nid[0])
+	id, group, err := p.resolveNode(nid...)
+	if err != nil {
+		return err
 	}
+
+	return p.link.BindNode(ctx, uid, group, id)
 }
 
 // UnbindNode 解绑节点
@@ -144,11 +267,34 @@ func (p *proxy) BindNode(ctx context.Context, uid int64, nid ...string) error {
 // 解绑操作会通过发布订阅方式同步到网关服务器和其他相关节点服务器上
 // nid 为需要解绑的节点ID，默认解绑当前节点
 func (p *proxy) UnbindNode(ctx context.Context, uid int64, nid ...string) error {
-	if len(nid) == 0 || nid[0] == "" {
-		return p.link.UnbindNode(ctx, uid, p.node.opts.id)
-	} else {
-		return p.link.UnbindNode(ctx, uid, nid[0])
+	id, group, err := p.resolveNode(nid...)
+	if err != nil {
+		return err
+	}
+
+	if err = p.link.UnbindNode(ctx, uid, group, id); err != nil {
+		return err
+	}
+
+	if id == p.node.opts.id {
+		p.node.shards.remove(uid)
 	}
+
+	return nil
+}
+
+// 解析节点ID及其所在分组，未指定节点ID时为当前节点
+func (p *proxy) resolveNode(nid ...string) (string, string, error) {
+	if len(nid) == 0 || nid[0] == "" || nid[0] == p.node.opts.id {
+		return p.node.opts.id, p.node.opts.group, nil
+	}
+
+	group, err := p.link.FindNodeGroup(nid[0])
+	if err != nil {
+		return "", "", err
+	}
+
+	return nid[0], group, nil
 }
 
 // LocateGate 定位用户所在网关
@@ -156,9 +302,9 @@ func (p *proxy) LocateGate(ctx context.Context, uid int64) (string, error) {
 	return p.link.LocateGate(ctx, uid)
 }
 
-// LocateNode 定位用户所在节点
-func (p *proxy) LocateNode(ctx context.Context, uid int64) (string, error) {
-	return p.link.LocateNode(ctx, uid)
+// LocateNode 定位用户在节点分组中所在的节点
+func (p *proxy) LocateNode(ctx context.Context, uid int64, group string) (string, error) {
+	return p.link.LocateNode(ctx, uid, group)
 }
 
 // FetchGateList 拉取网关列表
@@ -176,6 +322,21 @@ func (p *proxy) GetIP(ctx context.Context, args *GetIPArgs) (string, error) {
 	return p.link.GetIP(ctx, args)
 }
 
+// GetAttrs 获取会话属性
+func (p *proxy) GetAttrs(ctx context.Context, args *GetAttrsArgs) (map[string]string, error) {
+	return p.link.GetAttrs(ctx, args)
+}
+
+// SetAttrs 设置会话属性
+func (p *proxy) SetAttrs(ctx context.Context, args *SetAttrsArgs) error {
+	return p.link.SetAttrs(ctx, args)
+}
+
+// DelAttrs 删除会话属性
+func (p *proxy) DelAttrs(ctx context.Context, args *DelAttrsArgs) error {
+	return p.link.DelAttrs(ctx, args)
+}
+
 // Push 推送消息
 func (p *proxy) Push(ctx context.Context, args *PushArgs) error {
 	return p.link.Push(ctx, args)
@@ -204,20 +365,111 @@ func (p *proxy) Deliver(ctx context.Context, args *DeliverArgs) error {
 			NID:     args.NID,
 			UID:     args.UID,
 			Message: message,
+			Zone:    args.Zone,
 		})
 	}
 
-	p.node.deliver(&request{
+	return p.node.deliver(&request{
 		nid:     args.NID,
 		uid:     args.UID,
 		message: message,
 	})
+}
 
-	return nil
+// AfterFunc 延迟执行回调
+func (p *proxy) AfterFunc(d time.Duration, fn func(), uid ...int64) Timer {
+	return p.node.scheduler.afterFunc(d, fn, timerKey(uid))
+}
+
+// Every 按固定间隔执行回调
+func (p *proxy) Every(d time.Duration, fn func(), uid ...int64) Timer {
+	return p.node.scheduler.every(d, fn, timerKey(uid))
+}
+
+// Cron 按cron表达式执行回调
+func (p *proxy) Cron(spec string, fn func(), uid ...int64) (Timer, error) {
+	return p.node.scheduler.cron(spec, fn, timerKey(uid))
+}
+
+// Channel 获取集群频道，频道不存在时创建
+func (p *proxy) Channel(name string) Channel {
+	if c, ok := p.node.channels.Load(name); ok {
+		return c.(*channel)
+	}
+
+	c, _ := p.node.channels.LoadOrStore(name, newChannel(p.node, name))
+
+	return c.(*channel)
+}
+
+// Call 调用节点路由并等待响应
+func (p *proxy) Call(ctx context.Context, args *DeliverArgs, reply interface{}) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.node.opts.timeout)
+		defer cancel()
+	}
+
+	message := &Message{
+		Seq:   args.Message.Seq,
+		Route: args.Message.Route,
+		Data:  args.Message.Data,
+	}
+
+	var (
+		res *transport.Message
+		err error
+	)
+
+	if args.NID != p.GetID() {
+		res, err = p.link.Invoke(ctx, &link.DeliverArgs{
+			NID:     args.NID,
+			UID:     args.UID,
+			Message: message,
+			Zone:    args.Zone,
+		})
+	} else {
+		res, err = p.node.invoke(ctx, &request{
+			nid:     args.NID,
+			uid:     args.UID,
+			message: message,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if res.Route == cluster.ErrorRoute {
+		r := &cluster.ErrorReply{}
+		if err = r.Unmarshal(res.Buffer); err != nil {
+			return err
+		}
+		return errors.NewError(r.Message, code.NewCode(int(r.Code), r.Message, nil))
+	}
+
+	if reply == nil || len(res.Buffer) == 0 {
+		return nil
+	}
+
+	if v, ok := reply.(*[]byte); ok {
+		*v = res.Buffer
+		return nil
+	}
+
+	return p.node.opts.codec.Unmarshal(res.Buffer, reply)
 }
 
 // Response 响应消息
 func (p *proxy) Response(ctx context.Context, req Request, message interface{}) error {
+	return p.response(ctx, req, req.Route(), message)
+}
+
+// 以指定路由响应消息
+func (p *proxy) response(ctx context.Context, req Request, route int32, message interface{}) error {
+	if r, ok := req.(*request); ok && r.chReply != nil {
+		return r.reply(route, message)
+	}
+
 	switch {
 	case req.GID() != "":
 		return p.link.Push(ctx, &link.PushArgs{
@@ -226,7 +478,7 @@ func (p *proxy) Response(ctx context.Context, req Request, message interface{})
 			Target: req.CID(),
 			Message: &Message{
 				Seq:   req.Seq(),
-				Route: req.Route(),
+				Route: route,
 				Data:  message,
 			},
 		})
@@ -236,7 +488,7 @@ func (p *proxy) Response(ctx context.Context, req Request, message interface{})
 			UID: req.UID(),
 			Message: &Message{
 				Seq:   req.Seq(),
-				Route: req.Route(),
+				Route: route,
 				Data:  message,
 			},
 		})
@@ -251,8 +503,19 @@ func (p *proxy) Disconnect(ctx context.Context, args *DisconnectArgs) error {
 }
 
 // 启动监听
-func (p *proxy) watch(ctx context.Context) {
-	p.link.WatchUserLocate(ctx, cluster.Gate, cluster.Node)
+func (p *proxy) watch(ctx context.Context) error {
+	if err := p.link.WatchUserLocate(ctx, cluster.Gate, cluster.Node); err != nil {
+		return err
+	}
+
+	return p.link.WatchServiceInstance(ctx, cluster.Gate, cluster.Node)
+}
+
+// 定时器分发分片键
+func timerKey(uid []int64) int64 {
+	if len(uid) > 0 {
+		return uid[0]
+	}
 
-	p.link.WatchServiceInstance(ctx, cluster.Gate, cluster.Node)
+	return 0
 }
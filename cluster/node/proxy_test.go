@@ -0,0 +1,71 @@
+package node
+
+import (
+	"context"
+	"github.com/dobyte/due/encoding/json"
+	"testing"
+	"time"
+)
+
+func TestProxy_Call_Reentrant(t *testing.T) {
+	n := NewNode(WithID("node"), WithCodec(json.NewCodec()))
+
+	n.proxy.AddRouteHandler(1, false, func(req Request) {
+		var reply string
+		if err := n.proxy.Call(req.Context(), &DeliverArgs{
+			NID:     n.proxy.GetID(),
+			Message: &Message{Route: 2, Data: "ping"},
+		}, &reply); err != nil {
+			_ = req.ResponseError(err)
+			return
+		}
+
+		_ = req.Response(reply)
+	})
+
+	n.proxy.SetDefaultRouteHandler(func(req Request) {
+		_ = req.Response("pong")
+	})
+
+	n.dispatcher.start()
+	defer n.dispatcher.stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var reply string
+	if err := n.proxy.Call(ctx, &DeliverArgs{
+		NID:     n.proxy.GetID(),
+		Message: &Message{Route: 1},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if reply != "pong" {
+		t.Fatalf("unexpected reply: %s", reply)
+	}
+}
+
+func TestProxy_DeliverAfterStop(t *testing.T) {
+	n := NewNode(WithID("node"), WithCodec(json.NewCodec()))
+	n.proxy.SetDefaultRouteHandler(func(req Request) {
+		_ = req.Response("pong")
+	})
+
+	n.dispatcher.start()
+	n.dispatcher.stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	args := &DeliverArgs{NID: n.proxy.GetID(), Message: &Message{Route: 1}}
+
+	if err := n.proxy.Deliver(ctx, args); err != ErrNodeNotWorking {
+		t.Fatalf("expected ErrNodeNotWorking on deliver, got %v", err)
+	}
+
+	var reply string
+	if err := n.proxy.Call(ctx, args, &reply); err != ErrNodeNotWorking {
+		t.Fatalf("expected ErrNodeNotWorking on call, got %v", err)
+	}
+}
@@ -11,7 +11,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/gob"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/code"
+	"github.com/dobyte/due/errors"
 	"github.com/dobyte/due/session"
+	"github.com/dobyte/due/transport"
 )
 
 type Request interface {
@@ -35,8 +39,18 @@ type Request interface {
 	Context() context.Context
 	// GetIP 获取IP地址
 	GetIP() (string, error)
+	// Attrs 获取会话属性，为网关投递消息时的会话属性快照
+	Attrs() map[string]string
+	// Claims 获取鉴权声明，为网关鉴权器鉴权通过时返回的声明
+	Claims() map[string]string
+	// SetAttrs 设置会话属性
+	SetAttrs(attrs map[string]string) error
+	// DelAttrs 删除会话属性
+	DelAttrs(keys ...string) error
 	// Response 响应请求
 	Response(message interface{}) error
+	// ResponseError 以错误中携带的错误码响应错误，未携带错误码时使用code.InternalError
+	ResponseError(err error) error
 	// BindGate 绑定网关
 	BindGate(uid int64) error
 	// UnbindGate 解绑网关
@@ -51,13 +65,16 @@ type Request interface {
 
 // 请求数据
 type request struct {
-	ctx     context.Context // context
-	gid     string          // 来源网关ID
-	nid     string          // 来源节点ID
-	cid     int64           // 连接ID
-	uid     int64           // 用户ID
-	message *Message        // 请求消息
-	node    *Node           // 节点服务器
+	ctx     context.Context         // context
+	gid     string                  // 来源网关ID
+	nid     string                  // 来源节点ID
+	cid     int64                   // 连接ID
+	uid     int64                   // 用户ID
+	attrs   map[string]string       // 会话属性
+	claims  map[string]string       // 鉴权声明
+	message *Message                // 请求消息
+	node    *Node                   // 节点服务器
+	chReply chan *transport.Message // 同步调用的响应通道
 }
 
 // GID 获取来源网关ID
@@ -130,11 +147,110 @@ func (r *request) GetIP() (string, error) {
 	})
 }
 
+// Attrs 获取会话属性
+func (r *request) Attrs() map[string]string {
+	return r.attrs
+}
+
+// Claims 获取鉴权声明
+func (r *request) Claims() map[string]string {
+	return r.claims
+}
+
+// SetAttrs 设置会话属性，同时更新当前请求的会话属性快照
+func (r *request) SetAttrs(attrs map[string]string) error {
+	err := r.node.proxy.SetAttrs(r.Context(), &SetAttrsArgs{
+		GID:    r.gid,
+		Kind:   session.Conn,
+		Target: r.cid,
+		Attrs:  attrs,
+	})
+	if err != nil {
+		return err
+	}
+
+	snapshot := make(map[string]string, len(r.attrs)+len(attrs))
+	for k, v := range r.attrs {
+		snapshot[k] = v
+	}
+	for k, v := range attrs {
+		snapshot[k] = v
+	}
+	r.attrs = snapshot
+
+	return nil
+}
+
+// DelAttrs 删除会话属性，同时更新当前请求的会话属性快照
+func (r *request) DelAttrs(keys ...string) error {
+	err := r.node.proxy.DelAttrs(r.Context(), &DelAttrsArgs{
+		GID:    r.gid,
+		Kind:   session.Conn,
+		Target: r.cid,
+		Keys:   keys,
+	})
+	if err != nil {
+		return err
+	}
+
+	snapshot := make(map[string]string, len(r.attrs))
+	for k, v := range r.attrs {
+		snapshot[k] = v
+	}
+	for _, k := range keys {
+		delete(snapshot, k)
+	}
+	r.attrs = snapshot
+
+	return nil
+}
+
 // Response 响应请求
 func (r *request) Response(message interface{}) error {
 	return r.node.proxy.Response(r.Context(), r, message)
 }
 
+// ResponseError 以错误中携带的错误码响应错误，未携带错误码时使用code.InternalError
+func (r *request) ResponseError(err error) error {
+	c := errors.Code(err)
+	if c == code.Nil {
+		c = code.InternalError
+	}
+
+	reply := &cluster.ErrorReply{
+		Route:   r.Route(),
+		Code:    int32(c.Code()),
+		Message: c.Message(),
+	}
+
+	return r.node.proxy.response(r.Context(), r, cluster.ErrorRoute, reply.Marshal())
+}
+
+// 响应同步调用，每个请求仅能响应一次
+func (r *request) reply(route int32, message interface{}) error {
+	var (
+		buffer []byte
+		err    error
+	)
+
+	switch v := message.(type) {
+	case nil:
+	case []byte:
+		buffer = v
+	default:
+		if buffer, err = r.node.opts.codec.Marshal(v); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case r.chReply <- &transport.Message{Seq: r.Seq(), Route: route, Buffer: buffer}:
+		return nil
+	default:
+		return ErrRepeatedReply
+	}
+}
+
 // BindGate 绑定网关
 func (r *request) BindGate(uid int64) error {
 	return r.node.proxy.BindGate(r.Context(), r.gid, r.cid, uid)
@@ -0,0 +1,163 @@
+package node
+
+import (
+	"context"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/encoding/json"
+	"github.com/dobyte/due/registry"
+	"github.com/dobyte/due/router"
+	"github.com/dobyte/due/session"
+	"github.com/dobyte/due/transport"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 返回网关服务实例的注册器
+type gateRegistry struct {
+	memRegistry
+	ctx context.Context
+}
+
+func (r *gateRegistry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	return &gateWatcher{ctx: r.ctx, kind: cluster.Kind(serviceName)}, nil
+}
+
+type gateWatcher struct {
+	ctx     context.Context
+	kind    cluster.Kind
+	watched bool
+}
+
+func (w *gateWatcher) Next() ([]*registry.ServiceInstance, error) {
+	if !w.watched && w.kind == cluster.Gate {
+		w.watched = true
+		return []*registry.ServiceInstance{{ID: "gate", Kind: cluster.Gate, Endpoint: "test://gate"}}, nil
+	}
+
+	<-w.ctx.Done()
+
+	return nil, w.ctx.Err()
+}
+
+func (w *gateWatcher) Stop() error { return nil }
+
+// 记录会话属性的传输器
+type attrsTransporter struct {
+	transport.Transporter
+	mu    sync.Mutex
+	attrs map[string]string
+}
+
+func (tr *attrsTransporter) NewGateClient(ep *router.Endpoint) (transport.GateClient, error) {
+	return &attrsGateClient{tr: tr}, nil
+}
+
+type attrsGateClient struct {
+	transport.GateClient
+	tr *attrsTransporter
+}
+
+func (c *attrsGateClient) SetAttrs(ctx context.Context, kind session.Kind, target int64, attrs map[string]string) (bool, error) {
+	c.tr.mu.Lock()
+	defer c.tr.mu.Unlock()
+
+	for k, v := range attrs {
+		c.tr.attrs[k] = v
+	}
+
+	return false, nil
+}
+
+func (c *attrsGateClient) DelAttrs(ctx context.Context, kind session.Kind, target int64, keys []string) (bool, error) {
+	c.tr.mu.Lock()
+	defer c.tr.mu.Unlock()
+
+	for _, k := range keys {
+		delete(c.tr.attrs, k)
+	}
+
+	return false, nil
+}
+
+func TestRequest_Attrs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tr := &attrsTransporter{attrs: map[string]string{"level": "1", "guild": "a"}}
+	n := NewNode(
+		WithID("node"),
+		WithCodec(json.NewCodec()),
+		WithLocator(newMemLocator()),
+		WithRegistry(&gateRegistry{ctx: ctx}),
+		WithTransporter(tr),
+	)
+
+	snapshots := make(chan map[string]string, 3)
+	n.proxy.AddRouteHandler(1, false, func(req Request) {
+		snapshots <- req.Attrs()
+
+		if err := req.SetAttrs(map[string]string{"level": "2"}); err != nil {
+			t.Error(err)
+		}
+		snapshots <- req.Attrs()
+
+		if err := req.DelAttrs("guild"); err != nil {
+			t.Error(err)
+		}
+		snapshots <- req.Attrs()
+	})
+
+	n.dispatcher.start()
+	defer n.dispatcher.stop()
+
+	if err := n.proxy.link.WatchServiceInstance(ctx, cluster.Gate); err != nil {
+		t.Fatal(err)
+	}
+
+	if !waitFor(time.Second, func() bool {
+		return n.proxy.SetAttrs(ctx, &SetAttrsArgs{GID: "gate", Kind: session.Conn, Target: 1}) == nil
+	}) {
+		t.Fatal("the gate instance should be watched")
+	}
+
+	delivered := map[string]string{"level": "1", "guild": "a"}
+	if _, err := (&provider{n}).Deliver(ctx, &transport.DeliverArgs{
+		GID:     "gate",
+		CID:     1,
+		Attrs:   delivered,
+		Message: &transport.Message{Route: 1},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []map[string]string{
+		{"level": "1", "guild": "a"},
+		{"level": "2", "guild": "a"},
+		{"level": "2"},
+	}
+
+	for i, attrs := range expected {
+		select {
+		case snapshot := <-snapshots:
+			if !reflect.DeepEqual(snapshot, attrs) {
+				t.Fatalf("snapshot %d: expected %v, got %v", i, attrs, snapshot)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("the request should be handled")
+		}
+	}
+
+	// 更新快照不修改网关投递的会话属性
+	if !reflect.DeepEqual(delivered, map[string]string{"level": "1", "guild": "a"}) {
+		t.Fatalf("the delivered attrs should not be modified, got %v", delivered)
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if !reflect.DeepEqual(tr.attrs, map[string]string{"level": "2"}) {
+		t.Fatalf("the attrs should be updated on the gate, got %v", tr.attrs)
+	}
+}
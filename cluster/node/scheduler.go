@@ -0,0 +1,174 @@
+package node
+
+import (
+	"github.com/dobyte/due/utils/xcron"
+	"github.com/dobyte/due/utils/xtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Timer 定时器
+type Timer interface {
+	// Stop 停止定时器，已入队但尚未执行的回调不再执行
+	Stop()
+}
+
+type scheduler struct {
+	rw     sync.RWMutex // 保证调度器关闭后不再向分发队列投递回调
+	mu     sync.Mutex   // 保护定时器集合
+	closed bool
+	timers map[*timer]struct{}
+	node   *Node
+}
+
+type timer struct {
+	stopped   int32
+	key       int64
+	fn        func()
+	next      func(now time.Time) time.Duration // 计算下一次触发间隔，返回值小于0时不再触发
+	t         *time.Timer
+	scheduler *scheduler
+}
+
+func newScheduler(node *Node) *scheduler {
+	return &scheduler{
+		node:   node,
+		timers: make(map[*timer]struct{}),
+	}
+}
+
+// 延迟执行一次
+func (s *scheduler) afterFunc(d time.Duration, fn func(), key int64) Timer {
+	fired := false
+
+	return s.schedule(key, fn, func(time.Time) time.Duration {
+		if fired {
+			return -1
+		}
+		fired = true
+		return d
+	})
+}
+
+// 按固定间隔执行，间隔不大于0时不执行
+func (s *scheduler) every(d time.Duration, fn func(), key int64) Timer {
+	return s.schedule(key, fn, func(time.Time) time.Duration {
+		if d <= 0 {
+			return -1
+		}
+		return d
+	})
+}
+
+// 按cron表达式执行
+func (s *scheduler) cron(spec string, fn func(), key int64) (Timer, error) {
+	sched, err := xcron.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.schedule(key, fn, func(now time.Time) time.Duration {
+		next := sched.Next(now)
+		if next.IsZero() {
+			return -1
+		}
+		return next.Sub(now)
+	}), nil
+}
+
+// 创建并启动定时器，调度器关闭后创建的定时器不会触发
+func (s *scheduler) schedule(key int64, fn func(), next func(now time.Time) time.Duration) *timer {
+	t := &timer{key: key, fn: fn, next: next, scheduler: s}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		t.stopped = 1
+		return t
+	}
+
+	d := t.next(xtime.Now())
+	if d < 0 {
+		t.stopped = 1
+		return t
+	}
+
+	s.timers[t] = struct{}{}
+	t.t = time.AfterFunc(d, t.fire)
+
+	return t
+}
+
+// 关闭调度器并停止所有定时器
+func (s *scheduler) close() {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+
+	for t := range s.timers {
+		atomic.StoreInt32(&t.stopped, 1)
+		t.t.Stop()
+	}
+
+	s.timers = make(map[*timer]struct{})
+}
+
+// 定时器触发时将回调投递到分发队列，并安排下一次触发
+// 触发期间定时器可能被停止，重新安排前须在持有锁时再次检查，避免重新启动已停止的定时器
+func (t *timer) fire() {
+	t.scheduler.rw.RLock()
+	defer t.scheduler.rw.RUnlock()
+
+	if t.scheduler.closed || t.isStopped() {
+		return
+	}
+
+	if err := t.scheduler.node.dispatcher.dispatch(t.key, ShardDispatch, func() {
+		if !t.isStopped() {
+			t.fn()
+		}
+	}); err != nil {
+		return
+	}
+
+	d := t.next(xtime.Now())
+
+	t.scheduler.mu.Lock()
+	defer t.scheduler.mu.Unlock()
+
+	if t.isStopped() {
+		return
+	}
+
+	if d >= 0 {
+		t.t.Reset(d)
+	} else {
+		delete(t.scheduler.timers, t)
+	}
+}
+
+// Stop 停止定时器
+func (t *timer) Stop() {
+	if !atomic.CompareAndSwapInt32(&t.stopped, 0, 1) {
+		return
+	}
+
+	t.scheduler.mu.Lock()
+	defer t.scheduler.mu.Unlock()
+
+	if t.t != nil {
+		t.t.Stop()
+	}
+
+	delete(t.scheduler.timers, t)
+}
+
+func (t *timer) isStopped() bool {
+	return atomic.LoadInt32(&t.stopped) == 1
+}
@@ -0,0 +1,164 @@
+package node
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newSchedulerNode() *Node {
+	n := NewNode(WithID("node"))
+	n.dispatcher.start()
+
+	return n
+}
+
+// 等待条件成立，超时返回false
+func waitFor(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	return cond()
+}
+
+func (s *scheduler) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.timers)
+}
+
+func TestScheduler_AfterFunc(t *testing.T) {
+	n := newSchedulerNode()
+	defer n.dispatcher.stop()
+
+	var calls int32
+	n.proxy.AfterFunc(10*time.Millisecond, func() { atomic.AddInt32(&calls, 1) })
+
+	if !waitFor(time.Second, func() bool { return atomic.LoadInt32(&calls) == 1 }) {
+		t.Fatal("the callback should be executed once")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("the callback should be executed only once, got %d", got)
+	}
+
+	if !waitFor(time.Second, func() bool { return n.scheduler.count() == 0 }) {
+		t.Fatal("the fired one-shot timer should be removed")
+	}
+}
+
+func TestScheduler_Every(t *testing.T) {
+	n := newSchedulerNode()
+	defer n.dispatcher.stop()
+
+	var calls int32
+	timer := n.proxy.Every(10*time.Millisecond, func() { atomic.AddInt32(&calls, 1) })
+
+	if !waitFor(time.Second, func() bool { return atomic.LoadInt32(&calls) >= 3 }) {
+		t.Fatal("the callback should be executed repeatedly")
+	}
+
+	timer.Stop()
+	time.Sleep(20 * time.Millisecond)
+	stopped := atomic.LoadInt32(&calls)
+	time.Sleep(50 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&calls); got != stopped {
+		t.Fatalf("the callback should not be executed after stop, got %d more", got-stopped)
+	}
+
+	if n.scheduler.count() != 0 {
+		t.Fatal("the stopped timer should be removed")
+	}
+}
+
+func TestScheduler_Cron(t *testing.T) {
+	n := newSchedulerNode()
+	defer n.dispatcher.stop()
+
+	if _, err := n.proxy.Cron("invalid", func() {}); err == nil {
+		t.Fatal("expected error on invalid cron spec")
+	}
+
+	var calls int32
+	timer, err := n.proxy.Cron("* * * * * *", func() { atomic.AddInt32(&calls, 1) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer timer.Stop()
+
+	if !waitFor(3*time.Second, func() bool { return atomic.LoadInt32(&calls) >= 2 }) {
+		t.Fatalf("the callback should be executed every second, got %d", atomic.LoadInt32(&calls))
+	}
+}
+
+func TestScheduler_StopDuringFire(t *testing.T) {
+	n := newSchedulerNode()
+	defer n.dispatcher.stop()
+
+	var (
+		tm    *timer
+		fires int32
+		ready = make(chan struct{})
+		done  = make(chan struct{})
+	)
+
+	// 第二次计算触发间隔时定时器正在触发，此时停止定时器
+	tm = n.scheduler.schedule(0, func() {}, func(time.Time) time.Duration {
+		switch atomic.AddInt32(&fires, 1) {
+		case 1:
+			return 10 * time.Millisecond
+		case 2:
+			<-ready
+			tm.Stop()
+			close(done)
+		}
+		return time.Minute
+	})
+	close(ready)
+
+	<-done
+	time.Sleep(50 * time.Millisecond)
+
+	n.scheduler.mu.Lock()
+	active := tm.t.Stop()
+	n.scheduler.mu.Unlock()
+
+	if active {
+		t.Fatal("the stopped timer should not be rescheduled")
+	}
+
+	if got := atomic.LoadInt32(&fires); got != 2 {
+		t.Fatalf("the stopped timer should not fire again, got %d", got)
+	}
+}
+
+func TestScheduler_Close(t *testing.T) {
+	n := newSchedulerNode()
+	defer n.dispatcher.stop()
+
+	var calls int32
+	n.proxy.Every(10*time.Millisecond, func() { atomic.AddInt32(&calls, 1) })
+
+	n.scheduler.close()
+	time.Sleep(50 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Fatalf("the callback should not be executed after close, got %d", got)
+	}
+
+	n.proxy.AfterFunc(time.Millisecond, func() { atomic.AddInt32(&calls, 1) })
+	time.Sleep(20 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Fatal("the timer created after close should not fire")
+	}
+}
@@ -0,0 +1,84 @@
+package node
+
+import (
+	"sync"
+	"time"
+)
+
+// MigrationHandler 分片迁移处理器，用户在分片路由中的归属节点变为其他节点时调用，nid为新的归属节点
+type MigrationHandler func(route int32, uid int64, nid string)
+
+// 经由分片路由投递到当前节点的用户及其最近一次投递时间
+// 用户断开连接、解绑当前节点或空闲超时后移出，不再接收迁移通知
+type shards struct {
+	mu    sync.Mutex
+	users map[int32]map[int64]time.Time
+}
+
+func newShards() *shards {
+	return &shards{users: make(map[int32]map[int64]time.Time)}
+}
+
+// 记录用户
+func (s *shards) track(route int32, uid int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users, ok := s.users[route]
+	if !ok {
+		users = make(map[int64]time.Time)
+		s.users[route] = users
+	}
+	users[uid] = time.Now()
+}
+
+// 移出用户
+func (s *shards) remove(uid int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for route, users := range s.users {
+		delete(users, uid)
+		if len(users) == 0 {
+			delete(s.users, route)
+		}
+	}
+}
+
+// 移出最近一次投递早于指定时间的用户
+func (s *shards) expire(before time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for route, users := range s.users {
+		for uid, at := range users {
+			if at.Before(before) {
+				delete(users, uid)
+			}
+		}
+		if len(users) == 0 {
+			delete(s.users, route)
+		}
+	}
+}
+
+// 移出不再归属当前节点的用户，owner返回用户的归属节点
+func (s *shards) migrate(route int32, owner func(uid int64) (string, bool)) map[int64]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	migrated := make(map[int64]string)
+	users := s.users[route]
+	for uid := range users {
+		if nid, ok := owner(uid); ok {
+			migrated[uid] = nid
+			delete(users, uid)
+		}
+	}
+
+	if users != nil && len(users) == 0 {
+		delete(s.users, route)
+	}
+
+	return migrated
+}
@@ -0,0 +1,68 @@
+package node
+
+import (
+	"context"
+	"github.com/dobyte/due/cluster"
+	"testing"
+	"time"
+)
+
+// 获取记录的用户数
+func (s *shards) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, users := range s.users {
+		n += len(users)
+	}
+
+	return n
+}
+
+func TestShards_Remove(t *testing.T) {
+	ctx := context.Background()
+	locator := newMemLocator()
+	n := newWorkingNode(locator, &memRegistry{})
+	defer n.dispatcher.stop()
+
+	n.shards.track(1, 1)
+	n.shards.track(2, 1)
+	n.shards.track(1, 2)
+
+	n.handleEvent(&eventEntity{event: cluster.Disconnect, gid: "gate", uid: 1})
+
+	if got := n.shards.count(); got != 1 {
+		t.Fatalf("the disconnected user should be removed, got %d users", got)
+	}
+
+	if err := locator.Set(ctx, 2, cluster.Node, "", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := n.proxy.UnbindNode(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := n.shards.count(); got != 0 || len(n.shards.users) != 0 {
+		t.Fatalf("the unbound user should be removed, got %d users in %d routes", got, len(n.shards.users))
+	}
+}
+
+func TestShards_Expire(t *testing.T) {
+	s := newShards()
+	s.track(1, 1)
+	s.track(2, 2)
+
+	s.expire(time.Now().Add(-time.Minute))
+
+	if got := s.count(); got != 2 {
+		t.Fatalf("the active users should be kept, got %d users", got)
+	}
+
+	s.expire(time.Now().Add(time.Millisecond))
+
+	if got := s.count(); got != 0 || len(s.users) != 0 {
+		t.Fatalf("the idle users should be removed, got %d users in %d routes", got, len(s.users))
+	}
+}
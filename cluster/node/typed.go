@@ -0,0 +1,92 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"github.com/dobyte/due/code"
+	"github.com/dobyte/due/errors"
+	"github.com/dobyte/due/log"
+	"reflect"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	requestType = reflect.TypeOf((*Request)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// 创建类型化路由处理器
+// handler 须形如 func(ctx context.Context, req Request, in *In) (*Out, error)
+func newTypedRouteHandler(handler interface{}) (RouteHandler, error) {
+	fn := reflect.ValueOf(handler)
+	if err := checkTypedRouteHandler(fn); err != nil {
+		return nil, err
+	}
+
+	inType := fn.Type().In(2).Elem()
+
+	return func(req Request) {
+		in := reflect.New(inType)
+		if err := req.Parse(in.Interface()); err != nil {
+			log.Warnf("parse request message failed, route: %d, uid: %d, err: %v", req.Route(), req.UID(), err)
+			responseError(req, errors.NewError(err, code.InvalidParameter))
+			return
+		}
+
+		outs := fn.Call([]reflect.Value{reflect.ValueOf(req.Context()), reflect.ValueOf(req), in})
+
+		if err, _ := outs[1].Interface().(error); err != nil {
+			responseError(req, err)
+			return
+		}
+
+		var message interface{}
+		if out := outs[0]; !isNilValue(out) {
+			message = out.Interface()
+		}
+
+		if err := req.Response(message); err != nil {
+			log.Errorf("response message failed, route: %d, uid: %d, err: %v", req.Route(), req.UID(), err)
+		}
+	}, nil
+}
+
+// 检测类型化路由处理器签名
+func checkTypedRouteHandler(fn reflect.Value) error {
+	if !fn.IsValid() || fn.Kind() != reflect.Func || fn.IsNil() {
+		return errors.New(fmt.Sprintf("the typed route handler must be a non-nil function, but got %v", fn))
+	}
+
+	t := fn.Type()
+
+	if t.NumIn() != 3 || t.In(0) != contextType || t.In(1) != requestType || t.In(2).Kind() != reflect.Ptr {
+		return errors.New(fmt.Sprintf("the typed route handler must accept (context.Context, node.Request, *In), but got %v", t))
+	}
+
+	if t.NumOut() != 2 || t.Out(1) != errorType {
+		return errors.New(fmt.Sprintf("the typed route handler must return (Out, error), but got %v", t))
+	}
+
+	return nil
+}
+
+// 响应错误
+func responseError(req Request, err error) {
+	if errors.Code(err) == code.Nil {
+		log.Errorf("route handler failed, route: %d, uid: %d, err: %v", req.Route(), req.UID(), err)
+	}
+
+	if err = req.ResponseError(err); err != nil {
+		log.Errorf("response error failed, route: %d, uid: %d, err: %v", req.Route(), req.UID(), err)
+	}
+}
+
+// 是否为空值
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
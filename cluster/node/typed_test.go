@@ -0,0 +1,126 @@
+package node
+
+import (
+	"context"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/code"
+	"github.com/dobyte/due/encoding/json"
+	"github.com/dobyte/due/errors"
+	"github.com/dobyte/due/transport"
+	"testing"
+)
+
+type greetIn struct {
+	Name string `json:"name"`
+}
+
+type greetOut struct {
+	Text string `json:"text"`
+}
+
+func greet(ctx context.Context, req Request, in *greetIn) (*greetOut, error) {
+	if in.Name == "" {
+		return nil, errors.NewError(code.InvalidParameter)
+	}
+
+	return &greetOut{Text: "hello " + in.Name}, nil
+}
+
+func serveTyped(t *testing.T, handler RouteHandler, data []byte) *transport.Message {
+	n := NewNode(WithCodec(json.NewCodec()))
+	req := &request{
+		ctx:     context.Background(),
+		nid:     "node",
+		message: &Message{Seq: 1, Route: 1, Data: data},
+		node:    n,
+		chReply: make(chan *transport.Message, 1),
+	}
+
+	handler(req)
+
+	select {
+	case reply := <-req.chReply:
+		return reply
+	default:
+		t.Fatal("the typed route handler did not respond")
+		return nil
+	}
+}
+
+func TestTypedRouteHandler(t *testing.T) {
+	handler, err := newTypedRouteHandler(greet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reply := serveTyped(t, handler, []byte(`{"name":"due"}`))
+	if reply.Route != 1 || string(reply.Buffer) != `{"text":"hello due"}` {
+		t.Fatalf("unexpected reply: %d %s", reply.Route, reply.Buffer)
+	}
+}
+
+func TestTypedRouteHandler_ResponseError(t *testing.T) {
+	handler, err := newTypedRouteHandler(greet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		data []byte
+		code code.Code
+	}{
+		{data: []byte(`{}`), code: code.InvalidParameter},
+		{data: []byte(`{`), code: code.InvalidParameter},
+	}
+
+	for _, tt := range tests {
+		reply := serveTyped(t, handler, tt.data)
+		if reply.Route != cluster.ErrorRoute {
+			t.Fatalf("expected error route, got %d", reply.Route)
+		}
+
+		var r cluster.ErrorReply
+		if err = r.Unmarshal(reply.Buffer); err != nil {
+			t.Fatal(err)
+		}
+
+		if r.Route != 1 || r.Code != int32(tt.code.Code()) {
+			t.Fatalf("unexpected error reply: %+v", r)
+		}
+	}
+
+	internal, err := newTypedRouteHandler(func(ctx context.Context, req Request, in *greetIn) (*greetOut, error) {
+		return nil, errors.New("internal")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r cluster.ErrorReply
+	if err = r.Unmarshal(serveTyped(t, internal, []byte(`{}`)).Buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Code != int32(code.InternalError.Code()) {
+		t.Fatalf("expected internal error, got %+v", r)
+	}
+}
+
+func TestTypedRouteHandler_InvalidSignature(t *testing.T) {
+	var nilFunc func(ctx context.Context, req Request, in *greetIn) (*greetOut, error)
+
+	handlers := []interface{}{
+		nil,
+		nilFunc,
+		1,
+		func(req Request) {},
+		func(ctx context.Context, req Request, in greetIn) (*greetOut, error) { return nil, nil },
+		func(ctx context.Context, req Request, in *greetIn) *greetOut { return nil },
+	}
+
+	for i, handler := range handlers {
+		if _, err := newTypedRouteHandler(handler); err == nil {
+			t.Fatalf("handler %d: expected signature error", i)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package cluster
+
+import (
+	"encoding/binary"
+	"github.com/dobyte/due/errors"
+)
+
+var ErrInvalidErrorReply = errors.New("invalid error reply")
+
+// ErrorReply 错误响应
+// 编码格式为：原请求路由（4字节）+ 错误码（4字节）+ 错误消息，整数均采用大端字节序
+type ErrorReply struct {
+	Route   int32  // 原请求路由
+	Code    int32  // 错误码
+	Message string // 错误消息
+}
+
+// Marshal 编码错误响应
+func (r *ErrorReply) Marshal() []byte {
+	buf := make([]byte, 8+len(r.Message))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(r.Route))
+	binary.BigEndian.PutUint32(buf[4:8], uint32(r.Code))
+	copy(buf[8:], r.Message)
+
+	return buf
+}
+
+// Unmarshal 解码错误响应
+func (r *ErrorReply) Unmarshal(data []byte) error {
+	if len(data) < 8 {
+		return ErrInvalidErrorReply
+	}
+
+	r.Route = int32(binary.BigEndian.Uint32(data[0:4]))
+	r.Code = int32(binary.BigEndian.Uint32(data[4:8]))
+	r.Message = string(data[8:])
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package cluster_test
+
+import (
+	"github.com/dobyte/due/cluster"
+	"testing"
+)
+
+func TestErrorReply(t *testing.T) {
+	reply := &cluster.ErrorReply{Route: 1001, Code: 2, Message: "invalid parameter"}
+
+	var decoded cluster.ErrorReply
+	if err := decoded.Unmarshal(reply.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded != *reply {
+		t.Fatalf("decoded reply mismatch: %+v", decoded)
+	}
+
+	if err := decoded.Unmarshal([]byte{1, 2, 3}); err != cluster.ErrInvalidErrorReply {
+		t.Fatalf("expected invalid error reply, got %v", err)
+	}
+}
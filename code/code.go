@@ -6,10 +6,16 @@ import (
 )
 
 var (
-	Nil              = NewCode(-1, "", nil)
-	InternalError    = NewCode(1, "internal error", nil)
-	InvalidParameter = NewCode(2, "invalid parameter", nil)
-	NotLocatedUser   = NewCode(3, "not located user", nil)
+	Nil                = NewCode(-1, "", nil)
+	InternalError      = NewCode(1, "internal error", nil)
+	InvalidParameter   = NewCode(2, "invalid parameter", nil)
+	NotLocatedUser     = NewCode(3, "not located user", nil)
+	RateLimited        = NewCode(4, "too fast", nil)
+	RouteNotFound      = NewCode(5, "route not found", nil)
+	ServiceUnavailable = NewCode(6, "service unavailable", nil)
+	Timeout            = NewCode(7, "timeout", nil)
+	ResumeFailed       = NewCode(8, "resume failed", nil)
+	DuplicateLogin     = NewCode(9, "duplicate login", nil)
 )
 
 type Code interface {
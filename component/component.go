@@ -1,32 +1,53 @@
 package component
 
+import "time"
+
 type Component interface {
 	// Name 组件名称
 	Name() string
+	// Depends 组件依赖的其他组件名称，容器会保证依赖组件先于当前组件启动、后于当前组件销毁
+	Depends() []string
+	// ShutdownTimeout 组件销毁超时时间，小于等于0时使用容器默认超时时间
+	ShutdownTimeout() time.Duration
 	// Init 初始化组件
-	Init()
+	Init() error
 	// Start 启动组件
-	Start()
+	Start() error
 	// Restart 重启组件
-	Restart()
+	// 热重启时新进程就绪后在旧进程中调用，组件应停止接收新的请求并处理完存量请求，随后容器将销毁组件
+	Restart() error
 	// Destroy 销毁组件
-	Destroy()
+	Destroy() error
 }
 
 type Base struct {
+	depends         []string
+	shutdownTimeout time.Duration
 }
 
 // Name 组件名称
 func (b *Base) Name() string { return "base" }
 
+// Depends 组件依赖的其他组件名称
+func (b *Base) Depends() []string { return b.depends }
+
+// DependOn 设置组件依赖的其他组件名称
+func (b *Base) DependOn(names ...string) { b.depends = append(b.depends, names...) }
+
+// ShutdownTimeout 组件销毁超时时间
+func (b *Base) ShutdownTimeout() time.Duration { return b.shutdownTimeout }
+
+// SetShutdownTimeout 设置组件销毁超时时间
+func (b *Base) SetShutdownTimeout(timeout time.Duration) { b.shutdownTimeout = timeout }
+
 // Init 初始化组件
-func (b *Base) Init() {}
+func (b *Base) Init() error { return nil }
 
 // Start 启动组件
-func (b *Base) Start() {}
+func (b *Base) Start() error { return nil }
 
 // Restart 重启组件
-func (b *Base) Restart() {}
+func (b *Base) Restart() error { return nil }
 
 // Destroy 销毁组件
-func (b *Base) Destroy() {}
+func (b *Base) Destroy() error { return nil }
@@ -0,0 +1,61 @@
+package component
+
+import (
+	"fmt"
+	"github.com/dobyte/due/errors"
+)
+
+var ErrCircularDependency = errors.New("circular component dependency")
+
+// Sort 按照依赖关系对组件进行拓扑排序，无依赖关系的组件保持添加顺序
+func Sort(components []Component) ([]Component, error) {
+	var (
+		indexes  = make(map[string]int, len(components))
+		degrees  = make([]int, len(components))
+		children = make([][]int, len(components))
+	)
+
+	for i, comp := range components {
+		name := comp.Name()
+		if _, ok := indexes[name]; ok {
+			return nil, errors.New(fmt.Sprintf("duplicate component name: %s", name))
+		}
+		indexes[name] = i
+	}
+
+	for i, comp := range components {
+		for _, dep := range comp.Depends() {
+			j, ok := indexes[dep]
+			if !ok {
+				return nil, errors.New(fmt.Sprintf("unknown component dependency: %s -> %s", comp.Name(), dep))
+			}
+			degrees[i]++
+			children[j] = append(children[j], i)
+		}
+	}
+
+	sorted := make([]Component, 0, len(components))
+	visited := make([]bool, len(components))
+
+	for len(sorted) < len(components) {
+		next := -1
+		for i := range components {
+			if !visited[i] && degrees[i] == 0 {
+				next = i
+				break
+			}
+		}
+
+		if next == -1 {
+			return nil, ErrCircularDependency
+		}
+
+		visited[next] = true
+		sorted = append(sorted, components[next])
+		for _, child := range children[next] {
+			degrees[child]--
+		}
+	}
+
+	return sorted, nil
+}
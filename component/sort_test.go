@@ -0,0 +1,58 @@
+package component_test
+
+import (
+	"github.com/dobyte/due/component"
+	"testing"
+)
+
+type comp struct {
+	component.Base
+	name string
+}
+
+func (c *comp) Name() string { return c.name }
+
+func newComp(name string, depends ...string) *comp {
+	c := &comp{name: name}
+	c.DependOn(depends...)
+	return c
+}
+
+func TestSort(t *testing.T) {
+	sorted, err := component.Sort([]component.Component{
+		newComp("gate", "node"),
+		newComp("master"),
+		newComp("node"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(sorted))
+	for _, c := range sorted {
+		names = append(names, c.Name())
+	}
+
+	if names[0] != "master" || names[1] != "node" || names[2] != "gate" {
+		t.Fatalf("unexpected order: %v", names)
+	}
+}
+
+func TestSortCircular(t *testing.T) {
+	_, err := component.Sort([]component.Component{
+		newComp("gate", "node"),
+		newComp("node", "gate"),
+	})
+	if err != component.ErrCircularDependency {
+		t.Fatalf("expect circular dependency error, got: %v", err)
+	}
+}
+
+func TestSortUnknown(t *testing.T) {
+	_, err := component.Sort([]component.Component{
+		newComp("gate", "node"),
+	})
+	if err == nil {
+		t.Fatal("expect unknown dependency error")
+	}
+}
@@ -47,6 +47,15 @@ func Set(pattern string, value interface{}) error {
 	return globalReader.Set(pattern, value)
 }
 
+// OnChange 监听配置变化，返回取消监听的函数；配置读取器未实现Notifier时不会回调
+func OnChange(handler ChangeHandler) (cancel func()) {
+	if notifier, ok := globalReader.(Notifier); ok {
+		return notifier.OnChange(handler)
+	}
+
+	return func() {}
+}
+
 // Close 关闭配置监听
 func Close() {
 	globalReader.Close()
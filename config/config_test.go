@@ -37,3 +37,22 @@ func TestConfig(t *testing.T) {
 	//
 	//select {}
 }
+
+func TestOnChange(t *testing.T) {
+	calls := 0
+	cancel := config.OnChange(func() { calls++ })
+
+	if err := config.Set("config.test.onChange", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+
+	if err := config.Set("config.test.onChange", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected 1 change notification, got %d", calls)
+	}
+}
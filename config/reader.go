@@ -10,6 +10,7 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 )
 
@@ -24,19 +25,39 @@ type Reader interface {
 	Close()
 }
 
+// Notifier 配置变化通知器，读取器可选实现该接口以支持监听配置变化
+type Notifier interface {
+	// OnChange 监听配置变化，配置源变化或设置配置值后回调，返回取消监听的函数
+	OnChange(handler ChangeHandler) (cancel func())
+}
+
+// ChangeHandler 配置变化处理器
+type ChangeHandler func()
+
+type changeListener struct {
+	id      int64
+	handler ChangeHandler
+}
+
 func init() {
 	gob.Register(map[string]interface{}{})
 	gob.Register([]interface{}{})
 }
 
 type defaultReader struct {
-	opts   *options
-	ctx    context.Context
-	cancel context.CancelFunc
-	values atomic.Value
+	opts      *options
+	ctx       context.Context
+	cancel    context.CancelFunc
+	values    atomic.Value
+	rw        sync.RWMutex
+	listenID  int64
+	listeners []changeListener
 }
 
-var _ Reader = &defaultReader{}
+var (
+	_ Reader   = &defaultReader{}
+	_ Notifier = &defaultReader{}
+)
 
 func NewReader(opts ...Option) Reader {
 	o := &options{
@@ -124,6 +145,8 @@ func (r *defaultReader) watch() {
 				}
 
 				r.values.Store(dst)
+
+				r.notify()
 			}
 		}()
 	}
@@ -325,9 +348,46 @@ func (r *defaultReader) Set(pattern string, value interface{}) error {
 
 	r.values.Store(values)
 
+	r.notify()
+
 	return nil
 }
 
+// OnChange 监听配置变化，返回取消监听的函数
+func (r *defaultReader) OnChange(handler ChangeHandler) (cancel func()) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.listenID++
+	id := r.listenID
+	r.listeners = append(r.listeners, changeListener{id: id, handler: handler})
+
+	return func() {
+		r.rw.Lock()
+		defer r.rw.Unlock()
+
+		for i, listener := range r.listeners {
+			if listener.id == id {
+				listeners := make([]changeListener, 0, len(r.listeners)-1)
+				listeners = append(listeners, r.listeners[:i]...)
+				r.listeners = append(listeners, r.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// 通知配置变化处理器
+func (r *defaultReader) notify() {
+	r.rw.RLock()
+	listeners := r.listeners
+	r.rw.RUnlock()
+
+	for _, listener := range listeners {
+		listener.handler()
+	}
+}
+
 func (r *defaultReader) copyValues() (map[string]interface{}, error) {
 	var buf bytes.Buffer
 	enc := gob.NewEncoder(&buf)
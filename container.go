@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"github.com/dobyte/due/component"
 	"github.com/dobyte/due/config"
+	"github.com/dobyte/due/errors"
+	"github.com/dobyte/due/internal/graceful"
 	"github.com/dobyte/due/log"
+	"time"
 
 	"os"
 	"os/signal"
@@ -12,13 +15,19 @@ import (
 )
 
 type Container struct {
+	opts       *options
 	sig        chan os.Signal
 	components []component.Component
 }
 
 // NewContainer 创建一个容器
-func NewContainer() *Container {
-	return &Container{sig: make(chan os.Signal)}
+func NewContainer(opts ...Option) *Container {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Container{opts: o, sig: make(chan os.Signal)}
 }
 
 // Add 添加组件
@@ -27,27 +36,105 @@ func (c *Container) Add(components ...component.Component) {
 }
 
 // Serve 启动容器
-func (c *Container) Serve() {
+// 组件按照依赖关系依次初始化和启动，任一组件初始化或启动失败时，已启动成功的组件会按相反顺序销毁，
+// 启动失败的组件须自行清理已启动的部分
+// 收到SIGHUP信号时，容器将启动一个继承当前进程监听器的新进程，新进程就绪后当前进程通知组件热重启并退出
+func (c *Container) Serve() error {
 	log.Debug(fmt.Sprintf("Welcome to the due framework %s, Learn more at https://github.com/dobyte/due", Version))
 
-	for _, comp := range c.components {
-		comp.Init()
+	components, err := component.Sort(c.components)
+	if err != nil {
+		log.Errorf("container sort components failed: %v", err)
+		return err
+	}
+
+	for _, comp := range components {
+		if err = comp.Init(); err != nil {
+			log.Errorf("component %s init failed: %v", comp.Name(), err)
+			return err
+		}
 	}
 
-	for _, comp := range c.components {
-		comp.Start()
+	for i, comp := range components {
+		if err = comp.Start(); err != nil {
+			log.Errorf("component %s start failed: %v", comp.Name(), err)
+			c.destroy(components[:i])
+			return err
+		}
 	}
 
 	signal.Notify(c.sig, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGKILL, syscall.SIGTERM, syscall.SIGUSR1, syscall.SIGUSR2)
 
-	sig := <-c.sig
+	graceful.Ready()
+
+	for {
+		sig := <-c.sig
+
+		if sig != syscall.SIGHUP {
+			log.Warnf("process got signal %v, container will close", sig)
+			break
+		}
 
-	log.Warnf("process got signal %v, container will close", sig)
+		pid, err := graceful.Fork(c.opts.restartTimeout)
+		if err != nil {
+			log.Errorf("process got signal %v, container restart failed: %v", sig, err)
+			continue
+		}
+
+		log.Warnf("process got signal %v, container restarted in process %d and will close", sig, pid)
+
+		c.restart(components)
+		break
+	}
 
 	signal.Stop(c.sig)
 	config.Close()
 
-	for _, comp := range c.components {
-		comp.Destroy()
+	c.destroy(components)
+
+	return nil
+}
+
+// 按照启动顺序的相反顺序通知组件进行热重启
+func (c *Container) restart(components []component.Component) {
+	for i := len(components) - 1; i >= 0; i-- {
+		if err := components[i].Restart(); err != nil {
+			log.Errorf("component %s restart failed: %v", components[i].Name(), err)
+		}
+	}
+}
+
+// 按照启动顺序的相反顺序销毁组件
+func (c *Container) destroy(components []component.Component) {
+	for i := len(components) - 1; i >= 0; i-- {
+		comp := components[i]
+
+		timeout := comp.ShutdownTimeout()
+		if timeout <= 0 {
+			timeout = c.opts.shutdownTimeout
+		}
+
+		if err := destroyWithTimeout(comp, timeout); err != nil {
+			log.Errorf("component %s destroy failed: %v", comp.Name(), err)
+		}
+	}
+}
+
+// 在超时时间内销毁组件
+func destroyWithTimeout(comp component.Component, timeout time.Duration) error {
+	done := make(chan error, 1)
+
+	go func() {
+		done <- comp.Destroy()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return errors.New(fmt.Sprintf("destroy timeout after %v", timeout))
 	}
 }
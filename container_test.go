@@ -0,0 +1,67 @@
+package due_test
+
+import (
+	"github.com/dobyte/due"
+	"github.com/dobyte/due/component"
+	"github.com/dobyte/due/errors"
+	"reflect"
+	"testing"
+)
+
+// 记录生命周期的组件
+type lifecycle struct {
+	component.Base
+	name      string
+	initErr   error
+	startErr  error
+	destroyed *[]string
+}
+
+func (c *lifecycle) Name() string { return c.name }
+
+func (c *lifecycle) Init() error { return c.initErr }
+
+func (c *lifecycle) Start() error { return c.startErr }
+
+func (c *lifecycle) Destroy() error {
+	*c.destroyed = append(*c.destroyed, c.name)
+	return nil
+}
+
+func TestContainer_RollbackOnInitFailure(t *testing.T) {
+	var destroyed []string
+
+	container := due.NewContainer()
+	container.Add(
+		&lifecycle{name: "a", destroyed: &destroyed},
+		&lifecycle{name: "b", initErr: errors.New("init failed"), destroyed: &destroyed},
+	)
+
+	if err := container.Serve(); err == nil {
+		t.Fatal("expected init error")
+	}
+
+	if len(destroyed) != 0 {
+		t.Fatalf("no component should be destroyed before started, got %v", destroyed)
+	}
+}
+
+func TestContainer_RollbackOnStartFailure(t *testing.T) {
+	var destroyed []string
+
+	container := due.NewContainer()
+	container.Add(
+		&lifecycle{name: "a", destroyed: &destroyed},
+		&lifecycle{name: "b", destroyed: &destroyed},
+		&lifecycle{name: "c", startErr: errors.New("start failed"), destroyed: &destroyed},
+		&lifecycle{name: "d", destroyed: &destroyed},
+	)
+
+	if err := container.Serve(); err == nil {
+		t.Fatal("expected start error")
+	}
+
+	if expected := []string{"b", "a"}; !reflect.DeepEqual(destroyed, expected) {
+		t.Fatalf("only the started components should be destroyed in reverse order, expected %v, got %v", expected, destroyed)
+	}
+}
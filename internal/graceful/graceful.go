@@ -0,0 +1,229 @@
+package graceful
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dobyte/due/errors"
+)
+
+const (
+	envListeners = "DUE_GRACEFUL_LISTENERS" // 继承的监听器标识，按文件描述符顺序以逗号分隔
+	envReadyFD   = "DUE_GRACEFUL_READY_FD"  // 通知父进程就绪的文件描述符
+)
+
+const firstInheritedFD = 3 // 继承的第一个文件描述符（0、1、2为标准输入输出）
+
+type filer interface {
+	File() (*os.File, error)
+}
+
+var (
+	mu        sync.Mutex
+	inherited = make(map[string]*os.File) // 从父进程继承的监听器文件
+	listeners = make(map[string]filer)    // 当前进程可传递给子进程的监听器
+	ready     *os.File                    // 通知父进程就绪的管道
+)
+
+func init() {
+	if keys := os.Getenv(envListeners); keys != "" {
+		for i, k := range strings.Split(keys, ",") {
+			inherited[k] = os.NewFile(uintptr(firstInheritedFD+i), k)
+		}
+	}
+
+	if fd, err := strconv.Atoi(os.Getenv(envReadyFD)); err == nil {
+		ready = os.NewFile(uintptr(fd), "ready")
+	}
+
+	_ = os.Unsetenv(envListeners)
+	_ = os.Unsetenv(envReadyFD)
+}
+
+// IsInherited 当前进程是否由热重启创建
+func IsInherited() bool {
+	return ready != nil
+}
+
+// Listen 监听流式网络地址，存在从父进程继承的同地址监听器时直接复用
+func Listen(network, addr string) (net.Listener, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	k := key(network, addr)
+
+	var (
+		ln  net.Listener
+		err error
+	)
+
+	if f, ok := inherited[k]; ok {
+		delete(inherited, k)
+		ln, err = net.FileListener(f)
+		_ = f.Close()
+	} else {
+		ln, err = net.Listen(network, addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if l, ok := ln.(filer); ok {
+		listeners[k] = l
+	}
+
+	return ln, nil
+}
+
+// ListenPacket 监听数据报网络地址，存在从父进程继承的同地址连接时直接复用
+func ListenPacket(network, addr string) (net.PacketConn, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	k := key(network, addr)
+
+	var (
+		conn net.PacketConn
+		err  error
+	)
+
+	if f, ok := inherited[k]; ok {
+		delete(inherited, k)
+		conn, err = net.FilePacketConn(f)
+		_ = f.Close()
+	} else {
+		conn, err = net.ListenPacket(network, addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c, ok := conn.(filer); ok {
+		listeners[k] = c
+	}
+
+	return conn, nil
+}
+
+// Ready 通知父进程当前进程已就绪，并关闭未被使用的继承监听器
+func Ready() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for k, f := range inherited {
+		_ = f.Close()
+		delete(inherited, k)
+	}
+
+	if ready != nil {
+		_, _ = ready.Write([]byte{1})
+		_ = ready.Close()
+		ready = nil
+	}
+}
+
+// Fork 启动一个继承当前进程所有监听器的新进程，并等待新进程就绪
+// 新进程在超时时间内未就绪或提前退出时，返回错误
+func Fork(timeout time.Duration) (int, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+
+	keys, files := collect()
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append(files, w)
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("%s=%s", envListeners, strings.Join(keys, ",")),
+		fmt.Sprintf("%s=%d", envReadyFD, firstInheritedFD+len(files)),
+	)
+
+	err = cmd.Start()
+	_ = w.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		buf := make([]byte, 1)
+		if _, err := r.Read(buf); err != nil {
+			done <- errors.New(fmt.Sprintf("process %d exited before ready", cmd.Process.Pid))
+		} else {
+			done <- nil
+		}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err = <-done:
+	case <-timer.C:
+		err = errors.New(fmt.Sprintf("process %d not ready after %v", cmd.Process.Pid, timeout))
+	}
+
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return 0, err
+	}
+
+	go cmd.Wait()
+
+	return cmd.Process.Pid, nil
+}
+
+// 收集当前进程所有可传递的监听器文件
+func collect() ([]string, []*os.File) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	keys := make([]string, 0, len(listeners))
+	for k := range listeners {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	files := make([]*os.File, 0, len(keys))
+	available := keys[:0]
+	for _, k := range keys {
+		f, err := listeners[k].File()
+		if err != nil {
+			// 已关闭的监听器不再传递
+			delete(listeners, k)
+			continue
+		}
+		files = append(files, f)
+		available = append(available, k)
+	}
+
+	return available, files
+}
+
+func key(network, addr string) string {
+	return network + "@" + addr
+}
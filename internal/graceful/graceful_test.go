@@ -0,0 +1,283 @@
+package graceful
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+const envTestChild = "DUE_GRACEFUL_TEST_CHILD" // 测试子进程的运行模式
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(envTestChild) {
+	case "serve":
+		serveChild()
+	case "exit":
+		os.Exit(0)
+	case "hang":
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// 子进程复用继承的监听器，通知就绪后应答一个连接
+func serveChild() {
+	addr := os.Getenv("DUE_GRACEFUL_TEST_ADDR")
+	if !IsInherited() || len(inherited) != 1 {
+		os.Exit(1)
+	}
+
+	ln, err := Listen("tcp", addr)
+	if err != nil {
+		os.Exit(1)
+	}
+
+	Ready()
+
+	conn, err := ln.Accept()
+	if err != nil {
+		os.Exit(1)
+	}
+	_, _ = conn.Write([]byte("child\n"))
+	_ = conn.Close()
+	_ = ln.Close()
+
+	os.Exit(0)
+}
+
+func reset() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for k, f := range inherited {
+		_ = f.Close()
+		delete(inherited, k)
+	}
+	for k := range listeners {
+		delete(listeners, k)
+	}
+	ready = nil
+}
+
+func TestListen_Collect(t *testing.T) {
+	defer reset()
+
+	ln, err := Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	keys, files := collect()
+	for _, f := range files {
+		_ = f.Close()
+	}
+
+	if len(keys) != 2 || keys[0] != "tcp@127.0.0.1:0" || keys[1] != "udp@127.0.0.1:0" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+
+	_ = ln.Close()
+
+	keys, files = collect()
+	for _, f := range files {
+		_ = f.Close()
+	}
+
+	if len(keys) != 1 || keys[0] != "udp@127.0.0.1:0" {
+		t.Fatalf("closed listener should not be collected: %v", keys)
+	}
+}
+
+func TestListen_Inherited(t *testing.T) {
+	defer reset()
+
+	origin, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer origin.Close()
+
+	f, err := origin.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	inherited[key("tcp", "inherited")] = f
+	mu.Unlock()
+
+	ln, err := Listen("tcp", "inherited")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	if ln.Addr().String() != origin.Addr().String() {
+		t.Fatalf("expected inherited addr %s, got %s", origin.Addr(), ln.Addr())
+	}
+
+	if _, ok := inherited[key("tcp", "inherited")]; ok {
+		t.Fatal("inherited listener should be consumed")
+	}
+
+	if _, ok := listeners[key("tcp", "inherited")]; !ok {
+		t.Fatal("inherited listener should be passed on to the next process")
+	}
+}
+
+func TestListenPacket_Inherited(t *testing.T) {
+	defer reset()
+
+	origin, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer origin.Close()
+
+	f, err := origin.(*net.UDPConn).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	inherited[key("udp", "inherited")] = f
+	mu.Unlock()
+
+	conn, err := ListenPacket("udp", "inherited")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if conn.LocalAddr().String() != origin.LocalAddr().String() {
+		t.Fatalf("expected inherited addr %s, got %s", origin.LocalAddr(), conn.LocalAddr())
+	}
+}
+
+func TestReady(t *testing.T) {
+	defer reset()
+
+	origin, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer origin.Close()
+
+	f, err := origin.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	mu.Lock()
+	inherited[key("tcp", "unused")] = f
+	ready = w
+	mu.Unlock()
+
+	if !IsInherited() {
+		t.Fatal("expected inherited process")
+	}
+
+	Ready()
+
+	buf := make([]byte, 1)
+	if n, err := r.Read(buf); err != nil || n != 1 {
+		t.Fatalf("expected ready notification, got %d bytes: %v", n, err)
+	}
+
+	if len(inherited) != 0 {
+		t.Fatal("unused inherited listeners should be closed")
+	}
+
+	if IsInherited() {
+		t.Fatal("ready pipe should be closed")
+	}
+}
+
+func TestFork(t *testing.T) {
+	defer reset()
+
+	ln, err := Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+
+	setenv(t, envTestChild, "serve")
+	setenv(t, "DUE_GRACEFUL_TEST_ADDR", "127.0.0.1:0")
+
+	if _, err = Fork(10 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// 父进程停止接收后，新连接由子进程应答
+	_ = ln.Close()
+
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if line != "child\n" {
+		t.Fatalf("expected reply from child, got %q", line)
+	}
+}
+
+func TestFork_ExitedBeforeReady(t *testing.T) {
+	defer reset()
+
+	setenv(t, envTestChild, "exit")
+
+	if _, err := Fork(10 * time.Second); err == nil {
+		t.Fatal("expected error when the child exits before ready")
+	}
+}
+
+func TestFork_Timeout(t *testing.T) {
+	defer reset()
+
+	setenv(t, envTestChild, "hang")
+
+	start := time.Now()
+
+	if _, err := Fork(200 * time.Millisecond); err == nil {
+		t.Fatal("expected error when the child is not ready in time")
+	}
+
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("fork should give up after the timeout")
+	}
+}
+
+func setenv(t *testing.T, key, value string) {
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.Unsetenv(key)
+	})
+}
@@ -2,18 +2,20 @@ package link
 
 import (
 	"context"
+	"fmt"
 	"github.com/dobyte/due/cluster"
 	"github.com/dobyte/due/crypto"
 	"github.com/dobyte/due/encoding"
 	"github.com/dobyte/due/errors"
 	"github.com/dobyte/due/locate"
-	"github.com/dobyte/due/log"
 	"github.com/dobyte/due/packet"
 	"github.com/dobyte/due/registry"
 	"github.com/dobyte/due/router"
 	"github.com/dobyte/due/session"
 	"github.com/dobyte/due/transport"
 	"golang.org/x/sync/errgroup"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -34,12 +36,24 @@ type Link struct {
 	gateRouter *router.Router // 网关路由器
 	nodeRouter *router.Router // 节点路由器
 	sourceGate sync.Map       // 用户来源网关
-	sourceNode sync.Map       // 用户来源节点
+	sourceNode sync.Map       // 用户来源节点（nodeKey -> 节点ID）
+	locator    locate.Locator // 当前游戏区的定位器
+	root       *Link          // 当前游戏区的链接，仅跨区链接有效
+	zrw        sync.RWMutex
+	zones      map[string]*Link                             // 跨区链接
+	services   map[cluster.Kind][]*registry.ServiceInstance // 最近一次监听到的所有游戏区的服务实例
+}
+
+// 用户在节点分组中的定位
+type nodeKey struct {
+	uid   int64
+	group string
 }
 
 type Options struct {
 	GID         string                // 网关ID
 	NID         string                // 节点ID
+	Zone        string                // 游戏区
 	Codec       encoding.Codec        // 编解码器
 	Locator     locate.Locator        // 定位器
 	Registry    registry.Registry     // 注册器
@@ -48,13 +62,39 @@ type Options struct {
 }
 
 func NewLink(opts *Options) *Link {
-	return &Link{
+	l := &Link{
 		opts:       opts,
 		gateRouter: router.NewRouter(),
 		nodeRouter: router.NewRouter(),
+		zones:      make(map[string]*Link),
+		services:   make(map[cluster.Kind][]*registry.ServiceInstance),
+	}
+
+	if opts.Locator != nil {
+		l.locator = zoneLocator(opts.Locator, opts.Zone, false)
+	}
+
+	return l
+}
+
+// Close 关闭链接，关闭当前游戏区及跨区链接的路由器
+func (l *Link) Close() {
+	l.gateRouter.Close()
+	l.nodeRouter.Close()
+
+	l.zrw.RLock()
+	defer l.zrw.RUnlock()
+
+	for _, zl := range l.zones {
+		zl.Close()
 	}
 }
 
+// Locator 获取当前游戏区的定位器
+func (l *Link) Locator() locate.Locator {
+	return l.locator
+}
+
 // BindGate 绑定网关
 func (l *Link) BindGate(ctx context.Context, gid string, cid, uid int64) error {
 	client, err := l.getGateClientByGID(gid)
@@ -67,7 +107,9 @@ func (l *Link) BindGate(ctx context.Context, gid string, cid, uid int64) error {
 		return err
 	}
 
-	l.sourceGate.Store(uid, gid)
+	if l.cacheable() {
+		l.sourceGate.Store(uid, gid)
+	}
 
 	return nil
 }
@@ -88,16 +130,18 @@ func (l *Link) UnbindGate(ctx context.Context, uid int64) error {
 }
 
 // BindNode 绑定节点
-// 单个用户只能被绑定到某一台节点服务器上，多次绑定会直接覆盖上次绑定
+// 单个用户在每个节点分组中只能被绑定到某一台节点服务器上，同一分组中多次绑定会直接覆盖上次绑定
 // 绑定操作会通过发布订阅方式同步到网关服务器和其他相关节点服务器上
-// nid 为需要绑定的节点ID
-func (l *Link) BindNode(ctx context.Context, uid int64, nid string) error {
-	err := l.opts.Locator.Set(ctx, uid, cluster.Node, nid)
+// group 为节点分组，nid 为需要绑定的节点ID
+func (l *Link) BindNode(ctx context.Context, uid int64, group, nid string) error {
+	err := l.locator.Set(ctx, uid, cluster.Node, group, nid)
 	if err != nil {
 		return err
 	}
 
-	l.sourceNode.Store(uid, nid)
+	if l.cacheable() {
+		l.sourceNode.Store(nodeKey{uid: uid, group: group}, nid)
+	}
 
 	return nil
 }
@@ -105,14 +149,14 @@ func (l *Link) BindNode(ctx context.Context, uid int64, nid string) error {
 // UnbindNode 解绑节点
 // 解绑时会对解绑节点ID进行校验，不匹配则解绑失败
 // 解绑操作会通过发布订阅方式同步到网关服务器和其他相关节点服务器上
-// nid 为需要解绑的节点ID
-func (l *Link) UnbindNode(ctx context.Context, uid int64, nid string) error {
-	err := l.opts.Locator.Rem(ctx, uid, cluster.Node, nid)
+// group 为节点分组，nid 为需要解绑的节点ID
+func (l *Link) UnbindNode(ctx context.Context, uid int64, group, nid string) error {
+	err := l.locator.Rem(ctx, uid, cluster.Node, group, nid)
 	if err != nil {
 		return err
 	}
 
-	l.sourceNode.Delete(uid)
+	l.sourceNode.Delete(nodeKey{uid: uid, group: group})
 
 	return nil
 }
@@ -125,7 +169,7 @@ func (l *Link) LocateGate(ctx context.Context, uid int64) (string, error) {
 		}
 	}
 
-	gid, err := l.opts.Locator.Get(ctx, uid, cluster.Gate)
+	gid, err := l.locator.Get(ctx, uid, cluster.Gate, "")
 	if err != nil {
 		return "", err
 	}
@@ -134,20 +178,24 @@ func (l *Link) LocateGate(ctx context.Context, uid int64) (string, error) {
 		return "", ErrNotFoundUserSource
 	}
 
-	l.sourceGate.Store(uid, gid)
+	if l.cacheable() {
+		l.sourceGate.Store(uid, gid)
+	}
 
 	return gid, nil
 }
 
-// LocateNode 定位用户所在节点
-func (l *Link) LocateNode(ctx context.Context, uid int64) (string, error) {
-	if val, ok := l.sourceNode.Load(uid); ok {
+// LocateNode 定位用户在节点分组中所在的节点
+func (l *Link) LocateNode(ctx context.Context, uid int64, group string) (string, error) {
+	key := nodeKey{uid: uid, group: group}
+
+	if val, ok := l.sourceNode.Load(key); ok {
 		if nid := val.(string); nid != "" {
 			return nid, nil
 		}
 	}
 
-	nid, err := l.opts.Locator.Get(ctx, uid, cluster.Node)
+	nid, err := l.locator.Get(ctx, uid, cluster.Node, group)
 	if err != nil {
 		return "", err
 	}
@@ -156,22 +204,45 @@ func (l *Link) LocateNode(ctx context.Context, uid int64) (string, error) {
 		return "", ErrNotFoundUserSource
 	}
 
-	l.sourceNode.Store(uid, nid)
+	if l.cacheable() {
+		l.sourceNode.Store(key, nid)
+	}
 
 	return nid, nil
 }
 
-// FetchServiceList 拉取服务列表
+// CountNodeUsers 统计绑定到指定节点的用户数量
+// 定位器实现了locate.CountLocator时以定位器中的用户定位为准，
+// 否则仅统计本地缓存的用户定位信息，即当前实例绑定、定位过或监听到的用户
+func (l *Link) CountNodeUsers(ctx context.Context, nid string) (int, error) {
+	if cl, ok := l.locator.(locate.CountLocator); ok {
+		n, err := cl.Count(ctx, cluster.Node, nid)
+		return int(n), err
+	}
+
+	n := 0
+	l.sourceNode.Range(func(_, val interface{}) bool {
+		if val.(string) == nid {
+			n++
+		}
+		return true
+	})
+
+	return n, nil
+}
+
+// FindNodeGroup 查找节点所在分组
+func (l *Link) FindNodeGroup(nid string) (string, error) {
+	return l.nodeRouter.FindNodeGroup(nid)
+}
+
+// FetchServiceList 拉取当前游戏区的服务列表
 func (l *Link) FetchServiceList(ctx context.Context, kind cluster.Kind, states ...cluster.State) ([]*registry.ServiceInstance, error) {
 	services, err := l.opts.Registry.Services(ctx, string(kind))
 	if err != nil {
 		return nil, err
 	}
 
-	if len(states) == 0 {
-		return services, nil
-	}
-
 	mp := make(map[cluster.State]struct{}, len(states))
 	for _, state := range states {
 		mp[state] = struct{}{}
@@ -179,7 +250,11 @@ func (l *Link) FetchServiceList(ctx context.Context, kind cluster.Kind, states .
 
 	list := make([]*registry.ServiceInstance, 0, len(services))
 	for i := range services {
-		if _, ok := mp[services[i].State]; ok {
+		if services[i].Zone != l.opts.Zone {
+			continue
+		}
+
+		if _, ok := mp[services[i].State]; ok || len(states) == 0 {
 			list = append(list, services[i])
 		}
 	}
@@ -189,6 +264,10 @@ func (l *Link) FetchServiceList(ctx context.Context, kind cluster.Kind, states .
 
 // GetIP 获取客户端IP
 func (l *Link) GetIP(ctx context.Context, args *GetIPArgs) (string, error) {
+	if zl := l.zone(args.Zone); zl != l {
+		return zl.GetIP(ctx, args)
+	}
+
 	switch args.Kind {
 	case session.Conn:
 		return l.directGetIP(ctx, args.GID, args.Kind, args.Target)
@@ -227,8 +306,140 @@ func (l *Link) indirectGetIP(ctx context.Context, uid int64) (string, error) {
 	return v.(string), nil
 }
 
+// GetAttrs 获取会话属性
+func (l *Link) GetAttrs(ctx context.Context, args *GetAttrsArgs) (map[string]string, error) {
+	if zl := l.zone(args.Zone); zl != l {
+		return zl.GetAttrs(ctx, args)
+	}
+
+	switch args.Kind {
+	case session.Conn:
+		return l.directGetAttrs(ctx, args.GID, args.Kind, args.Target)
+	case session.User:
+		if args.GID == "" {
+			return l.indirectGetAttrs(ctx, args.Target)
+		} else {
+			return l.directGetAttrs(ctx, args.GID, args.Kind, args.Target)
+		}
+	default:
+		return nil, ErrInvalidSessionKind
+	}
+}
+
+// 直接获取会话属性
+func (l *Link) directGetAttrs(ctx context.Context, gid string, kind session.Kind, target int64) (map[string]string, error) {
+	client, err := l.getGateClientByGID(gid)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, _, err := client.GetAttrs(ctx, kind, target)
+	return attrs, err
+}
+
+// 间接获取会话属性
+func (l *Link) indirectGetAttrs(ctx context.Context, uid int64) (map[string]string, error) {
+	v, err := l.doGateRPC(ctx, uid, func(client transport.GateClient) (bool, interface{}, error) {
+		attrs, miss, err := client.GetAttrs(ctx, session.User, uid)
+		return miss, attrs, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, _ := v.(map[string]string)
+
+	return attrs, nil
+}
+
+// SetAttrs 设置会话属性
+func (l *Link) SetAttrs(ctx context.Context, args *SetAttrsArgs) error {
+	if zl := l.zone(args.Zone); zl != l {
+		return zl.SetAttrs(ctx, args)
+	}
+
+	switch args.Kind {
+	case session.Conn:
+		return l.directSetAttrs(ctx, args.GID, args.Kind, args.Target, args.Attrs)
+	case session.User:
+		if args.GID == "" {
+			return l.indirectSetAttrs(ctx, args.Target, args.Attrs)
+		} else {
+			return l.directSetAttrs(ctx, args.GID, args.Kind, args.Target, args.Attrs)
+		}
+	default:
+		return ErrInvalidSessionKind
+	}
+}
+
+// 直接设置会话属性
+func (l *Link) directSetAttrs(ctx context.Context, gid string, kind session.Kind, target int64, attrs map[string]string) error {
+	client, err := l.getGateClientByGID(gid)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.SetAttrs(ctx, kind, target, attrs)
+	return err
+}
+
+// 间接设置会话属性
+func (l *Link) indirectSetAttrs(ctx context.Context, uid int64, attrs map[string]string) error {
+	_, err := l.doGateRPC(ctx, uid, func(client transport.GateClient) (bool, interface{}, error) {
+		miss, err := client.SetAttrs(ctx, session.User, uid, attrs)
+		return miss, nil, err
+	})
+
+	return err
+}
+
+// DelAttrs 删除会话属性
+func (l *Link) DelAttrs(ctx context.Context, args *DelAttrsArgs) error {
+	if zl := l.zone(args.Zone); zl != l {
+		return zl.DelAttrs(ctx, args)
+	}
+
+	switch args.Kind {
+	case session.Conn:
+		return l.directDelAttrs(ctx, args.GID, args.Kind, args.Target, args.Keys)
+	case session.User:
+		if args.GID == "" {
+			return l.indirectDelAttrs(ctx, args.Target, args.Keys)
+		} else {
+			return l.directDelAttrs(ctx, args.GID, args.Kind, args.Target, args.Keys)
+		}
+	default:
+		return ErrInvalidSessionKind
+	}
+}
+
+// 直接删除会话属性
+func (l *Link) directDelAttrs(ctx context.Context, gid string, kind session.Kind, target int64, keys []string) error {
+	client, err := l.getGateClientByGID(gid)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DelAttrs(ctx, kind, target, keys)
+	return err
+}
+
+// 间接删除会话属性
+func (l *Link) indirectDelAttrs(ctx context.Context, uid int64, keys []string) error {
+	_, err := l.doGateRPC(ctx, uid, func(client transport.GateClient) (bool, interface{}, error) {
+		miss, err := client.DelAttrs(ctx, session.User, uid, keys)
+		return miss, nil, err
+	})
+
+	return err
+}
+
 // Push 推送消息
 func (l *Link) Push(ctx context.Context, args *PushArgs) error {
+	if zl := l.zone(args.Zone); zl != l {
+		return zl.Push(ctx, args)
+	}
+
 	switch args.Kind {
 	case session.Conn:
 		return l.directPush(ctx, args)
@@ -284,6 +495,10 @@ func (l *Link) indirectPush(ctx context.Context, args *PushArgs) error {
 
 // Multicast 推送组播消息
 func (l *Link) Multicast(ctx context.Context, args *MulticastArgs) (int64, error) {
+	if zl := l.zone(args.Zone); zl != l {
+		return zl.Multicast(ctx, args)
+	}
+
 	switch args.Kind {
 	case session.Conn:
 		return l.directMulticast(ctx, args)
@@ -362,6 +577,10 @@ func (l *Link) indirectMulticast(ctx context.Context, args *MulticastArgs) (int6
 
 // Broadcast 推送广播消息
 func (l *Link) Broadcast(ctx context.Context, args *BroadcastArgs) (int64, error) {
+	if zl := l.zone(args.Zone); zl != l {
+		return zl.Broadcast(ctx, args)
+	}
+
 	buffer, err := l.toBuffer(args.Message.Data, true)
 	if err != nil {
 		return 0, err
@@ -402,8 +621,153 @@ func (l *Link) Broadcast(ctx context.Context, args *BroadcastArgs) (int64, error
 	return total, err
 }
 
+// JoinChannel 将绑定到指定网关的用户加入频道
+func (l *Link) JoinChannel(ctx context.Context, gid, channel string, uids []int64) error {
+	client, err := l.getGateClientByGID(gid)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.JoinChannel(ctx, channel, uids)
+	return err
+}
+
+// LeaveChannel 将绑定到指定网关的用户移出频道，用户为空时解散该网关上的频道
+func (l *Link) LeaveChannel(ctx context.Context, gid, channel string, uids []int64) error {
+	client, err := l.getGateClientByGID(gid)
+	if err != nil {
+		return err
+	}
+
+	return client.LeaveChannel(ctx, channel, uids)
+}
+
+// DismissChannel 解散持有频道成员的网关上的频道
+func (l *Link) DismissChannel(ctx context.Context, channel string) error {
+	gids, err := l.channelGates(ctx, channel)
+	if err != nil {
+		return err
+	}
+
+	return l.rangeGates(ctx, gids, func(ctx context.Context, _ string, client transport.GateClient) error {
+		return client.LeaveChannel(ctx, channel, nil)
+	})
+}
+
+// PublishChannel 向持有频道成员的网关发布频道消息，由网关按本地的频道成员推送，返回推送总数
+// 单个网关发布失败不影响其他网关，部分网关发布失败时同时返回推送总数及失败网关的错误
+func (l *Link) PublishChannel(ctx context.Context, channel string, message *Message) (int64, error) {
+	gids, err := l.channelGates(ctx, channel)
+	if err != nil {
+		return 0, err
+	}
+
+	if gids != nil && len(gids) == 0 {
+		return 0, nil
+	}
+
+	buffer, err := l.toBuffer(message.Data, true)
+	if err != nil {
+		return 0, err
+	}
+
+	total := int64(0)
+	err = l.rangeGates(ctx, gids, func(ctx context.Context, _ string, client transport.GateClient) error {
+		n, err := client.PublishChannel(ctx, channel, &transport.Message{
+			Seq:    message.Seq,
+			Route:  message.Route,
+			Buffer: buffer,
+		})
+		if err != nil {
+			return err
+		}
+
+		atomic.AddInt64(&total, n)
+
+		return nil
+	})
+
+	return total, err
+}
+
+// 获取持有频道成员的网关，定位器未实现locate.ChannelLocator时返回nil，表示所有网关
+func (l *Link) channelGates(ctx context.Context, channel string) ([]string, error) {
+	cl, ok := l.locator.(locate.ChannelLocator)
+	if !ok {
+		return nil, nil
+	}
+
+	gids, err := cl.ChannelGates(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	if gids == nil {
+		gids = []string{}
+	}
+
+	return gids, nil
+}
+
+// 并发调用指定网关，gids为nil时调用所有网关；已下线的网关直接忽略
+// 单个网关调用失败不影响其他网关，返回按网关ID排序汇总的调用错误
+func (l *Link) rangeGates(ctx context.Context, gids []string, fn func(ctx context.Context, gid string, client transport.GateClient) error) error {
+	endpoints := make(map[string]*router.Endpoint)
+	if gids == nil {
+		l.gateRouter.RangeGateEndpoint(func(gid string, ep *router.Endpoint) bool {
+			endpoints[gid] = ep
+			return true
+		})
+	} else {
+		for _, gid := range gids {
+			if ep, err := l.gateRouter.FindGateEndpoint(gid); err == nil {
+				endpoints[gid] = ep
+			}
+		}
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []string
+	)
+
+	for gid, ep := range endpoints {
+		gid, ep := gid, ep
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			client, err := l.opts.Transporter.NewGateClient(ep)
+			if err == nil {
+				err = fn(ctx, gid, client)
+			}
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Sprintf("%s: %v", gid, err))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	sort.Strings(errs)
+
+	return errors.New(fmt.Sprintf("gate call failed, %s", strings.Join(errs, "; ")))
+}
+
 // Disconnect 断开连接
 func (l *Link) Disconnect(ctx context.Context, args *DisconnectArgs) error {
+	if zl := l.zone(args.Zone); zl != l {
+		return zl.Disconnect(ctx, args)
+	}
+
 	switch args.Kind {
 	case session.Conn:
 		return l.directDisconnect(ctx, args.GID, args.Kind, args.Target, args.IsForce)
@@ -439,13 +803,103 @@ func (l *Link) indirectDisconnect(ctx context.Context, uid int64, isForce bool)
 	return err
 }
 
+// Kick 踢下线，reason为踢下线原因的错误码
+func (l *Link) Kick(ctx context.Context, gid string, kind session.Kind, target int64, reason int32) error {
+	client, err := l.getGateClientByGID(gid)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Kick(ctx, kind, target, reason)
+	return err
+}
+
+// Resume 恢复断线的用户会话，会话从原网关移交给当前网关
+func (l *Link) Resume(ctx context.Context, gid string, uid int64, nonce string) (*transport.ResumeReply, error) {
+	client, err := l.getGateClientByGID(gid)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, _, err := client.Resume(ctx, uid, nonce, l.opts.GID)
+	return reply, err
+}
+
 // Deliver 投递消息给节点处理
 func (l *Link) Deliver(ctx context.Context, args *DeliverArgs) error {
+	if zl := l.zone(args.Zone); zl != l {
+		return zl.Deliver(ctx, args)
+	}
+
+	arguments, err := l.toDeliverArgs(args)
+	if err != nil {
+		return err
+	}
+
+	if args.NID != "" {
+		client, err := l.getNodeClientByNID(args.NID)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.Deliver(ctx, arguments)
+		return err
+	} else {
+		_, err := l.doNodeRPC(ctx, arguments.Message.Route, args.UID, func(ctx context.Context, client transport.NodeClient) (bool, interface{}, error) {
+			miss, err := client.Deliver(ctx, arguments)
+			return miss, nil, err
+		})
+		return err
+	}
+}
+
+// Invoke 调用节点路由并等待响应
+func (l *Link) Invoke(ctx context.Context, args *DeliverArgs) (*transport.Message, error) {
+	if zl := l.zone(args.Zone); zl != l {
+		return zl.Invoke(ctx, args)
+	}
+
+	arguments, err := l.toDeliverArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.NID != "" {
+		client, err := l.getNodeClientByNID(args.NID)
+		if err != nil {
+			return nil, err
+		}
+
+		reply, _, err := client.Invoke(ctx, arguments)
+		return reply, err
+	} else {
+		reply, err := l.doNodeRPC(ctx, arguments.Message.Route, args.UID, func(ctx context.Context, client transport.NodeClient) (bool, interface{}, error) {
+			reply, miss, err := client.Invoke(ctx, arguments)
+			return miss, reply, err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// 重试后仍未命中用户所在节点
+		message, _ := reply.(*transport.Message)
+		if message == nil {
+			return nil, ErrNotFoundUserSource
+		}
+
+		return message, nil
+	}
+}
+
+// 构建投递参数
+func (l *Link) toDeliverArgs(args *DeliverArgs) (*transport.DeliverArgs, error) {
 	arguments := &transport.DeliverArgs{
-		GID: l.opts.GID,
-		NID: l.opts.NID,
-		CID: args.CID,
-		UID: args.UID,
+		GID:    l.opts.GID,
+		NID:    l.opts.NID,
+		CID:    args.CID,
+		UID:    args.UID,
+		Attrs:  args.Attrs,
+		Claims: args.Claims,
 	}
 
 	switch msg := args.Message.(type) {
@@ -458,7 +912,7 @@ func (l *Link) Deliver(ctx context.Context, args *DeliverArgs) error {
 	case *Message:
 		buffer, err := l.toBuffer(msg.Data, false)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		arguments.Message = &transport.Message{
 			Seq:    msg.Seq,
@@ -466,28 +920,40 @@ func (l *Link) Deliver(ctx context.Context, args *DeliverArgs) error {
 			Buffer: buffer,
 		}
 	default:
-		return ErrInvalidMessage
+		return nil, ErrInvalidMessage
 	}
 
-	if args.NID != "" {
-		client, err := l.getNodeClientByNID(args.NID)
-		if err != nil {
-			return err
+	return arguments, nil
+}
+
+// Trigger 触发事件
+// 事件投递到用户在各个节点分组中绑定的节点，用户未绑定任何节点时返回ErrNotFoundUserSource
+func (l *Link) Trigger(ctx context.Context, event cluster.Event, uid int64) error {
+	var (
+		err   error
+		found bool
+	)
+
+	for _, group := range l.nodeRouter.NodeGroups() {
+		switch e := l.trigger(ctx, event, uid, group); e {
+		case nil:
+			found = true
+		case ErrNotFoundUserSource, router.ErrNotFoundEndpoint:
+		default:
+			found = true
+			err = e
 		}
+	}
 
-		_, err = client.Deliver(ctx, arguments)
-		return err
-	} else {
-		_, err := l.doNodeRPC(ctx, arguments.Message.Route, args.UID, func(ctx context.Context, client transport.NodeClient) (bool, interface{}, error) {
-			miss, err := client.Deliver(ctx, arguments)
-			return miss, nil, err
-		})
-		return err
+	if !found {
+		return ErrNotFoundUserSource
 	}
+
+	return err
 }
 
-// Trigger 触发事件
-func (l *Link) Trigger(ctx context.Context, event cluster.Event, uid int64) error {
+// 触发用户在节点分组中绑定的节点的事件
+func (l *Link) trigger(ctx context.Context, event cluster.Event, uid int64, group string) error {
 	var (
 		err    error
 		nid    string
@@ -502,7 +968,7 @@ func (l *Link) Trigger(ctx context.Context, event cluster.Event, uid int64) erro
 	)
 
 	for i := 0; i < 2; i++ {
-		if nid, err = l.LocateNode(ctx, uid); err != nil {
+		if nid, err = l.LocateNode(ctx, uid, group); err != nil {
 			return err
 		}
 
@@ -523,7 +989,7 @@ func (l *Link) Trigger(ctx context.Context, event cluster.Event, uid int64) erro
 
 		miss, _ := client.Trigger(ctx, args)
 		if miss {
-			l.sourceNode.Delete(uid)
+			l.sourceNode.Delete(nodeKey{uid: uid, group: group})
 			continue
 		}
 
@@ -581,6 +1047,7 @@ func (l *Link) doNodeRPC(ctx context.Context, route int32, uid int64, fn func(ct
 		client    transport.NodeClient
 		entity    *router.Route
 		ep        *router.Endpoint
+		release   func()
 		continued bool
 		reply     interface{}
 	)
@@ -591,7 +1058,12 @@ func (l *Link) doNodeRPC(ctx context.Context, route int32, uid int64, fn func(ct
 
 	for i := 0; i < 2; i++ {
 		if entity.Stateful() {
-			if nid, err = l.LocateNode(ctx, uid); err != nil {
+			if entity.Sharding() {
+				nid, err = entity.FindShardOwner(uid)
+			} else {
+				nid, err = l.LocateNode(ctx, uid, entity.Group())
+			}
+			if err != nil {
 				return nil, err
 			}
 			if nid == prev {
@@ -600,19 +1072,21 @@ func (l *Link) doNodeRPC(ctx context.Context, route int32, uid int64, fn func(ct
 			prev = nid
 		}
 
-		ep, err = entity.FindEndpoint(nid)
+		ep, release, err = entity.Acquire(nid, uid)
 		if err != nil {
 			return nil, err
 		}
 
 		client, err = l.opts.Transporter.NewNodeClient(ep)
 		if err != nil {
+			release()
 			return nil, err
 		}
 
 		continued, reply, err = fn(ctx, client)
+		release()
 		if continued {
-			l.sourceNode.Delete(uid)
+			l.sourceNode.Delete(nodeKey{uid: uid, group: entity.Group()})
 			continue
 		}
 
@@ -622,6 +1096,21 @@ func (l *Link) doNodeRPC(ctx context.Context, route int32, uid int64, fn func(ct
 	return reply, err
 }
 
+// FindShardOwner 查询用户在分片路由中的归属节点
+func (l *Link) FindShardOwner(route int32, uid int64) (string, error) {
+	entity, err := l.nodeRouter.FindNodeRoute(route)
+	if err != nil {
+		return "", err
+	}
+
+	return entity.FindShardOwner(uid)
+}
+
+// OnRebalance 监听分片路由的哈希环变化
+func (l *Link) OnRebalance(handler router.RebalanceHandler) {
+	l.nodeRouter.OnRebalance(handler)
+}
+
 // 消息转buffer
 func (l *Link) toBuffer(message interface{}, encrypt bool) ([]byte, error) {
 	if message == nil {
@@ -673,19 +1162,23 @@ func (l *Link) getNodeClientByNID(nid string) (transport.NodeClient, error) {
 }
 
 // WatchServiceInstance 监听服务实例
-func (l *Link) WatchServiceInstance(ctx context.Context, kinds ...cluster.Kind) {
+func (l *Link) WatchServiceInstance(ctx context.Context, kinds ...cluster.Kind) error {
 	for _, kind := range kinds {
-		l.watchServiceInstance(ctx, kind)
+		if err := l.watchServiceInstance(ctx, kind); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
 // 监听服务实例
-func (l *Link) watchServiceInstance(ctx context.Context, kind cluster.Kind) {
+func (l *Link) watchServiceInstance(ctx context.Context, kind cluster.Kind) error {
 	rctx, rcancel := context.WithTimeout(ctx, 10*time.Second)
 	watcher, err := l.opts.Registry.Watch(rctx, string(kind))
 	rcancel()
 	if err != nil {
-		log.Fatalf("the service instance watch failed: %v", err)
+		return err
 	}
 
 	go func() {
@@ -702,22 +1195,20 @@ func (l *Link) watchServiceInstance(ctx context.Context, kind cluster.Kind) {
 				continue
 			}
 
-			if kind == cluster.Node {
-				l.nodeRouter.ReplaceServices(services...)
-			} else {
-				l.gateRouter.ReplaceServices(services...)
-			}
+			l.replaceServices(kind, services)
 		}
 	}()
+
+	return nil
 }
 
 // WatchUserLocate 监听用户定位
-func (l *Link) WatchUserLocate(ctx context.Context, kinds ...cluster.Kind) {
+func (l *Link) WatchUserLocate(ctx context.Context, kinds ...cluster.Kind) error {
 	rctx, rcancel := context.WithTimeout(ctx, 10*time.Second)
-	watcher, err := l.opts.Locator.Watch(rctx, kinds...)
+	watcher, err := l.locator.Watch(rctx, kinds...)
 	rcancel()
 	if err != nil {
-		log.Fatalf("user locate event watch failed: %v", err)
+		return err
 	}
 
 	go func() {
@@ -734,12 +1225,19 @@ func (l *Link) WatchUserLocate(ctx context.Context, kinds ...cluster.Kind) {
 				continue
 			}
 			for _, event := range events {
-				var source *sync.Map
+				var (
+					source *sync.Map
+					key    interface{}
+				)
 				switch event.InsKind {
 				case cluster.Gate:
-					source = &l.sourceGate
+					// 网关的分组定位不是用户连接的网关，例如多端登录的设备列表
+					if event.InsGroup != "" {
+						continue
+					}
+					source, key = &l.sourceGate, event.UID
 				case cluster.Node:
-					source = &l.sourceNode
+					source, key = &l.sourceNode, nodeKey{uid: event.UID, group: event.InsGroup}
 				}
 
 				if source == nil {
@@ -748,11 +1246,13 @@ func (l *Link) WatchUserLocate(ctx context.Context, kinds ...cluster.Kind) {
 
 				switch event.Type {
 				case locate.SetLocation:
-					source.Store(event.UID, event.InsID)
+					source.Store(key, event.InsID)
 				case locate.RemLocation:
-					source.Delete(event.UID)
+					source.Delete(key)
 				}
 			}
 		}
 	}()
+
+	return nil
 }
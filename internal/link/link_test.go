@@ -0,0 +1,396 @@
+package link
+
+import (
+	"context"
+	"fmt"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/encoding/json"
+	"github.com/dobyte/due/locate"
+	"github.com/dobyte/due/registry"
+	"github.com/dobyte/due/router"
+	"github.com/dobyte/due/transport"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// 内存定位器
+type memLocator struct {
+	mu        sync.Mutex
+	locations map[string]string
+	zones     map[string]*memLocator
+}
+
+func newMemLocator() *memLocator {
+	return &memLocator{locations: make(map[string]string), zones: make(map[string]*memLocator)}
+}
+
+func locationKey(uid int64, kind cluster.Kind, group string) string {
+	return fmt.Sprintf("%s:%s:%d", kind, group, uid)
+}
+
+func (l *memLocator) Get(ctx context.Context, uid int64, kind cluster.Kind, group string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.locations[locationKey(uid, kind, group)], nil
+}
+
+func (l *memLocator) Set(ctx context.Context, uid int64, kind cluster.Kind, group string, insID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.locations[locationKey(uid, kind, group)] = insID
+
+	return nil
+}
+
+func (l *memLocator) Rem(ctx context.Context, uid int64, kind cluster.Kind, group string, insID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := locationKey(uid, kind, group)
+	if l.locations[key] == insID {
+		delete(l.locations, key)
+	}
+
+	return nil
+}
+
+func (l *memLocator) Watch(ctx context.Context, kinds ...cluster.Kind) (locate.Watcher, error) {
+	return nil, nil
+}
+
+func (l *memLocator) Zone(zone string) locate.Locator {
+	if zone == "" {
+		return l
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	zl, ok := l.zones[zone]
+	if !ok {
+		zl = newMemLocator()
+		l.zones[zone] = zl
+	}
+
+	return zl
+}
+
+// 支持统计实例用户数量的内存定位器
+type countLocator struct {
+	*memLocator
+}
+
+func (l *countLocator) Count(ctx context.Context, kind cluster.Kind, insID string) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := int64(0)
+	for key, id := range l.locations {
+		if id == insID && strings.HasPrefix(key, string(kind)+":") {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+func (l *countLocator) Zone(zone string) locate.Locator {
+	if zone == "" {
+		return l
+	}
+
+	return &countLocator{l.memLocator.Zone(zone).(*memLocator)}
+}
+
+// 记录频道成员所在网关的内存定位器
+type channelLocator struct {
+	*memLocator
+	channels map[string]map[string]struct{}
+}
+
+func newChannelLocator() *channelLocator {
+	return &channelLocator{memLocator: newMemLocator(), channels: make(map[string]map[string]struct{})}
+}
+
+func (l *channelLocator) Zone(zone string) locate.Locator {
+	if zone == "" {
+		return l
+	}
+
+	return l.memLocator.Zone(zone)
+}
+
+func (l *channelLocator) AddChannelGate(ctx context.Context, channel string, gid string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	gates, ok := l.channels[channel]
+	if !ok {
+		gates = make(map[string]struct{})
+		l.channels[channel] = gates
+	}
+	gates[gid] = struct{}{}
+
+	return nil
+}
+
+func (l *channelLocator) RemChannelGate(ctx context.Context, channel string, gid string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.channels[channel], gid)
+
+	return nil
+}
+
+func (l *channelLocator) ChannelGates(ctx context.Context, channel string) ([]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	gids := make([]string, 0, len(l.channels[channel]))
+	for gid := range l.channels[channel] {
+		gids = append(gids, gid)
+	}
+
+	return gids, nil
+}
+
+// 记录频道调用的传输器，网关客户端按端口地址区分网关
+type channelTransporter struct {
+	transport.Transporter
+	mu        sync.Mutex
+	published []string
+	left      []string
+}
+
+func (tr *channelTransporter) NewGateClient(ep *router.Endpoint) (transport.GateClient, error) {
+	return &channelGateClient{tr: tr, gid: ep.Address()}, nil
+}
+
+func (tr *channelTransporter) snapshot() (published, left []string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	published = append(published, tr.published...)
+	left = append(left, tr.left...)
+	sort.Strings(published)
+	sort.Strings(left)
+
+	return
+}
+
+type channelGateClient struct {
+	transport.GateClient
+	tr  *channelTransporter
+	gid string
+}
+
+func (c *channelGateClient) LeaveChannel(ctx context.Context, channel string, uids []int64) error {
+	c.tr.mu.Lock()
+	defer c.tr.mu.Unlock()
+
+	c.tr.left = append(c.tr.left, c.gid)
+
+	return nil
+}
+
+func (c *channelGateClient) PublishChannel(ctx context.Context, channel string, message *transport.Message) (int64, error) {
+	c.tr.mu.Lock()
+	defer c.tr.mu.Unlock()
+
+	c.tr.published = append(c.tr.published, c.gid)
+
+	return 1, nil
+}
+
+func newChannelLink(t *testing.T, locator locate.Locator, tr *channelTransporter, gids ...string) *Link {
+	l := NewLink(&Options{Codec: json.NewCodec(), Locator: locator, Transporter: tr})
+
+	for _, gid := range gids {
+		if err := l.gateRouter.AddService(&registry.ServiceInstance{
+			ID:       gid,
+			Kind:     cluster.Gate,
+			Endpoint: fmt.Sprintf("test://%s", gid),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return l
+}
+
+func TestLink_ChannelMemberGates(t *testing.T) {
+	ctx := context.Background()
+	locator := newChannelLocator()
+	tr := &channelTransporter{}
+	l := newChannelLink(t, locator, tr, "gate-1", "gate-2", "gate-3")
+
+	// gate-4已下线，仍残留在频道登记中
+	for _, gid := range []string{"gate-1", "gate-3", "gate-4"} {
+		if err := locator.AddChannelGate(ctx, "room", gid); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	total, err := l.PublishChannel(ctx, "room", &Message{Route: 1, Data: []byte("hello")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Fatalf("expected 2 pushes, got %d", total)
+	}
+
+	if err = l.DismissChannel(ctx, "room"); err != nil {
+		t.Fatal(err)
+	}
+
+	published, left := tr.snapshot()
+	if strings.Join(published, ",") != "gate-1,gate-3" {
+		t.Fatalf("the message should be published on the member gates only, got %v", published)
+	}
+	if strings.Join(left, ",") != "gate-1,gate-3" {
+		t.Fatalf("the channel should be dismissed on the member gates only, got %v", left)
+	}
+}
+
+func TestLink_ChannelWithoutMembers(t *testing.T) {
+	ctx := context.Background()
+	tr := &channelTransporter{}
+	l := newChannelLink(t, newChannelLocator(), tr, "gate-1", "gate-2")
+
+	if total, err := l.PublishChannel(ctx, "room", &Message{Route: 1, Data: []byte("hello")}); err != nil || total != 0 {
+		t.Fatalf("expected no pushes, got %d: %v", total, err)
+	}
+
+	if published, _ := tr.snapshot(); len(published) != 0 {
+		t.Fatalf("no gate should be called without members, got %v", published)
+	}
+}
+
+func TestLink_ChannelNotSupported(t *testing.T) {
+	ctx := context.Background()
+	tr := &channelTransporter{}
+	l := newChannelLink(t, newMemLocator(), tr, "gate-1", "gate-2")
+
+	// 定位器不登记频道成员所在网关时，发布到所有网关
+	if _, err := l.PublishChannel(ctx, "room", &Message{Route: 1, Data: []byte("hello")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if published, _ := tr.snapshot(); strings.Join(published, ",") != "gate-1,gate-2" {
+		t.Fatalf("the message should be published on all gates, got %v", published)
+	}
+}
+
+func TestLink_NodeGroups(t *testing.T) {
+	ctx := context.Background()
+	locator := newMemLocator()
+	l := NewLink(&Options{Locator: locator})
+
+	if err := l.BindNode(ctx, 1, "a", "node-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.BindNode(ctx, 1, "b", "node-b"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, link := range []*Link{l, NewLink(&Options{Locator: locator})} {
+		for group, expected := range map[string]string{"a": "node-a", "b": "node-b"} {
+			nid, err := link.LocateNode(ctx, 1, group)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if nid != expected {
+				t.Fatalf("group %s: expected %s, got %s", group, expected, nid)
+			}
+		}
+
+		if _, err := link.LocateNode(ctx, 1, "c"); err != ErrNotFoundUserSource {
+			t.Fatalf("expected not found in an unbound group, got %v", err)
+		}
+	}
+
+	if err := l.UnbindNode(ctx, 1, "a", "node-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := l.LocateNode(ctx, 1, "a"); err != ErrNotFoundUserSource {
+		t.Fatalf("expected not found after unbind, got %v", err)
+	}
+
+	if nid, err := l.LocateNode(ctx, 1, "b"); err != nil || nid != "node-b" {
+		t.Fatalf("the binding of group b should be kept: %s, %v", nid, err)
+	}
+
+	if n, err := l.CountNodeUsers(ctx, "node-b"); err != nil || n != 1 {
+		t.Fatalf("expected 1 user bound to node-b, got %d: %v", n, err)
+	}
+}
+
+func TestLink_CountNodeUsers(t *testing.T) {
+	ctx := context.Background()
+	locator := &countLocator{newMemLocator()}
+	l := NewLink(&Options{Locator: locator})
+
+	// 其他实例绑定的用户不在本地缓存中，须以定位器为准
+	for uid := int64(1); uid <= 2; uid++ {
+		if err := locator.Set(ctx, uid, cluster.Node, "", "node"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n, err := l.CountNodeUsers(ctx, "node"); err != nil || n != 2 {
+		t.Fatalf("expected 2 users bound to the node, got %d: %v", n, err)
+	}
+
+	if err := l.UnbindNode(ctx, 1, "", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := l.CountNodeUsers(ctx, "node"); err != nil || n != 1 {
+		t.Fatalf("expected 1 user bound to the node, got %d: %v", n, err)
+	}
+}
+
+func TestLink_Zone(t *testing.T) {
+	ctx := context.Background()
+	locator := newMemLocator()
+	l := NewLink(&Options{Locator: locator})
+	zl := l.zone("z1")
+
+	if err := zl.BindNode(ctx, 1, "a", "node-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := l.LocateNode(ctx, 1, "a"); err != ErrNotFoundUserSource {
+		t.Fatalf("the zones should be isolated, got %v", err)
+	}
+
+	// 跨区链接不监听定位变化，定位变化后须立即可见
+	if err := locator.Zone("z1").Set(ctx, 1, cluster.Node, "a", "node-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if nid, err := zl.LocateNode(ctx, 1, "a"); err != nil || nid != "node-2" {
+		t.Fatalf("the cross-zone link should not cache locations: %s, %v", nid, err)
+	}
+}
+
+func TestLink_ZoneNotSupported(t *testing.T) {
+	ctx := context.Background()
+	locator := struct{ locate.Locator }{newMemLocator()}
+	l := NewLink(&Options{Zone: "z0", Locator: locator})
+
+	if err := l.BindNode(ctx, 1, "a", "node-1"); err != nil {
+		t.Fatalf("the current zone should share the locator: %v", err)
+	}
+
+	if _, err := l.zone("z1").LocateNode(ctx, 1, "a"); err != locate.ErrZoneNotSupported {
+		t.Fatalf("expected zone not supported, got %v", err)
+	}
+}
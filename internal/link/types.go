@@ -6,6 +6,7 @@ type GetIPArgs struct {
 	GID    string       // 网关ID，会话类型为用户时可忽略此参数
 	Kind   session.Kind // 会话类型，session.Conn 或 session.User
 	Target int64        // 会话目标，CID 或 UID
+	Zone   string       // 游戏区，为空时为当前游戏区
 }
 
 type Message struct {
@@ -19,6 +20,7 @@ type PushArgs struct {
 	Kind    session.Kind // 会话类型，session.Conn 或 session.User
 	Target  int64        // 会话目标，CID 或 UID
 	Message *Message     // 消息
+	Zone    string       // 游戏区，为空时为当前游戏区
 }
 
 type MulticastArgs struct {
@@ -26,18 +28,46 @@ type MulticastArgs struct {
 	Kind    session.Kind // 会话类型，session.Conn 或 session.User
 	Targets []int64      // 会话目标，CID 或 UID
 	Message *Message     // 消息
+	Zone    string       // 游戏区，为空时为当前游戏区
 }
 
 type BroadcastArgs struct {
 	Kind    session.Kind // 会话类型，session.Conn 或 session.User
 	Message *Message     // 消息
+	Zone    string       // 游戏区，为空时为当前游戏区
 }
 
 type DeliverArgs struct {
-	NID     string      // 接收节点。存在接收节点时，消息会直接投递给接收节点；不存在接收节点时，系统定位用户所在节点，然后投递。
-	CID     int64       // 连接ID
-	UID     int64       // 用户ID
-	Message interface{} // 消息
+	NID     string            // 接收节点。存在接收节点时，消息会直接投递给接收节点；不存在接收节点时，系统定位用户所在节点，然后投递。
+	CID     int64             // 连接ID
+	UID     int64             // 用户ID
+	Attrs   map[string]string // 会话属性
+	Claims  map[string]string // 鉴权声明
+	Message interface{}       // 消息
+	Zone    string            // 游戏区，为空时为当前游戏区
+}
+
+type GetAttrsArgs struct {
+	GID    string       // 网关ID，会话类型为用户时可忽略此参数
+	Kind   session.Kind // 会话类型，session.Conn 或 session.User
+	Target int64        // 会话目标，CID 或 UID
+	Zone   string       // 游戏区，为空时为当前游戏区
+}
+
+type SetAttrsArgs struct {
+	GID    string            // 网关ID，会话类型为用户时可忽略此参数
+	Kind   session.Kind      // 会话类型，session.Conn 或 session.User
+	Target int64             // 会话目标，CID 或 UID
+	Attrs  map[string]string // 会话属性
+	Zone   string            // 游戏区，为空时为当前游戏区
+}
+
+type DelAttrsArgs struct {
+	GID    string       // 网关ID，会话类型为用户时可忽略此参数
+	Kind   session.Kind // 会话类型，session.Conn 或 session.User
+	Target int64        // 会话目标，CID 或 UID
+	Keys   []string     // 属性键
+	Zone   string       // 游戏区，为空时为当前游戏区
 }
 
 type DisconnectArgs struct {
@@ -45,4 +75,5 @@ type DisconnectArgs struct {
 	Kind    session.Kind // 会话类型，session.Conn 或 session.User
 	Target  int64        // 会话目标，CID 或 UID
 	IsForce bool         // 是否强制断开
+	Zone    string       // 游戏区，为空时为当前游戏区
 }
@@ -0,0 +1,137 @@
+package link
+
+import (
+	"context"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/locate"
+	"github.com/dobyte/due/log"
+	"github.com/dobyte/due/registry"
+	"github.com/dobyte/due/router"
+)
+
+// 获取指定游戏区的链接，zone为空时为当前游戏区
+// 跨区链接与当前游戏区的链接共用服务实例监听，使用独立的路由器及指定游戏区的定位器；
+// 跨区链接不监听用户定位变化，因此不缓存用户定位，每次定位均查询定位器
+func (l *Link) zone(zone string) *Link {
+	if zone == "" || zone == l.opts.Zone {
+		return l
+	}
+
+	if l.root != nil {
+		return l.root.zone(zone)
+	}
+
+	l.zrw.RLock()
+	zl, ok := l.zones[zone]
+	l.zrw.RUnlock()
+	if ok {
+		return zl
+	}
+
+	l.zrw.Lock()
+	defer l.zrw.Unlock()
+
+	if zl, ok = l.zones[zone]; ok {
+		return zl
+	}
+
+	opts := *l.opts
+	opts.Zone = zone
+
+	zl = &Link{
+		opts:       &opts,
+		gateRouter: router.NewRouter(),
+		nodeRouter: router.NewRouter(),
+		root:       l,
+	}
+
+	if l.opts.Locator != nil {
+		zl.locator = zoneLocator(l.opts.Locator, zone, true)
+	}
+
+	for kind, services := range l.services {
+		zl.router(kind).ReplaceServices(filterServices(services, zone)...)
+	}
+
+	l.zones[zone] = zl
+
+	return zl
+}
+
+// 是否缓存用户定位，跨区链接不监听用户定位变化，缓存的定位无法及时失效
+func (l *Link) cacheable() bool {
+	return l.root == nil
+}
+
+// 获取游戏区的定位器
+// 定位器不支持游戏区时，当前游戏区沿用原定位器，跨区链接的定位操作均返回ErrZoneNotSupported
+func zoneLocator(locator locate.Locator, zone string, cross bool) locate.Locator {
+	zl, err := locate.Zone(locator, zone)
+	if err == nil {
+		return zl
+	}
+
+	if !cross {
+		log.Warnf("the locator does not support zones, zone %q shares the locations of the default zone", zone)
+		return locator
+	}
+
+	return unsupportedLocator{}
+}
+
+// 不支持游戏区的定位器
+type unsupportedLocator struct{}
+
+func (unsupportedLocator) Get(context.Context, int64, cluster.Kind, string) (string, error) {
+	return "", locate.ErrZoneNotSupported
+}
+
+func (unsupportedLocator) Set(context.Context, int64, cluster.Kind, string, string) error {
+	return locate.ErrZoneNotSupported
+}
+
+func (unsupportedLocator) Rem(context.Context, int64, cluster.Kind, string, string) error {
+	return locate.ErrZoneNotSupported
+}
+
+func (unsupportedLocator) Watch(context.Context, ...cluster.Kind) (locate.Watcher, error) {
+	return nil, locate.ErrZoneNotSupported
+}
+
+// 替换服务实例，按游戏区分发到当前游戏区及跨区链接的路由器
+func (l *Link) replaceServices(kind cluster.Kind, services []*registry.ServiceInstance) {
+	l.zrw.Lock()
+	l.services[kind] = services
+	zones := make([]*Link, 0, len(l.zones))
+	for _, zl := range l.zones {
+		zones = append(zones, zl)
+	}
+	l.zrw.Unlock()
+
+	l.router(kind).ReplaceServices(filterServices(services, l.opts.Zone)...)
+
+	for _, zl := range zones {
+		zl.router(kind).ReplaceServices(filterServices(services, zl.opts.Zone)...)
+	}
+}
+
+// 获取服务实例类型对应的路由器
+func (l *Link) router(kind cluster.Kind) *router.Router {
+	if kind == cluster.Node {
+		return l.nodeRouter
+	}
+
+	return l.gateRouter
+}
+
+// 筛选游戏区的服务实例
+func filterServices(services []*registry.ServiceInstance, zone string) []*registry.ServiceInstance {
+	list := make([]*registry.ServiceInstance, 0, len(services))
+	for _, service := range services {
+		if service.Zone == zone {
+			list = append(list, service)
+		}
+	}
+
+	return list
+}
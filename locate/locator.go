@@ -10,19 +10,72 @@ package locate
 import (
 	"context"
 	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/errors"
 )
 
+var (
+	ErrZoneNotSupported   = errors.New("the locator does not support zones")
+	ErrDeviceNotSupported = errors.New("the locator does not support login devices")
+)
+
+// Locator 用户定位器
+// 用户在每种实例类型的每个分组中仅有一个定位，网关不区分分组，insGroup为空时为默认分组
 type Locator interface {
 	// Get 获取用户定位
-	Get(ctx context.Context, uid int64, insKind cluster.Kind) (string, error)
+	Get(ctx context.Context, uid int64, insKind cluster.Kind, insGroup string) (string, error)
 	// Set 设置用户定位
-	Set(ctx context.Context, uid int64, insKind cluster.Kind, insID string) error
+	Set(ctx context.Context, uid int64, insKind cluster.Kind, insGroup string, insID string) error
 	// Rem 移除用户定位
-	Rem(ctx context.Context, uid int64, insKind cluster.Kind, insID string) error
+	Rem(ctx context.Context, uid int64, insKind cluster.Kind, insGroup string, insID string) error
 	// Watch 监听用户定位变化
 	Watch(ctx context.Context, insKinds ...cluster.Kind) (Watcher, error)
 }
 
+// ZoneLocator 游戏区定位器，定位器可选实现该接口以支持游戏区隔离
+type ZoneLocator interface {
+	// Zone 获取指定游戏区的定位器，不同游戏区的用户定位互相隔离，zone为空时返回当前定位器
+	Zone(zone string) Locator
+}
+
+// DeviceLocator 登录设备定位器，定位器可选实现该接口以支持多端登录
+// 登录设备为不透明的字符串，按登录时间排序，设备的增删须为原子操作且不发布定位事件
+type DeviceLocator interface {
+	// AddDevice 添加用户的登录设备，设备数超过上限时移除最早登录的设备并返回
+	AddDevice(ctx context.Context, uid int64, device string, limit int) (evicted []string, err error)
+	// RemDevice 移除用户的登录设备，返回剩余的登录设备，最近登录的设备在最后
+	RemDevice(ctx context.Context, uid int64, device string) (remains []string, err error)
+}
+
+// CountLocator 实例用户计数定位器，定位器可选实现该接口以统计定位到指定实例的用户数量
+type CountLocator interface {
+	// Count 统计定位到指定实例的用户数量
+	Count(ctx context.Context, insKind cluster.Kind, insID string) (int64, error)
+}
+
+// ChannelLocator 频道定位器，定位器可选实现该接口以记录持有频道成员的网关
+// 网关在本地频道成员由无到有时登记、由有到无时注销，发布及解散频道时仅调用已登记的网关
+type ChannelLocator interface {
+	// AddChannelGate 登记持有频道成员的网关
+	AddChannelGate(ctx context.Context, channel string, gid string) error
+	// RemChannelGate 注销持有频道成员的网关
+	RemChannelGate(ctx context.Context, channel string, gid string) error
+	// ChannelGates 获取持有频道成员的网关
+	ChannelGates(ctx context.Context, channel string) ([]string, error)
+}
+
+// Zone 获取指定游戏区的定位器，zone为空时返回原定位器；定位器未实现ZoneLocator时返回ErrZoneNotSupported
+func Zone(locator Locator, zone string) (Locator, error) {
+	if zl, ok := locator.(ZoneLocator); ok {
+		return zl.Zone(zone), nil
+	}
+
+	if zone == "" {
+		return locator, nil
+	}
+
+	return nil, ErrZoneNotSupported
+}
+
 type Watcher interface {
 	// Next 返回用户位置列表
 	Next() ([]*Event, error)
@@ -39,6 +92,8 @@ type Event struct {
 	InsID string `json:"ins_id"`
 	// 实例类型
 	InsKind cluster.Kind `json:"ins_kind"`
+	// 实例分组
+	InsGroup string `json:"ins_group"`
 }
 
 type EventType int
@@ -12,14 +12,49 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 const (
 	userLocationsKey = "%s:locate:user:%d:locations" // hash
+	userDevicesKey   = "%s:locate:user:%d:devices"   // sorted set
+	instanceUsersKey = "%s:locate:%s:%s:users"       // set
+	channelGatesKey  = "%s:locate:channels:%s:gates" // set
 	channelEventKey  = "%s:locate:channel:%v:event"  // channel
 )
 
-var _ locate.Locator = &Locator{}
+var (
+	_ locate.Locator        = &Locator{}
+	_ locate.ZoneLocator    = &Locator{}
+	_ locate.DeviceLocator  = &Locator{}
+	_ locate.CountLocator   = &Locator{}
+	_ locate.ChannelLocator = &Locator{}
+)
+
+// 设置用户定位，返回原定位
+var setLocationScript = redis.NewScript(`
+local old = redis.call('HGET', KEYS[1], ARGV[1])
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+return old
+`)
+
+// 添加登录设备，设备数超过上限时移除并返回最早登录的设备
+var addDeviceScript = redis.NewScript(`
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+local overflow = redis.call('ZCARD', KEYS[1]) - tonumber(ARGV[3])
+if overflow <= 0 then
+	return {}
+end
+local evicted = redis.call('ZRANGE', KEYS[1], 0, overflow - 1)
+redis.call('ZREMRANGEBYRANK', KEYS[1], 0, overflow - 1)
+return evicted
+`)
+
+// 移除登录设备，返回剩余的登录设备
+var remDeviceScript = redis.NewScript(`
+redis.call('ZREM', KEYS[1], ARGV[1])
+return redis.call('ZRANGE', KEYS[1], 0, -1)
+`)
 
 type Locator struct {
 	ctx      context.Context
@@ -27,6 +62,7 @@ type Locator struct {
 	opts     *options
 	sfg      singleflight.Group // singleFlight
 	watchers sync.Map
+	zones    sync.Map // 游戏区定位器
 }
 
 func NewLocator(opts ...Option) *Locator {
@@ -57,10 +93,11 @@ func NewLocator(opts ...Option) *Locator {
 }
 
 // Get 获取用户定位
-func (l *Locator) Get(ctx context.Context, uid int64, insKind cluster.Kind) (string, error) {
+func (l *Locator) Get(ctx context.Context, uid int64, insKind cluster.Kind, insGroup string) (string, error) {
 	key := fmt.Sprintf(userLocationsKey, l.opts.prefix, uid)
-	val, err, _ := l.sfg.Do(key+string(insKind), func() (interface{}, error) {
-		val, err := l.opts.client.HGet(ctx, key, string(insKind)).Result()
+	field := locationField(insKind, insGroup)
+	val, err, _ := l.sfg.Do(key+field, func() (interface{}, error) {
+		val, err := l.opts.client.HGet(ctx, key, field).Result()
 		if err != nil && err != redis.Nil {
 			return "", err
 		}
@@ -75,14 +112,27 @@ func (l *Locator) Get(ctx context.Context, uid int64, insKind cluster.Kind) (str
 }
 
 // Set 设置用户定位
-func (l *Locator) Set(ctx context.Context, uid int64, insKind cluster.Kind, insID string) error {
+// 同时将用户从原实例的用户集合移入新实例的用户集合，用于统计实例用户数量
+func (l *Locator) Set(ctx context.Context, uid int64, insKind cluster.Kind, insGroup string, insID string) error {
 	key := fmt.Sprintf(userLocationsKey, l.opts.prefix, uid)
-	err := l.opts.client.HSet(ctx, key, string(insKind), insID).Err()
-	if err != nil {
+	oldInsID, err := setLocationScript.Run(ctx, l.opts.client, []string{key}, locationField(insKind, insGroup), insID).Text()
+	if err != nil && err != redis.Nil {
 		return err
 	}
 
-	err = l.publish(ctx, uid, insKind, insID, locate.SetLocation)
+	if oldInsID != insID {
+		if oldInsID != "" {
+			if err = l.opts.client.SRem(ctx, l.instanceUsersKey(insKind, oldInsID), uid).Err(); err != nil {
+				return err
+			}
+		}
+
+		if err = l.opts.client.SAdd(ctx, l.instanceUsersKey(insKind, insID), uid).Err(); err != nil {
+			return err
+		}
+	}
+
+	err = l.publish(ctx, uid, insKind, insGroup, insID, locate.SetLocation)
 	if err != nil {
 		log.Errorf("location event publish failed: %v", err)
 	}
@@ -91,8 +141,8 @@ func (l *Locator) Set(ctx context.Context, uid int64, insKind cluster.Kind, insI
 }
 
 // Rem 移除用户定位
-func (l *Locator) Rem(ctx context.Context, uid int64, insKind cluster.Kind, insID string) error {
-	oldInsID, err := l.Get(ctx, uid, insKind)
+func (l *Locator) Rem(ctx context.Context, uid int64, insKind cluster.Kind, insGroup string, insID string) error {
+	oldInsID, err := l.Get(ctx, uid, insKind, insGroup)
 	if err != nil {
 		return err
 	}
@@ -102,12 +152,17 @@ func (l *Locator) Rem(ctx context.Context, uid int64, insKind cluster.Kind, insI
 	}
 
 	key := fmt.Sprintf(userLocationsKey, l.opts.prefix, uid)
-	err = l.opts.client.HDel(ctx, key, string(insKind)).Err()
+	err = l.opts.client.HDel(ctx, key, locationField(insKind, insGroup)).Err()
 	if err != nil {
 		return err
 	}
 
-	err = l.publish(ctx, uid, insKind, insID, locate.RemLocation)
+	err = l.opts.client.SRem(ctx, l.instanceUsersKey(insKind, insID), uid).Err()
+	if err != nil {
+		return err
+	}
+
+	err = l.publish(ctx, uid, insKind, insGroup, insID, locate.RemLocation)
 	if err != nil {
 		log.Errorf("location event publish failed: %v", err)
 	}
@@ -115,12 +170,13 @@ func (l *Locator) Rem(ctx context.Context, uid int64, insKind cluster.Kind, insI
 	return nil
 }
 
-func (l *Locator) publish(ctx context.Context, uid int64, insKind cluster.Kind, insID string, eventType locate.EventType) error {
+func (l *Locator) publish(ctx context.Context, uid int64, insKind cluster.Kind, insGroup string, insID string, eventType locate.EventType) error {
 	msg, err := marshal(&locate.Event{
-		UID:     uid,
-		Type:    eventType,
-		InsID:   insID,
-		InsKind: insKind,
+		UID:      uid,
+		Type:     eventType,
+		InsID:    insID,
+		InsKind:  insKind,
+		InsGroup: insGroup,
 	})
 	if err != nil {
 		return err
@@ -163,6 +219,78 @@ func (l *Locator) Watch(ctx context.Context, insKinds ...cluster.Kind) (locate.W
 	return w.fork(), nil
 }
 
+// AddDevice 添加用户的登录设备，设备数超过上限时移除最早登录的设备并返回
+// 设备列表以登录时间为分值的有序集合存储，增删均通过脚本原子执行，不发布定位事件
+func (l *Locator) AddDevice(ctx context.Context, uid int64, device string, limit int) ([]string, error) {
+	key := fmt.Sprintf(userDevicesKey, l.opts.prefix, uid)
+
+	return addDeviceScript.Run(ctx, l.opts.client, []string{key}, time.Now().UnixNano(), device, limit).StringSlice()
+}
+
+// RemDevice 移除用户的登录设备，返回剩余的登录设备，最近登录的设备在最后
+func (l *Locator) RemDevice(ctx context.Context, uid int64, device string) ([]string, error) {
+	key := fmt.Sprintf(userDevicesKey, l.opts.prefix, uid)
+
+	return remDeviceScript.Run(ctx, l.opts.client, []string{key}, device).StringSlice()
+}
+
+// Count 统计定位到指定实例的用户数量
+func (l *Locator) Count(ctx context.Context, insKind cluster.Kind, insID string) (int64, error) {
+	return l.opts.client.SCard(ctx, l.instanceUsersKey(insKind, insID)).Result()
+}
+
+// AddChannelGate 登记持有频道成员的网关
+func (l *Locator) AddChannelGate(ctx context.Context, channel string, gid string) error {
+	return l.opts.client.SAdd(ctx, fmt.Sprintf(channelGatesKey, l.opts.prefix, channel), gid).Err()
+}
+
+// RemChannelGate 注销持有频道成员的网关
+func (l *Locator) RemChannelGate(ctx context.Context, channel string, gid string) error {
+	return l.opts.client.SRem(ctx, fmt.Sprintf(channelGatesKey, l.opts.prefix, channel), gid).Err()
+}
+
+// ChannelGates 获取持有频道成员的网关
+func (l *Locator) ChannelGates(ctx context.Context, channel string) ([]string, error) {
+	return l.opts.client.SMembers(ctx, fmt.Sprintf(channelGatesKey, l.opts.prefix, channel)).Result()
+}
+
+// 实例用户集合的键
+func (l *Locator) instanceUsersKey(insKind cluster.Kind, insID string) string {
+	return fmt.Sprintf(instanceUsersKey, l.opts.prefix, string(insKind), insID)
+}
+
+// 用户定位的哈希字段，默认分组仅使用实例类型
+func locationField(insKind cluster.Kind, insGroup string) string {
+	if insGroup == "" {
+		return string(insKind)
+	}
+
+	return string(insKind) + ":" + insGroup
+}
+
+// Zone 获取指定游戏区的定位器
+// 游戏区定位器与当前定位器共用redis客户端，定位数据的键及事件频道以游戏区为前缀
+func (l *Locator) Zone(zone string) locate.Locator {
+	if zone == "" {
+		return l
+	}
+
+	if v, ok := l.zones.Load(zone); ok {
+		return v.(*Locator)
+	}
+
+	opts := *l.opts
+	opts.prefix = fmt.Sprintf("%s:%s", l.opts.prefix, zone)
+
+	zl := &Locator{}
+	zl.ctx, zl.cancel = context.WithCancel(l.ctx)
+	zl.opts = &opts
+
+	v, _ := l.zones.LoadOrStore(zone, zl)
+
+	return v.(*Locator)
+}
+
 func marshal(event *locate.Event) (string, error) {
 	buf, err := json.Marshal(event)
 	if err != nil {
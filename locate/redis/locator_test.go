@@ -31,7 +31,7 @@ func TestLocator_Set(t *testing.T) {
 			kind = cluster.Gate
 		}
 
-		err := locator.Set(context.Background(), int64(i), kind, strconv.Itoa(i))
+		err := locator.Set(context.Background(), int64(i), kind, "", strconv.Itoa(i))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -86,7 +86,7 @@ func TestLocator_Watch(t *testing.T) {
 
 func TestLocator_Get(t *testing.T) {
 	for i := 1; i <= 6; i++ {
-		insID, err := locator.Get(context.Background(), int64(i), cluster.Node)
+		insID, err := locator.Get(context.Background(), int64(i), cluster.Node, "")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -97,9 +97,96 @@ func TestLocator_Get(t *testing.T) {
 
 func TestLocator_Rem(t *testing.T) {
 	for i := 1; i <= 6; i++ {
-		err := locator.Rem(context.Background(), int64(i), cluster.Node, strconv.Itoa(i))
+		err := locator.Rem(context.Background(), int64(i), cluster.Node, "", strconv.Itoa(i))
 		if err != nil {
 			t.Fatal(err)
 		}
 	}
 }
+
+func TestLocator_Devices(t *testing.T) {
+	ctx := context.Background()
+	uid := time.Now().UnixNano()
+
+	for i := 1; i <= 3; i++ {
+		evicted, err := locator.AddDevice(ctx, uid, strconv.Itoa(i), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if i == 3 && (len(evicted) != 1 || evicted[0] != "1") {
+			t.Fatalf("the earliest device should be evicted, got %v", evicted)
+		}
+	}
+
+	remains, err := locator.RemDevice(ctx, uid, "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(remains) != 1 || remains[0] != "2" {
+		t.Fatalf("unexpected remaining devices: %v", remains)
+	}
+
+	if _, err = locator.RemDevice(ctx, uid, "2"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocator_Count(t *testing.T) {
+	ctx := context.Background()
+	uid := time.Now().UnixNano()
+	nid := strconv.FormatInt(uid, 10)
+
+	if err := locator.Set(ctx, uid, cluster.Node, "", nid+"-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := locator.Set(ctx, uid, cluster.Node, "", nid+"-b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := locator.Count(ctx, cluster.Node, nid+"-a"); err != nil || n != 0 {
+		t.Fatalf("the user should move out of the previous node, got %d: %v", n, err)
+	}
+
+	if n, err := locator.Count(ctx, cluster.Node, nid+"-b"); err != nil || n != 1 {
+		t.Fatalf("expected 1 user located at the node, got %d: %v", n, err)
+	}
+
+	if err := locator.Rem(ctx, uid, cluster.Node, "", nid+"-b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := locator.Count(ctx, cluster.Node, nid+"-b"); err != nil || n != 0 {
+		t.Fatalf("expected no user located at the node, got %d: %v", n, err)
+	}
+}
+
+func TestLocator_ChannelGates(t *testing.T) {
+	ctx := context.Background()
+	channel := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	for _, gid := range []string{"gate-1", "gate-2"} {
+		if err := locator.AddChannelGate(ctx, channel, gid); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := locator.RemChannelGate(ctx, channel, "gate-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	gids, err := locator.ChannelGates(ctx, channel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(gids) != 1 || gids[0] != "gate-2" {
+		t.Fatalf("unexpected channel gates: %v", gids)
+	}
+
+	if err = locator.RemChannelGate(ctx, channel, "gate-2"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -9,7 +9,8 @@ package network
 
 type Client interface {
 	// Dial 拨号连接
-	Dial() (Conn, error)
+	// addr 为拨号地址，不传时使用配置的拨号地址
+	Dial(addr ...string) (Conn, error)
 	// OnConnect 监听连接打开
 	OnConnect(handler ConnectHandler)
 	// OnReceive 监听接收消息
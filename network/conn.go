@@ -23,6 +23,17 @@ var (
 	ErrConnectionClosed  = errors.New("connection is closed")
 	ErrIllegalMsgType    = errors.New("illegal message type")
 	ErrTooManyConnection = errors.New("too many connection")
+	ErrWriteQueueFull    = errors.New("write queue is full")
+)
+
+// OverflowPolicy 写入队列溢出策略
+type OverflowPolicy string
+
+const (
+	OverflowDropNewest OverflowPolicy = "dropNewest" // 丢弃最新的消息
+	OverflowDropOldest OverflowPolicy = "dropOldest" // 丢弃队列中最早的消息
+	OverflowDisconnect OverflowPolicy = "disconnect" // 强制断开连接
+	OverflowBlock      OverflowPolicy = "block"      // 阻塞等待，超时后丢弃最新的消息
 )
 
 type (
@@ -39,7 +50,7 @@ type (
 		Unbind()
 		// Send 发送消息（同步）
 		Send(msg []byte, msgType ...int) error
-		// Push 发送消息（异步）
+		// Push 发送消息（异步），写入队列已满时按溢出策略处理
 		Push(msg []byte, msgType ...int) error
 		// State 获取连接状态
 		State() ConnState
@@ -54,4 +65,13 @@ type (
 		// RemoteAddr 获取远端地址
 		RemoteAddr() (net.Addr, error)
 	}
+
+	// OverflowConn 支持写入队列溢出处理的连接，连接可选实现该接口，内置的连接均已实现
+	// 独立于Conn定义，避免第三方的连接实现因接口新增方法而不再满足Conn
+	OverflowConn interface {
+		// TryPush 发送消息（异步非阻塞），写入队列已满时按溢出策略处理，阻塞策略下直接丢弃消息
+		TryPush(msg []byte, msgType ...int) error
+		// Overflows 获取写入队列溢出次数
+		Overflows() int64
+	}
 )
@@ -24,15 +24,20 @@ func NewClient(opts ...ClientOption) network.Client {
 }
 
 // Dial 拨号连接
-func (c *client) Dial() (network.Conn, error) {
+func (c *client) Dial(addr ...string) (network.Conn, error) {
+	dialAddr := c.opts.addr
+	if len(addr) > 0 && addr[0] != "" {
+		dialAddr = addr[0]
+	}
 
-	conn, err := kcp.DialWithOptions(c.opts.addr, c.opts.blockCrypt, c.opts.dataShards, c.opts.parityShards)
-	conn.SetACKNoDelay(c.opts.ackNoDelay)
-	conn.SetNoDelay(c.opts.noDelay, c.opts.kcpInterval, c.opts.kcpResend, c.opts.kcpNc)
+	conn, err := kcp.DialWithOptions(dialAddr, c.opts.blockCrypt, c.opts.dataShards, c.opts.parityShards)
 	if err != nil {
 		return nil, err
 	}
 
+	conn.SetACKNoDelay(c.opts.ackNoDelay)
+	conn.SetNoDelay(c.opts.noDelay, c.opts.kcpInterval, c.opts.kcpResend, c.opts.kcpNc)
+
 	return newClientConn(c, conn), nil
 }
 
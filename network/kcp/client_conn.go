@@ -13,17 +13,21 @@ import (
 )
 
 type clientConn struct {
-	rw      sync.RWMutex
-	id      int64           // 连接ID
-	uid     int64           // 用户ID
-	conn    *kcp.UDPSession // KCP源连接
-	state   int32           // 连接状态
-	client  *client         // 客户端
-	chWrite chan chWrite    // 写入队列
-	done    chan struct{}   // 写入完成信号
+	rw        sync.RWMutex
+	id        int64           // 连接ID
+	uid       int64           // 用户ID
+	conn      *kcp.UDPSession // KCP源连接
+	state     int32           // 连接状态
+	client    *client         // 客户端
+	chWrite   chan chWrite    // 写入队列
+	done      chan struct{}   // 写入完成信号
+	overflows int64           // 写入队列溢出次数
 }
 
-var _ network.Conn = &clientConn{}
+var (
+	_ network.Conn         = &clientConn{}
+	_ network.OverflowConn = &clientConn{}
+)
 
 func newClientConn(client *client, conn *kcp.UDPSession) network.Conn {
 	c := &clientConn{
@@ -94,6 +98,29 @@ func (c *clientConn) Push(msg []byte, msgType ...int) (err error) {
 	return
 }
 
+// TryPush 发送消息（异步非阻塞），写入队列已满时丢弃消息
+func (c *clientConn) TryPush(msg []byte, msgType ...int) (err error) {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	if err = c.checkState(); err != nil {
+		return
+	}
+
+	select {
+	case c.chWrite <- chWrite{typ: dataPacket, msg: msg}:
+		return
+	default:
+		atomic.AddInt64(&c.overflows, 1)
+		return network.ErrWriteQueueFull
+	}
+}
+
+// Overflows 获取写入队列溢出次数
+func (c *clientConn) Overflows() int64 {
+	return atomic.LoadInt64(&c.overflows)
+}
+
 // State 获取连接状态
 func (c *clientConn) State() network.ConnState {
 	return network.ConnState(atomic.LoadInt32(&c.state))
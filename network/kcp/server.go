@@ -7,9 +7,11 @@
 package kcp
 
 import (
+	"github.com/dobyte/due/internal/graceful"
 	"github.com/dobyte/due/log"
 	"github.com/xtaci/kcp-go"
 	"net"
+	"sync/atomic"
 	"time"
 
 	"github.com/dobyte/due/network"
@@ -18,6 +20,7 @@ import (
 type server struct {
 	opts              *serverOptions            // 配置
 	listener          *kcp.Listener             // 监听器
+	listening         int32                     // 是否正在监听
 	connMgr           *serverConnMgr            // 连接管理器
 	startHandler      network.StartHandler      // 服务器启动hook函数
 	stopHandler       network.CloseHandler      // 服务器关闭hook函数
@@ -61,7 +64,7 @@ func (s *server) Start() error {
 
 // Stop 关闭服务器
 func (s *server) Stop() error {
-	if err := s.listener.Close(); err != nil {
+	if err := s.closeListener(); err != nil {
 		return err
 	}
 
@@ -70,6 +73,23 @@ func (s *server) Stop() error {
 	return nil
 }
 
+// StopAccept 停止接收新连接
+// KCP会话与监听器共享同一个UDP连接，停止接收后已建立的会话将无法继续收取数据。
+// 热重启时父子进程共享该UDP连接，任一进程都可能读取到对方会话的数据报，因此KCP服务器不支持零停机热重启，
+// 存量会话在热重启时断开，客户端需重新连接（网关开启会话恢复时可凭恢复令牌恢复会话）
+func (s *server) StopAccept() error {
+	return s.closeListener()
+}
+
+// 关闭监听器
+func (s *server) closeListener() error {
+	if !atomic.CompareAndSwapInt32(&s.listening, 1, 0) {
+		return nil
+	}
+
+	return s.listener.Close()
+}
+
 // Protocol 协议
 func (s *server) Protocol() string {
 	return "tcp"
@@ -102,12 +122,19 @@ func (s *server) OnReceive(handler network.ReceiveHandler) {
 
 // 初始化TCP服务器
 func (s *server) init() error {
-	ln, err := kcp.ListenWithOptions(s.opts.addr, s.opts.blockCrypt, s.opts.parityShards, s.opts.dataShards)
+	conn, err := graceful.ListenPacket("udp", s.opts.addr)
+	if err != nil {
+		return err
+	}
+
+	ln, err := kcp.ServeConn(s.opts.blockCrypt, s.opts.parityShards, s.opts.dataShards, conn)
 	if err != nil {
+		_ = conn.Close()
 		return err
 	}
 
 	s.listener = ln
+	atomic.StoreInt32(&s.listening, 1)
 
 	return nil
 }
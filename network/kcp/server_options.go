@@ -3,6 +3,7 @@ package kcp
 import (
 	"crypto/sha1"
 	"github.com/dobyte/due/config"
+	"github.com/dobyte/due/network"
 	"github.com/xtaci/kcp-go"
 	"golang.org/x/crypto/pbkdf2"
 	"time"
@@ -14,6 +15,9 @@ const (
 	defaultServerMaxConnNum             = 5000
 	defaultServerHeartbeatCheck         = false
 	defaultServerHeartbeatCheckInterval = 10
+	defaultServerWriteQueueSize         = 1024
+	defaultServerOverflowPolicy         = network.OverflowBlock
+	defaultServerOverflowTimeout        = 1000
 )
 
 const (
@@ -22,16 +26,22 @@ const (
 	defaultServerMaxConnNumKey             = "config.network.kcp.server.maxConnNum"
 	defaultServerHeartbeatCheckKey         = "config.network.kcp.server.heartbeatCheck"
 	defaultServerHeartbeatCheckIntervalKey = "config.network.kcp.server.heartbeatCheckInterval"
+	defaultServerWriteQueueSizeKey         = "config.network.kcp.server.writeQueueSize"
+	defaultServerOverflowPolicyKey         = "config.network.kcp.server.overflowPolicy"
+	defaultServerOverflowTimeoutKey        = "config.network.kcp.server.overflowTimeout"
 )
 
 type ServerOption func(o *serverOptions)
 
 type serverOptions struct {
-	addr                   string        // 监听地址，默认0.0.0.0:3553
-	maxMsgLen              int           // 最大消息长度，默认1K
-	maxConnNum             int           // 最大连接数，默认5000
-	enableHeartbeatCheck   bool          // 是否启用心跳检测，默认不启用
-	heartbeatCheckInterval time.Duration // 心跳检测间隔时间，默认10s
+	addr                   string                 // 监听地址，默认0.0.0.0:3553
+	maxMsgLen              int                    // 最大消息长度，默认1K
+	maxConnNum             int                    // 最大连接数，默认5000
+	enableHeartbeatCheck   bool                   // 是否启用心跳检测，默认不启用
+	heartbeatCheckInterval time.Duration          // 心跳检测间隔时间，默认10s
+	writeQueueSize         int                    // 写入队列大小，默认1024
+	overflowPolicy         network.OverflowPolicy // 写入队列溢出策略，默认阻塞等待
+	overflowTimeout        time.Duration          // 阻塞策略的等待超时时间，默认1s，不大于0时一直等待
 	blockCrypt             kcp.BlockCrypt
 	dataShards             int
 	parityShards           int
@@ -49,6 +59,9 @@ func defaultServerOptions() *serverOptions {
 		maxConnNum:             config.Get(defaultServerMaxConnNumKey, defaultServerMaxConnNum).Int(),
 		enableHeartbeatCheck:   config.Get(defaultServerHeartbeatCheckKey, defaultServerHeartbeatCheck).Bool(),
 		heartbeatCheckInterval: config.Get(defaultServerHeartbeatCheckIntervalKey, defaultServerHeartbeatCheckInterval).Duration() * time.Second,
+		writeQueueSize:         config.Get(defaultServerWriteQueueSizeKey, defaultServerWriteQueueSize).Int(),
+		overflowPolicy:         network.OverflowPolicy(config.Get(defaultServerOverflowPolicyKey, string(defaultServerOverflowPolicy)).String()),
+		overflowTimeout:        config.Get(defaultServerOverflowTimeoutKey, defaultServerOverflowTimeout).Duration() * time.Millisecond,
 		blockCrypt:             nil,
 		dataShards:             0,
 		parityShards:           0,
@@ -181,3 +194,18 @@ func WithServerAckAndSend(ackNoDelay bool, sendNoDelay, interval, resend, nc int
 		o.kcpNc = nc
 	}
 }
+
+// WithServerWriteQueueSize 设置连接的写入队列大小，不大于0时使用默认值
+func WithServerWriteQueueSize(size int) ServerOption {
+	return func(o *serverOptions) { o.writeQueueSize = size }
+}
+
+// WithServerOverflowPolicy 设置写入队列溢出策略，组播和广播消息不会因单个连接的写入队列已满而阻塞
+func WithServerOverflowPolicy(policy network.OverflowPolicy) ServerOption {
+	return func(o *serverOptions) { o.overflowPolicy = policy }
+}
+
+// WithServerOverflowTimeout 设置阻塞策略的等待超时时间，不大于0时一直等待
+func WithServerOverflowTimeout(timeout time.Duration) ServerOption {
+	return func(o *serverOptions) { o.overflowTimeout = timeout }
+}
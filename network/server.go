@@ -22,6 +22,8 @@ type Server interface {
 	Start() error
 	// Stop 关闭服务器
 	Stop() error
+	// StopAccept 停止接收新连接，已建立的连接不受影响（KCP服务器除外，其会话随监听器一同关闭）
+	StopAccept() error
 	// Protocol 协议
 	Protocol() string
 	// OnStart 监听服务器启动
@@ -24,13 +24,18 @@ func NewClient(opts ...ClientOption) network.Client {
 }
 
 // Dial 拨号连接
-func (c *client) Dial() (network.Conn, error) {
-	addr, err := net.ResolveTCPAddr("tcp", c.opts.addr)
+func (c *client) Dial(addr ...string) (network.Conn, error) {
+	dialAddr := c.opts.addr
+	if len(addr) > 0 && addr[0] != "" {
+		dialAddr = addr[0]
+	}
+
+	tcpAddr, err := net.ResolveTCPAddr("tcp", dialAddr)
 	if err != nil {
 		return nil, err
 	}
 
-	conn, err := net.Dial(addr.Network(), addr.String())
+	conn, err := net.Dial(tcpAddr.Network(), tcpAddr.String())
 	if err != nil {
 		return nil, err
 	}
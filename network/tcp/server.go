@@ -8,8 +8,10 @@
 package tcp
 
 import (
+	"github.com/dobyte/due/internal/graceful"
 	"github.com/dobyte/due/log"
 	"net"
+	"sync/atomic"
 	"time"
 
 	"github.com/dobyte/due/network"
@@ -18,6 +20,7 @@ import (
 type server struct {
 	opts              *serverOptions            // 配置
 	listener          net.Listener              // 监听器
+	listening         int32                     // 是否正在监听
 	connMgr           *serverConnMgr            // 连接管理器
 	startHandler      network.StartHandler      // 服务器启动hook函数
 	stopHandler       network.CloseHandler      // 服务器关闭hook函数
@@ -61,7 +64,7 @@ func (s *server) Start() error {
 
 // Stop 关闭服务器
 func (s *server) Stop() error {
-	if err := s.listener.Close(); err != nil {
+	if err := s.closeListener(); err != nil {
 		return err
 	}
 
@@ -70,6 +73,20 @@ func (s *server) Stop() error {
 	return nil
 }
 
+// StopAccept 停止接收新连接，已建立的连接不受影响
+func (s *server) StopAccept() error {
+	return s.closeListener()
+}
+
+// 关闭监听器
+func (s *server) closeListener() error {
+	if !atomic.CompareAndSwapInt32(&s.listening, 1, 0) {
+		return nil
+	}
+
+	return s.listener.Close()
+}
+
 // Protocol 协议
 func (s *server) Protocol() string {
 	return "tcp"
@@ -102,17 +119,13 @@ func (s *server) OnReceive(handler network.ReceiveHandler) {
 
 // 初始化TCP服务器
 func (s *server) init() error {
-	addr, err := net.ResolveTCPAddr("tcp", s.opts.addr)
-	if err != nil {
-		return err
-	}
-
-	ln, err := net.ListenTCP(addr.Network(), addr)
+	ln, err := graceful.Listen("tcp", s.opts.addr)
 	if err != nil {
 		return err
 	}
 
 	s.listener = ln
+	atomic.StoreInt32(&s.listening, 1)
 
 	return nil
 }
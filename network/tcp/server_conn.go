@@ -29,9 +29,13 @@ type serverConn struct {
 	chWrite           chan chWrite   // 写入队列
 	lastHeartbeatTime int64          // 上次心跳时间
 	done              chan struct{}  // 写入完成信号
+	overflows         int64          // 写入队列溢出次数
 }
 
-var _ network.Conn = &serverConn{}
+var (
+	_ network.Conn         = &serverConn{}
+	_ network.OverflowConn = &serverConn{}
+)
 
 // ID 获取连接ID
 func (c *serverConn) ID() int64 {
@@ -68,17 +72,18 @@ func (c *serverConn) Send(msg []byte, msgType ...int) (err error) {
 }
 
 // Push 发送消息（异步）
-func (c *serverConn) Push(msg []byte, msgType ...int) (err error) {
-	c.rw.RLock()
-	defer c.rw.RUnlock()
-
-	if err = c.checkState(); err != nil {
-		return
-	}
+func (c *serverConn) Push(msg []byte, msgType ...int) error {
+	return c.push(msg, true)
+}
 
-	c.chWrite <- chWrite{typ: dataPacket, msg: msg}
+// TryPush 发送消息（异步非阻塞）
+func (c *serverConn) TryPush(msg []byte, msgType ...int) error {
+	return c.push(msg, false)
+}
 
-	return
+// Overflows 获取写入队列溢出次数
+func (c *serverConn) Overflows() int64 {
+	return atomic.LoadInt64(&c.overflows)
 }
 
 // State 获取连接状态
@@ -151,12 +156,76 @@ func (c *serverConn) checkState() error {
 	return nil
 }
 
+// 写入消息到写入队列，写入队列已满时按溢出策略处理，block为false时阻塞策略下不等待
+func (c *serverConn) push(msg []byte, block bool) (err error) {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	if err = c.checkState(); err != nil {
+		return
+	}
+
+	write := chWrite{typ: dataPacket, msg: msg}
+
+	select {
+	case c.chWrite <- write:
+		return
+	default:
+	}
+
+	opts := c.connMgr.server.opts
+
+	switch opts.overflowPolicy {
+	case network.OverflowDropNewest:
+	case network.OverflowDropOldest:
+		for {
+			select {
+			case <-c.chWrite:
+				atomic.AddInt64(&c.overflows, 1)
+			default:
+			}
+
+			select {
+			case c.chWrite <- write:
+				return
+			default:
+			}
+		}
+	case network.OverflowDisconnect:
+		log.Warnf("the connection write queue overflow, cid: %d", c.id)
+		if atomic.CompareAndSwapInt32(&c.state, int32(network.ConnOpened), int32(network.ConnClosed)) {
+			_ = c.conn.Close()
+		}
+	default:
+		if block {
+			if opts.overflowTimeout <= 0 {
+				c.chWrite <- write
+				return
+			}
+
+			timer := time.NewTimer(opts.overflowTimeout)
+			defer timer.Stop()
+
+			select {
+			case c.chWrite <- write:
+				return
+			case <-timer.C:
+			}
+		}
+	}
+
+	atomic.AddInt64(&c.overflows, 1)
+
+	return network.ErrWriteQueueFull
+}
+
 // 初始化连接
 func (c *serverConn) init(conn net.Conn, cm *serverConnMgr) {
 	c.id = cm.id
 	c.conn = conn
 	c.connMgr = cm
-	c.chWrite = make(chan chWrite, 1024)
+	c.chWrite = make(chan chWrite, writeQueueSize(cm.server.opts.writeQueueSize))
+	c.overflows = 0
 	c.done = make(chan struct{})
 	c.lastHeartbeatTime = xtime.Now().Unix()
 	atomic.StoreInt32(&c.state, int32(network.ConnOpened))
@@ -309,3 +378,12 @@ func (c *serverConn) doWrite(buf []byte) (err error) {
 
 	return
 }
+
+// 写入队列大小，不大于0时使用默认值
+func writeQueueSize(size int) int {
+	if size <= 0 {
+		return defaultServerWriteQueueSize
+	}
+
+	return size
+}
@@ -0,0 +1,110 @@
+package tcp
+
+import (
+	"github.com/dobyte/due/network"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 创建未启动读写协程的连接，写入队列大小为1
+func newOverflowConn(policy network.OverflowPolicy, timeout time.Duration) *serverConn {
+	local, _ := net.Pipe()
+	s := &server{opts: &serverOptions{writeQueueSize: 1, overflowPolicy: policy, overflowTimeout: timeout}}
+	c := &serverConn{conn: local, connMgr: &serverConnMgr{server: s}, chWrite: make(chan chWrite, 1)}
+	atomic.StoreInt32(&c.state, int32(network.ConnOpened))
+
+	return c
+}
+
+func TestServerConn_WriteQueueSize(t *testing.T) {
+	for _, size := range []int{-1, 0} {
+		if writeQueueSize(size) != defaultServerWriteQueueSize {
+			t.Fatalf("the write queue size %d should fall back to the default", size)
+		}
+	}
+
+	if writeQueueSize(10) != 10 {
+		t.Fatal("the write queue size should be kept")
+	}
+}
+
+func TestServerConn_OverflowDropNewest(t *testing.T) {
+	c := newOverflowConn(network.OverflowDropNewest, 0)
+
+	if err := c.Push([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Push([]byte("b")); err != network.ErrWriteQueueFull {
+		t.Fatalf("expected write queue full, got %v", err)
+	}
+
+	if w := <-c.chWrite; string(w.msg) != "a" || c.Overflows() != 1 {
+		t.Fatalf("the newest message should be dropped, queued: %s, overflows: %d", w.msg, c.Overflows())
+	}
+}
+
+func TestServerConn_OverflowDropOldest(t *testing.T) {
+	c := newOverflowConn(network.OverflowDropOldest, 0)
+
+	if err := c.Push([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Push([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+
+	if w := <-c.chWrite; string(w.msg) != "b" || c.Overflows() != 1 {
+		t.Fatalf("the oldest message should be dropped, queued: %s, overflows: %d", w.msg, c.Overflows())
+	}
+}
+
+func TestServerConn_OverflowDisconnect(t *testing.T) {
+	c := newOverflowConn(network.OverflowDisconnect, 0)
+
+	if err := c.Push([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Push([]byte("b")); err != network.ErrWriteQueueFull {
+		t.Fatalf("expected write queue full, got %v", err)
+	}
+
+	if c.State() != network.ConnClosed {
+		t.Fatal("the connection should be closed after the write queue overflow")
+	}
+}
+
+func TestServerConn_OverflowBlock(t *testing.T) {
+	c := newOverflowConn(network.OverflowBlock, 50*time.Millisecond)
+
+	if err := c.Push([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := c.TryPush([]byte("b")); err != network.ErrWriteQueueFull || time.Since(start) >= 50*time.Millisecond {
+		t.Fatalf("the try push should not wait, err: %v", err)
+	}
+
+	start = time.Now()
+	if err := c.Push([]byte("b")); err != network.ErrWriteQueueFull || time.Since(start) < 50*time.Millisecond {
+		t.Fatalf("the push should wait until timeout, err: %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-c.chWrite
+	}()
+
+	if err := c.Push([]byte("c")); err != nil {
+		t.Fatalf("the push should succeed after the queue drained, err: %v", err)
+	}
+
+	if c.Overflows() != 2 {
+		t.Fatalf("unexpected overflows: %d", c.Overflows())
+	}
+}
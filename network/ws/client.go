@@ -3,6 +3,8 @@ package ws
 import (
 	"github.com/dobyte/due/network"
 	"github.com/gorilla/websocket"
+	"net/url"
+	"strings"
 )
 
 type client struct {
@@ -27,8 +29,21 @@ func NewClient(opts ...ClientOption) network.Client {
 }
 
 // Dial 拨号连接
-func (c *client) Dial() (network.Conn, error) {
-	conn, _, err := c.dialer.Dial(c.opts.url, nil)
+// addr 可以为完整的拨号地址，也可以仅为主机地址，仅为主机地址时沿用配置拨号地址的协议和路径
+func (c *client) Dial(addr ...string) (network.Conn, error) {
+	dialUrl := c.opts.url
+	if len(addr) > 0 && addr[0] != "" {
+		if strings.Contains(addr[0], "://") {
+			dialUrl = addr[0]
+		} else if u, err := url.Parse(c.opts.url); err != nil {
+			return nil, err
+		} else {
+			u.Host = addr[0]
+			dialUrl = u.String()
+		}
+	}
+
+	conn, _, err := c.dialer.Dial(dialUrl, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -8,10 +8,12 @@
 package ws
 
 import (
+	"github.com/dobyte/due/internal/graceful"
 	"github.com/dobyte/due/log"
 	"github.com/gorilla/websocket"
 	"net"
 	"net/http"
+	"sync/atomic"
 
 	"github.com/dobyte/due/network"
 )
@@ -19,6 +21,7 @@ import (
 type server struct {
 	opts              *serverOptions            // 配置
 	listener          net.Listener              // 监听器
+	listening         int32                     // 是否正在监听
 	connMgr           *connMgr                  // 连接管理器
 	startHandler      network.StartHandler      // 服务器启动hook函数
 	stopHandler       network.CloseHandler      // 服务器关闭hook函数
@@ -67,7 +70,7 @@ func (s *server) Start() error {
 
 // Stop 关闭服务器
 func (s *server) Stop() error {
-	if err := s.listener.Close(); err != nil {
+	if err := s.closeListener(); err != nil {
 		return err
 	}
 
@@ -76,19 +79,29 @@ func (s *server) Stop() error {
 	return nil
 }
 
-// 初始化服务器
-func (s *server) init() error {
-	addr, err := net.ResolveTCPAddr("tcp", s.opts.addr)
-	if err != nil {
-		return err
+// StopAccept 停止接收新连接，已建立的连接不受影响
+func (s *server) StopAccept() error {
+	return s.closeListener()
+}
+
+// 关闭监听器
+func (s *server) closeListener() error {
+	if !atomic.CompareAndSwapInt32(&s.listening, 1, 0) {
+		return nil
 	}
 
-	ln, err := net.ListenTCP(addr.Network(), addr)
+	return s.listener.Close()
+}
+
+// 初始化服务器
+func (s *server) init() error {
+	ln, err := graceful.Listen("tcp", s.opts.addr)
 	if err != nil {
 		return err
 	}
 
 	s.listener = ln
+	atomic.StoreInt32(&s.listening, 1)
 
 	return nil
 }
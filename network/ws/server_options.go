@@ -2,6 +2,7 @@ package ws
 
 import (
 	"github.com/dobyte/due/config"
+	"github.com/dobyte/due/network"
 	"net/http"
 	"time"
 )
@@ -14,6 +15,9 @@ const (
 	defaultServerCheckOrigin            = "*"
 	defaultServerHeartbeatCheck         = false
 	defaultServerHeartbeatCheckInterval = 10
+	defaultServerWriteQueueSize         = 1024
+	defaultServerOverflowPolicy         = network.OverflowBlock
+	defaultServerOverflowTimeout        = 1000
 	defaultServerHandshakeTimeout       = 10
 )
 
@@ -27,6 +31,9 @@ const (
 	defaultServerCertFileKey               = "config.network.ws.server.certFile"
 	defaultServerHeartbeatCheckKey         = "config.network.ws.server.heartbeatCheck"
 	defaultServerHeartbeatCheckIntervalKey = "config.network.ws.server.heartbeatCheckInterval"
+	defaultServerWriteQueueSizeKey         = "config.network.ws.server.writeQueueSize"
+	defaultServerOverflowPolicyKey         = "config.network.ws.server.overflowPolicy"
+	defaultServerOverflowTimeoutKey        = "config.network.ws.server.overflowTimeout"
 	defaultServerHandshakeTimeoutKey       = "config.network.ws.server.handshakeTimeout"
 )
 
@@ -35,16 +42,19 @@ type ServerOption func(o *serverOptions)
 type CheckOriginFunc func(r *http.Request) bool
 
 type serverOptions struct {
-	addr                   string          // 监听地址
-	maxMsgLen              int             // 最大消息长度（字节），默认1kb
-	maxConnNum             int             // 最大连接数
-	certFile               string          // 证书文件
-	keyFile                string          // 秘钥文件
-	path                   string          // 路径，默认为"/"
-	checkOrigin            CheckOriginFunc // 跨域检测
-	enableHeartbeatCheck   bool            // 是否启用心跳检测
-	heartbeatCheckInterval time.Duration   // 心跳检测间隔时间，默认10s
-	handshakeTimeout       time.Duration   // 握手超时时间，默认10s
+	addr                   string                 // 监听地址
+	maxMsgLen              int                    // 最大消息长度（字节），默认1kb
+	maxConnNum             int                    // 最大连接数
+	certFile               string                 // 证书文件
+	keyFile                string                 // 秘钥文件
+	path                   string                 // 路径，默认为"/"
+	checkOrigin            CheckOriginFunc        // 跨域检测
+	enableHeartbeatCheck   bool                   // 是否启用心跳检测
+	heartbeatCheckInterval time.Duration          // 心跳检测间隔时间，默认10s
+	writeQueueSize         int                    // 写入队列大小，默认1024
+	overflowPolicy         network.OverflowPolicy // 写入队列溢出策略，默认阻塞等待
+	overflowTimeout        time.Duration          // 阻塞策略的等待超时时间，默认1s，不大于0时一直等待
+	handshakeTimeout       time.Duration          // 握手超时时间，默认10s
 }
 
 func defaultServerOptions() *serverOptions {
@@ -74,6 +84,9 @@ func defaultServerOptions() *serverOptions {
 		certFile:               config.Get(defaultServerCertFileKey).String(),
 		enableHeartbeatCheck:   config.Get(defaultServerHeartbeatCheckKey, defaultServerHeartbeatCheck).Bool(),
 		heartbeatCheckInterval: config.Get(defaultServerHeartbeatCheckIntervalKey, defaultServerHeartbeatCheckInterval).Duration() * time.Second,
+		writeQueueSize:         config.Get(defaultServerWriteQueueSizeKey, defaultServerWriteQueueSize).Int(),
+		overflowPolicy:         network.OverflowPolicy(config.Get(defaultServerOverflowPolicyKey, string(defaultServerOverflowPolicy)).String()),
+		overflowTimeout:        config.Get(defaultServerOverflowTimeoutKey, defaultServerOverflowTimeout).Duration() * time.Millisecond,
 		handshakeTimeout:       config.Get(defaultServerHandshakeTimeoutKey, defaultServerHandshakeTimeout).Duration() * time.Second,
 	}
 }
@@ -117,3 +130,18 @@ func WithServerHeartbeatCheckInterval(heartbeatCheckInterval time.Duration) Serv
 func WithServerHandshakeTimeout(handshakeTimeout time.Duration) ServerOption {
 	return func(o *serverOptions) { o.handshakeTimeout = handshakeTimeout }
 }
+
+// WithServerWriteQueueSize 设置连接的写入队列大小，不大于0时使用默认值
+func WithServerWriteQueueSize(size int) ServerOption {
+	return func(o *serverOptions) { o.writeQueueSize = size }
+}
+
+// WithServerOverflowPolicy 设置写入队列溢出策略，组播和广播消息不会因单个连接的写入队列已满而阻塞
+func WithServerOverflowPolicy(policy network.OverflowPolicy) ServerOption {
+	return func(o *serverOptions) { o.overflowPolicy = policy }
+}
+
+// WithServerOverflowTimeout 设置阻塞策略的等待超时时间，不大于0时一直等待
+func WithServerOverflowTimeout(timeout time.Duration) ServerOption {
+	return func(o *serverOptions) { o.overflowTimeout = timeout }
+}
@@ -0,0 +1,50 @@
+package due
+
+import (
+	"github.com/dobyte/due/config"
+	"time"
+)
+
+const (
+	defaultShutdownTimeout = 10 * time.Second // 默认组件销毁超时时间
+	defaultRestartTimeout  = 30 * time.Second // 默认热重启等待新进程就绪超时时间
+)
+
+const (
+	defaultShutdownTimeoutKey = "config.container.shutdownTimeout"
+	defaultRestartTimeoutKey  = "config.container.restartTimeout"
+)
+
+type Option func(o *options)
+
+type options struct {
+	shutdownTimeout time.Duration // 组件销毁超时时间
+	restartTimeout  time.Duration // 热重启等待新进程就绪超时时间
+}
+
+func defaultOptions() *options {
+	opts := &options{
+		shutdownTimeout: defaultShutdownTimeout,
+		restartTimeout:  defaultRestartTimeout,
+	}
+
+	if timeout := config.Get(defaultShutdownTimeoutKey).Int64(); timeout > 0 {
+		opts.shutdownTimeout = time.Duration(timeout) * time.Second
+	}
+
+	if timeout := config.Get(defaultRestartTimeoutKey).Int64(); timeout > 0 {
+		opts.restartTimeout = time.Duration(timeout) * time.Second
+	}
+
+	return opts
+}
+
+// WithShutdownTimeout 设置组件默认销毁超时时间
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.shutdownTimeout = timeout }
+}
+
+// WithRestartTimeout 设置热重启等待新进程就绪超时时间
+func WithRestartTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.restartTimeout = timeout }
+}
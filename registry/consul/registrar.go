@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/dobyte/due/log"
 	"github.com/dobyte/due/registry"
+	"github.com/dobyte/due/utils/xuuid"
 	"github.com/hashicorp/consul/api"
 	"net"
 	"net/url"
@@ -13,11 +14,18 @@ import (
 )
 
 const (
-	checkIDFormat     = "service:%s"
-	checkUpdateOutput = "passed"
-	metaFieldKind     = "kind"
-	metaFieldAlias    = "alias"
-	metaFieldState    = "state"
+	checkIDFormat      = "service:%s"
+	checkUpdateOutput  = "passed"
+	metaFieldKind      = "kind"
+	metaFieldAlias     = "alias"
+	metaFieldGroup     = "group"
+	metaFieldZone      = "zone"
+	metaFieldState     = "state"
+	metaFieldOwner     = "owner"
+	metaFieldAddress   = "address"
+	metaFieldVersion   = "version"
+	metaPrefixMetadata = "md-"      // 服务实例元数据的键前缀，consul元数据的键仅支持字母、数字、下划线和中划线
+	routeFlagSharding  = "sharding" // 路由元数据中的分片标记，以逗号附加在有状态标记之后
 )
 
 type registrar struct {
@@ -25,6 +33,7 @@ type registrar struct {
 	cancel      context.CancelFunc
 	registry    *Registry
 	chHeartbeat chan string
+	owner       string // 注册者标识
 }
 
 func newRegistrar(registry *Registry) *registrar {
@@ -32,6 +41,7 @@ func newRegistrar(registry *Registry) *registrar {
 	r.ctx, r.cancel = context.WithCancel(registry.ctx)
 	r.registry = registry
 	r.chHeartbeat = make(chan string)
+	r.owner, _ = xuuid.UUID()
 
 	if r.registry.opts.enableHeartbeatCheck {
 		go r.keepHeartbeat()
@@ -42,25 +52,54 @@ func newRegistrar(registry *Registry) *registrar {
 
 // 注册服务
 func (r *registrar) register(ctx context.Context, ins *registry.ServiceInstance) error {
-	raw, err := url.Parse(ins.Endpoint)
+	registration, err := r.build(ins)
 	if err != nil {
 		return err
 	}
 
-	host, p, err := net.SplitHostPort(raw.Host)
+	if err = r.registry.opts.client.Agent().ServiceRegister(registration); err != nil {
+		return err
+	}
+
+	if r.registry.opts.enableHeartbeatCheck {
+		r.chHeartbeat <- ins.ID
+	}
+
+	return nil
+}
+
+// 更新服务
+// 以相同的服务ID重新注册，consul会保留已有健康检查的状态，无需重启心跳
+func (r *registrar) update(ctx context.Context, ins *registry.ServiceInstance) error {
+	registration, err := r.build(ins)
 	if err != nil {
 		return err
 	}
 
+	return r.registry.opts.client.Agent().ServiceRegister(registration)
+}
+
+// 构建服务注册信息
+func (r *registrar) build(ins *registry.ServiceInstance) (*api.AgentServiceRegistration, error) {
+	raw, err := url.Parse(ins.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	host, p, err := net.SplitHostPort(raw.Host)
+	if err != nil {
+		return nil, err
+	}
+
 	port, err := strconv.Atoi(p)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	registration := &api.AgentServiceRegistration{
 		ID:      ins.ID,
 		Name:    ins.Name,
-		Meta:    make(map[string]string, len(ins.Routes)+3),
+		Meta:    make(map[string]string, len(ins.Routes)+len(ins.Metadata)+8),
 		Address: host,
 		Port:    port,
 		TaggedAddresses: map[string]api.ServiceAddress{raw.Scheme: {
@@ -72,8 +111,31 @@ func (r *registrar) register(ctx context.Context, ins *registry.ServiceInstance)
 	registration.Meta[metaFieldKind] = string(ins.Kind)
 	registration.Meta[metaFieldAlias] = ins.Alias
 	registration.Meta[metaFieldState] = string(ins.State)
+	if ins.Group != "" {
+		registration.Meta[metaFieldGroup] = ins.Group
+	}
+	if ins.Zone != "" {
+		registration.Meta[metaFieldZone] = ins.Zone
+	}
+	registration.Meta[metaFieldOwner] = r.owner
+	if ins.Address != "" {
+		registration.Meta[metaFieldAddress] = ins.Address
+	}
+	if ins.Version != "" {
+		registration.Meta[metaFieldVersion] = ins.Version
+	}
+	for k, v := range ins.Metadata {
+		registration.Meta[metaPrefixMetadata+k] = v
+	}
+	if ins.Weight > 0 {
+		registration.Weights = &api.AgentWeights{Passing: ins.Weight, Warning: 1}
+	}
 	for _, route := range ins.Routes {
-		registration.Meta[strconv.Itoa(int(route.ID))] = strconv.FormatBool(route.Stateful)
+		value := strconv.FormatBool(route.Stateful)
+		if route.Sharding {
+			value += "," + routeFlagSharding
+		}
+		registration.Meta[strconv.Itoa(int(route.ID))] = value
 	}
 
 	if r.registry.opts.enableHealthCheck {
@@ -93,15 +155,7 @@ func (r *registrar) register(ctx context.Context, ins *registry.ServiceInstance)
 		})
 	}
 
-	if err = r.registry.opts.client.Agent().ServiceRegister(registration); err != nil {
-		return err
-	}
-
-	if r.registry.opts.enableHeartbeatCheck {
-		r.chHeartbeat <- ins.ID
-	}
-
-	return nil
+	return registration, nil
 }
 
 // 解注册服务
@@ -111,6 +165,11 @@ func (r *registrar) deregister(ctx context.Context, ins *registry.ServiceInstanc
 
 	r.registry.registrars.Delete(ins.ID)
 
+	// 服务实例已被其他注册者覆盖（如热重启时新进程以相同ID注册），不再解注册
+	if svc, _, err := r.registry.opts.client.Agent().Service(ins.ID, nil); err == nil && svc.Meta[metaFieldOwner] != r.owner {
+		return nil
+	}
+
 	return r.registry.opts.client.Agent().ServiceDeregister(ins.ID)
 }
 
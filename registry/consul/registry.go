@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -79,6 +80,21 @@ func (r *Registry) Deregister(ctx context.Context, ins *registry.ServiceInstance
 	return r.opts.client.Agent().ServiceDeregister(ins.ID)
 }
 
+// Update 更新已注册的服务实例
+// 仅支持更新由当前注册器注册的服务实例
+func (r *Registry) Update(ctx context.Context, ins *registry.ServiceInstance) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	v, ok := r.registrars.Load(ins.ID)
+	if !ok {
+		return registry.ErrNotRegistered
+	}
+
+	return v.(*registrar).update(ctx, ins)
+}
+
 // Services 获取服务实例列表
 func (r *Registry) Services(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
 	if r.err != nil {
@@ -133,6 +149,7 @@ func (r *Registry) services(ctx context.Context, serviceName string, waitIndex u
 			ID:     entry.Service.ID,
 			Name:   entry.Service.Service,
 			Routes: make([]registry.Route, 0, len(entry.Service.Meta)),
+			Weight: entry.Service.Weights.Passing,
 		}
 
 		for scheme, addr := range entry.Service.TaggedAddresses {
@@ -154,15 +171,34 @@ func (r *Registry) services(ctx context.Context, serviceName string, waitIndex u
 				ins.Kind = cluster.Kind(v)
 			case metaFieldAlias:
 				ins.Alias = v
+			case metaFieldGroup:
+				ins.Group = v
+			case metaFieldZone:
+				ins.Zone = v
 			case metaFieldState:
 				ins.State = cluster.State(v)
+			case metaFieldOwner:
+			case metaFieldAddress:
+				ins.Address = v
+			case metaFieldVersion:
+				ins.Version = v
 			default:
+				if strings.HasPrefix(k, metaPrefixMetadata) {
+					if ins.Metadata == nil {
+						ins.Metadata = make(map[string]string)
+					}
+					ins.Metadata[strings.TrimPrefix(k, metaPrefixMetadata)] = v
+					continue
+				}
+
 				route, err := strconv.Atoi(k)
 				if err != nil {
 					continue
 				}
 
-				stateful, err := strconv.ParseBool(v)
+				flags := strings.Split(v, ",")
+
+				stateful, err := strconv.ParseBool(flags[0])
 				if err != nil {
 					continue
 				}
@@ -170,6 +206,7 @@ func (r *Registry) services(ctx context.Context, serviceName string, waitIndex u
 				ins.Routes = append(ins.Routes, registry.Route{
 					ID:       int32(route),
 					Stateful: stateful,
+					Sharding: len(flags) > 1 && flags[1] == routeFlagSharding,
 				})
 			}
 		}
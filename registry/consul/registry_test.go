@@ -19,7 +19,7 @@ const (
 
 var reg = consul.NewRegistry()
 
-func server(t *testing.T) {
+func server(t *testing.T, port int) {
 	ls, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
 		t.Fatal(err)
@@ -41,7 +41,7 @@ func server(t *testing.T) {
 }
 
 func TestRegistry_Register(t *testing.T) {
-	server(t)
+	server(t, port)
 
 	host, err := xnet.ExternalIP()
 	if err != nil {
@@ -137,3 +137,85 @@ func TestRegistry_Watch(t *testing.T) {
 
 	time.Sleep(60 * time.Second)
 }
+
+func TestRegistry_Update(t *testing.T) {
+	server(t, port+1)
+
+	host, err := xnet.ExternalIP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	ins := &registry.ServiceInstance{
+		ID:       "test-update",
+		Name:     serviceName,
+		Kind:     cluster.Node,
+		State:    cluster.Work,
+		Endpoint: fmt.Sprintf("grpc://%s:%d", host, port+1),
+		Version:  "v1",
+		Metadata: map[string]string{"region": "a"},
+	}
+
+	if err = reg.Update(ctx, ins); err != registry.ErrNotRegistered {
+		t.Fatalf("expected not registered, got %v", err)
+	}
+
+	if err = reg.Register(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+	defer reg.Deregister(ctx, ins)
+
+	watcher, err := reg.Watch(ctx, serviceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Stop()
+
+	snapshots := make(chan []*registry.ServiceInstance, 16)
+	go func() {
+		for {
+			services, err := watcher.Next()
+			if err != nil {
+				return
+			}
+			snapshots <- services
+		}
+	}()
+
+	time.Sleep(2 * time.Second)
+
+	updated := *ins
+	updated.State = cluster.Busy
+	updated.Metadata = map[string]string{"region": "b"}
+	if err = reg.Update(ctx, &updated); err != nil {
+		t.Fatal(err)
+	}
+
+	// 更新期间服务实例始终存在，不应出现注销后重新注册的事件
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case services := <-snapshots:
+			var found *registry.ServiceInstance
+			for _, service := range services {
+				if service.ID == ins.ID {
+					found = service
+				}
+			}
+
+			if found == nil {
+				t.Fatal("the service instance should not be deregistered during the update")
+			}
+
+			if found.State == cluster.Busy {
+				if found.Metadata["region"] != "b" || found.Version != "v1" {
+					t.Fatalf("unexpected updated service instance: %+v", found)
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("the updated service instance should be watched")
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"context"
 	"fmt"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"sync/atomic"
 	"time"
 
 	"github.com/dobyte/due/registry"
@@ -19,7 +20,6 @@ import (
 type heartbeat struct {
 	leaseID clientv3.LeaseID
 	key     string
-	value   string
 }
 
 type registrar struct {
@@ -29,6 +29,8 @@ type registrar struct {
 	kv          clientv3.KV
 	lease       clientv3.Lease
 	chHeartbeat chan heartbeat
+	leaseID     int64        // 当前注册使用的租约ID
+	value       atomic.Value // 当前注册的服务实例数据，租约失效重新写入时使用
 }
 
 func newRegistrar(registry *Registry) *registrar {
@@ -57,7 +59,7 @@ func newRegistrar(registry *Registry) *registrar {
 				}
 
 				ctx, cancel = context.WithCancel(r.ctx)
-				go r.heartbeat(ctx, heartbeat.leaseID, heartbeat.key, heartbeat.value)
+				go r.heartbeat(ctx, heartbeat.leaseID, heartbeat.key)
 			case <-r.ctx.Done():
 				if cancel != nil {
 					cancel()
@@ -79,6 +81,8 @@ func (r *registrar) register(ctx context.Context, ins *registry.ServiceInstance)
 
 	key := fmt.Sprintf("/%s/%s/%s", r.registry.opts.namespace, ins.Name, ins.ID)
 
+	r.value.Store(value)
+
 	leaseID, err := r.put(ctx, key, value)
 	if err != nil {
 		return err
@@ -87,13 +91,30 @@ func (r *registrar) register(ctx context.Context, ins *registry.ServiceInstance)
 	r.chHeartbeat <- heartbeat{
 		leaseID: leaseID,
 		key:     key,
-		value:   value,
 	}
 
 	return nil
 }
 
+// 更新服务
+func (r *registrar) update(ctx context.Context, ins *registry.ServiceInstance) error {
+	value, err := marshal(ins)
+	if err != nil {
+		return err
+	}
+
+	r.value.Store(value)
+
+	key := fmt.Sprintf("/%s/%s/%s", r.registry.opts.namespace, ins.Name, ins.ID)
+	leaseID := clientv3.LeaseID(atomic.LoadInt64(&r.leaseID))
+
+	_, err = r.kv.Put(ctx, key, value, clientv3.WithLease(leaseID))
+
+	return err
+}
+
 // 解注册服务
+// 仅删除由当前租约写入的服务实例，避免热重启时误删新进程以相同ID注册的服务实例
 func (r *registrar) deregister(ctx context.Context, ins *registry.ServiceInstance) (err error) {
 	r.cancel()
 	close(r.chHeartbeat)
@@ -101,7 +122,11 @@ func (r *registrar) deregister(ctx context.Context, ins *registry.ServiceInstanc
 	r.registry.registrars.Delete(ins.ID)
 
 	key := fmt.Sprintf("/%s/%s/%s", r.registry.opts.namespace, ins.Name, ins.ID)
-	_, err = r.kv.Delete(ctx, key)
+	leaseID := clientv3.LeaseID(atomic.LoadInt64(&r.leaseID))
+	_, err = r.kv.Txn(ctx).
+		If(clientv3.Compare(clientv3.LeaseValue(key), "=", leaseID)).
+		Then(clientv3.OpDelete(key)).
+		Commit()
 
 	if r.lease != nil {
 		_ = r.lease.Close()
@@ -122,11 +147,13 @@ func (r *registrar) put(ctx context.Context, key, value string) (clientv3.LeaseI
 		return 0, err
 	}
 
+	atomic.StoreInt64(&r.leaseID, int64(res.ID))
+
 	return res.ID, nil
 }
 
 // 心跳
-func (r *registrar) heartbeat(ctx context.Context, leaseID clientv3.LeaseID, key, value string) {
+func (r *registrar) heartbeat(ctx context.Context, leaseID clientv3.LeaseID, key string) {
 	chKA, err := r.lease.KeepAlive(ctx, leaseID)
 	ok := err == nil
 
@@ -138,7 +165,7 @@ func (r *registrar) heartbeat(ctx context.Context, leaseID clientv3.LeaseID, key
 				}
 
 				pctx, pcancel := context.WithTimeout(ctx, r.registry.opts.timeout)
-				leaseID, err = r.put(pctx, key, value)
+				leaseID, err = r.put(pctx, key, r.value.Load().(string))
 				pcancel()
 				if err != nil {
 					time.Sleep(r.registry.opts.retryInterval)
@@ -89,6 +89,21 @@ func (r *Registry) Deregister(ctx context.Context, ins *registry.ServiceInstance
 	return err
 }
 
+// Update 更新已注册的服务实例
+// 仅支持更新由当前注册器注册的服务实例，更新沿用原有租约
+func (r *Registry) Update(ctx context.Context, ins *registry.ServiceInstance) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	v, ok := r.registrars.Load(ins.ID)
+	if !ok {
+		return registry.ErrNotRegistered
+	}
+
+	return v.(*registrar).update(ctx, ins)
+}
+
 // Watch 监听相同服务名的服务实例变化
 func (r *Registry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
 	if r.err != nil {
@@ -2,14 +2,19 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"github.com/dobyte/due/cluster"
 )
 
+var ErrNotRegistered = errors.New("the service instance is not registered")
+
 type Registry interface {
 	// Register 注册服务实例
 	Register(ctx context.Context, ins *ServiceInstance) error
 	// Deregister 解注册服务实例
 	Deregister(ctx context.Context, ins *ServiceInstance) error
+	// Update 更新已注册的服务实例，用于变更状态、权重、版本及元数据，无需解注册
+	Update(ctx context.Context, ins *ServiceInstance) error
 	// Watch 监听相同服务名的服务实例变化
 	Watch(ctx context.Context, serviceName string) (Watcher, error)
 	// Services 获取服务实例列表
@@ -32,12 +37,24 @@ type ServiceInstance struct {
 	Kind cluster.Kind `json:"kind"`
 	// 服务实体别名
 	Alias string `json:"alias"`
+	// 服务实例分组，仅节点有效，为空时为默认分组
+	Group string `json:"group"`
+	// 服务实例所在游戏区，不同游戏区的实例互不可见，为空时为默认游戏区
+	Zone string `json:"zone"`
 	// 服务实例状态
 	State cluster.State `json:"state"`
 	// 服务路由ID
 	Routes []Route `json:"routes"`
 	// 服务器实体暴露端口
 	Endpoint string `json:"endpoint"`
+	// 服务实体对外暴露的客户端连接地址，仅网关有效
+	Address string `json:"address"`
+	// 服务实例权重，用于加权负载均衡，不大于0时按1处理
+	Weight int `json:"weight"`
+	// 服务实例版本
+	Version string `json:"version"`
+	// 服务实例元数据
+	Metadata map[string]string `json:"metadata"`
 }
 
 type Route struct {
@@ -45,4 +62,6 @@ type Route struct {
 	ID int32 `json:"id"`
 	// 是否有状态
 	Stateful bool `json:"stateful"`
+	// 是否按用户ID一致性哈希分片，仅有状态路由有效
+	Sharding bool `json:"sharding"`
 }
@@ -0,0 +1,67 @@
+package router
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const ringReplicas = 160 // 每个实例的虚拟节点数
+
+// 一致性哈希环
+// 哈希值仅由实例ID和用户ID决定，保证网关与节点对同一实例集合计算出相同的归属实例
+type ring struct {
+	members []string          // 实例ID，按字典序排列
+	hashes  []uint32          // 虚拟节点哈希值，升序排列
+	owners  map[uint32]string // 虚拟节点 -> 实例ID
+}
+
+func newRing(members []string) *ring {
+	sort.Strings(members)
+
+	r := &ring{
+		members: members,
+		hashes:  make([]uint32, 0, len(members)*ringReplicas),
+		owners:  make(map[uint32]string, len(members)*ringReplicas),
+	}
+
+	for _, member := range members {
+		for i := 0; i < ringReplicas; i++ {
+			h := crc32.ChecksumIEEE([]byte(member + "#" + strconv.Itoa(i)))
+			if _, ok := r.owners[h]; ok {
+				continue
+			}
+			r.owners[h] = member
+			r.hashes = append(r.hashes, h)
+		}
+	}
+
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+
+	return r
+}
+
+// 查找用户归属实例
+func (r *ring) get(uid int64) (string, bool) {
+	if len(r.hashes) == 0 {
+		return "", false
+	}
+
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], uint64(uid))
+	h := crc32.ChecksumIEEE(key[:])
+
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+
+	return r.owners[r.hashes[i]], true
+}
+
+// 成员标识，用于判断哈希环是否发生变化
+func (r *ring) signature() string {
+	return strings.Join(r.members, ",")
+}
@@ -0,0 +1,101 @@
+package router
+
+import (
+	"fmt"
+	"testing"
+)
+
+const ringUsers = 10000
+
+func ringMembers(n int) []string {
+	members := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		members = append(members, fmt.Sprintf("node-%d", i))
+	}
+
+	return members
+}
+
+func ringOwners(r *ring) map[int64]string {
+	owners := make(map[int64]string, ringUsers)
+	for uid := int64(1); uid <= ringUsers; uid++ {
+		owner, ok := r.get(uid)
+		if !ok {
+			return nil
+		}
+		owners[uid] = owner
+	}
+
+	return owners
+}
+
+func TestRing_Empty(t *testing.T) {
+	r := newRing(nil)
+
+	if _, ok := r.get(1); ok {
+		t.Fatal("expected no owner on an empty ring")
+	}
+
+	if r.signature() != "" {
+		t.Fatalf("unexpected signature: %s", r.signature())
+	}
+}
+
+func TestRing_Stable(t *testing.T) {
+	r1 := newRing([]string{"node-2", "node-0", "node-1"})
+	r2 := newRing([]string{"node-1", "node-2", "node-0"})
+
+	if r1.signature() != r2.signature() {
+		t.Fatalf("signature mismatch: %s != %s", r1.signature(), r2.signature())
+	}
+
+	o1, o2 := ringOwners(r1), ringOwners(r2)
+	for uid, owner := range o1 {
+		if o2[uid] != owner {
+			t.Fatalf("owner mismatch, uid: %d, %s != %s", uid, owner, o2[uid])
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, owner := range o1 {
+		counts[owner]++
+	}
+
+	for member, n := range counts {
+		if n < ringUsers/3/2 {
+			t.Fatalf("unbalanced ring, member: %s, users: %d", member, n)
+		}
+	}
+}
+
+func TestRing_Join(t *testing.T) {
+	before := ringOwners(newRing(ringMembers(4)))
+	after := ringOwners(newRing(ringMembers(5)))
+
+	moved := 0
+	for uid, owner := range before {
+		if after[uid] == owner {
+			continue
+		}
+		if after[uid] != "node-4" {
+			t.Fatalf("user %d moved between existing members: %s -> %s", uid, owner, after[uid])
+		}
+		moved++
+	}
+
+	// 理想情况下约1/5的用户迁移到新实例
+	if moved == 0 || moved > ringUsers*2/5 {
+		t.Fatalf("unexpected remapping on join: %d", moved)
+	}
+}
+
+func TestRing_Leave(t *testing.T) {
+	before := ringOwners(newRing(ringMembers(5)))
+	after := ringOwners(newRing(ringMembers(4)))
+
+	for uid, owner := range before {
+		if owner != "node-4" && after[uid] != owner {
+			t.Fatalf("user %d of a remaining member moved: %s -> %s", uid, owner, after[uid])
+		}
+	}
+}
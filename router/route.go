@@ -1,66 +1,250 @@
 package router
 
 import (
+	"github.com/dobyte/due/cluster"
 	"github.com/dobyte/due/internal/endpoint"
+	"github.com/dobyte/due/utils/xrand"
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
+// BalanceStrategy 负载均衡策略
+type BalanceStrategy string
+
 const (
-	balanceStrategyRandom           = "random" // 随机
-	balanceStrategyRoundRobin       = "rr"     // 轮询
-	balanceStrategyWeightRoundRobin = "wrr"    // 加权轮询
+	RandomStrategy           BalanceStrategy = "random" // 随机
+	RoundRobinStrategy       BalanceStrategy = "rr"     // 轮询
+	WeightRoundRobinStrategy BalanceStrategy = "wrr"    // 加权轮询
+	LeastConnStrategy        BalanceStrategy = "lc"     // 最少连接
 )
 
 type Route struct {
-	id              int32    // 路由ID
-	stateful        bool     // 是否有状态
-	endpoints       sync.Map // 服务端口
-	balanceStrategy string   // 负载均衡策略
+	id        int32           // 路由ID
+	group     string          // 节点分组
+	stateful  bool            // 是否有状态
+	sharding  bool            // 是否按用户ID一致性哈希分片
+	strategy  BalanceStrategy // 负载均衡策略
+	rules     *rules          // 灰度路由规则
+	endpoints sync.Map        // 服务端口（实例ID -> *serviceEndpoint）
+	works     atomic.Value    // 工作状态的服务端口快照（[]*serviceEndpoint），按实例ID排序
+	ring      atomic.Value    // 分片哈希环（*ring）
+	counter   uint64          // 轮询计数
+	mu        sync.Mutex      // 加权轮询锁
+	currents  map[string]int  // 加权轮询的当前权重
 }
 
 type Endpoint = endpoint.Endpoint
 
+type serviceEndpoint struct {
+	insID   string        // 服务实例ID
+	state   cluster.State // 服务实例状态
+	weight  int           // 服务实例权重
+	version string        // 服务实例版本
+	ep      *Endpoint     // 服务端口
+	conns   *int64        // 进行中的请求数
+}
+
+// Group 路由所属的节点分组
+func (r *Route) Group() string {
+	return r.group
+}
+
 // Stateful 是否有状态
 func (r *Route) Stateful() bool {
 	return r.stateful
 }
 
+// Sharding 是否按用户ID一致性哈希分片
+func (r *Route) Sharding() bool {
+	return r.sharding
+}
+
+// Strategy 负载均衡策略
+func (r *Route) Strategy() BalanceStrategy {
+	return r.strategy
+}
+
+// FindShardOwner 查询用户在分片路由中的归属实例
+func (r *Route) FindShardOwner(uid int64) (string, error) {
+	if rg, ok := r.ring.Load().(*ring); ok {
+		if insID, ok := rg.get(uid); ok {
+			return insID, nil
+		}
+	}
+
+	return "", ErrNotFoundEndpoint
+}
+
 // FindEndpoint 查询路由服务端口
-func (r *Route) FindEndpoint(insID string) (*Endpoint, error) {
-	if insID == "" {
-		switch r.balanceStrategy {
-		case balanceStrategyRandom:
-			return r.randomDispatch()
-		case balanceStrategyRoundRobin:
-			return r.roundRobinDispatch()
-		default:
-			return r.randomDispatch()
+// 未指定实例ID时，按负载均衡策略从工作状态的实例中分配，繁忙、挂起和关闭状态的实例不参与分配；
+// 无状态路由先按灰度路由规则筛选出用户可分配的版本实例
+func (r *Route) FindEndpoint(insID string, uid int64) (*Endpoint, error) {
+	se, err := r.findServiceEndpoint(insID, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return se.ep, nil
+}
+
+// Acquire 查询路由服务端口并记录进行中的请求，请求结束后须调用release
+// 最少连接策略按进行中的请求数分配实例
+func (r *Route) Acquire(insID string, uid int64) (ep *Endpoint, release func(), err error) {
+	se, err := r.findServiceEndpoint(insID, uid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	atomic.AddInt64(se.conns, 1)
+
+	return se.ep, func() { atomic.AddInt64(se.conns, -1) }, nil
+}
+
+func (r *Route) findServiceEndpoint(insID string, uid int64) (*serviceEndpoint, error) {
+	if insID != "" {
+		val, ok := r.endpoints.Load(insID)
+		if !ok {
+			return nil, ErrNotFoundEndpoint
 		}
+
+		return val.(*serviceEndpoint), nil
 	}
 
-	val, ok := r.endpoints.Load(insID)
-	if !ok {
+	works, _ := r.works.Load().([]*serviceEndpoint)
+	if !r.stateful && r.rules != nil {
+		works = r.rules.filter(r.id, uid, works)
+	}
+	if len(works) == 0 {
 		return nil, ErrNotFoundEndpoint
 	}
 
-	return val.(*Endpoint), nil
+	switch r.strategy {
+	case RoundRobinStrategy:
+		return r.roundRobinDispatch(works), nil
+	case WeightRoundRobinStrategy:
+		return r.weightRoundRobinDispatch(works), nil
+	case LeastConnStrategy:
+		return r.leastConnDispatch(works), nil
+	default:
+		return r.randomDispatch(works), nil
+	}
 }
 
 // 随机分配
-func (r *Route) randomDispatch() (ep *Endpoint, err error) {
-	r.endpoints.Range(func(_, val interface{}) bool {
-		ep = val.(*Endpoint)
-		return false
-	})
+func (r *Route) randomDispatch(works []*serviceEndpoint) *serviceEndpoint {
+	return works[xrand.Int(0, len(works)-1)]
+}
+
+// 轮询分配
+func (r *Route) roundRobinDispatch(works []*serviceEndpoint) *serviceEndpoint {
+	n := atomic.AddUint64(&r.counter, 1)
+
+	return works[(n-1)%uint64(len(works))]
+}
+
+// 加权轮询分配（平滑加权轮询）
+func (r *Route) weightRoundRobinDispatch(works []*serviceEndpoint) *serviceEndpoint {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	if ep == nil {
-		err = ErrNotFoundEndpoint
+	if r.currents == nil {
+		r.currents = make(map[string]int, len(works))
 	}
 
-	return
+	var (
+		total int
+		best  *serviceEndpoint
+	)
+
+	for _, se := range works {
+		total += se.weight
+		r.currents[se.insID] += se.weight
+		if best == nil || r.currents[se.insID] > r.currents[best.insID] {
+			best = se
+		}
+	}
+
+	r.currents[best.insID] -= total
+
+	return best
 }
 
-// 轮询分配
-func (r *Route) roundRobinDispatch() (ep *Endpoint, err error) {
-	return r.randomDispatch()
+// 移除已不在工作状态的实例的加权轮询权重
+func (r *Route) pruneCurrents(works []*serviceEndpoint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.currents) == 0 {
+		return
+	}
+
+	set := make(map[string]struct{}, len(works))
+	for _, se := range works {
+		set[se.insID] = struct{}{}
+	}
+
+	for insID := range r.currents {
+		if _, ok := set[insID]; !ok {
+			delete(r.currents, insID)
+		}
+	}
+}
+
+// 最少连接分配，进行中的请求数相同时按轮询分配
+func (r *Route) leastConnDispatch(works []*serviceEndpoint) *serviceEndpoint {
+	offset := int(atomic.AddUint64(&r.counter, 1) % uint64(len(works)))
+
+	var (
+		best  *serviceEndpoint
+		least int64
+	)
+
+	for i := range works {
+		se := works[(offset+i)%len(works)]
+		if conns := atomic.LoadInt64(se.conns); best == nil || conns < least {
+			best, least = se, conns
+		}
+	}
+
+	return best
+}
+
+// 注册信息是否一致，一致时可沿用路由的分配状态
+func (r *Route) compatible(route *Route) bool {
+	return r.group == route.group && r.stateful == route.stateful && r.sharding == route.sharding &&
+		r.strategy == route.strategy && r.rules == route.rules
+}
+
+// 重建工作状态的服务端口快照，分片路由同时重建哈希环
+// 工作和繁忙状态的实例参与分片，繁忙状态的实例保留其分片，避免负载波动引起用户迁移
+func (r *Route) rebuild() *ring {
+	var (
+		works   = make([]*serviceEndpoint, 0)
+		members = make([]string, 0)
+	)
+
+	r.endpoints.Range(func(key, val interface{}) bool {
+		switch se := val.(*serviceEndpoint); se.state {
+		case cluster.Work:
+			works = append(works, se)
+			members = append(members, se.insID)
+		case cluster.Busy:
+			members = append(members, se.insID)
+		}
+		return true
+	})
+
+	sort.Slice(works, func(i, j int) bool { return works[i].insID < works[j].insID })
+	r.works.Store(works)
+	r.pruneCurrents(works)
+
+	if !r.sharding {
+		return nil
+	}
+
+	rg := newRing(members)
+	r.ring.Store(rg)
+
+	return rg
 }
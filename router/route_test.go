@@ -0,0 +1,232 @@
+package router
+
+import (
+	"fmt"
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/internal/endpoint"
+	"github.com/dobyte/due/registry"
+	"testing"
+)
+
+func newTestRoute(strategy BalanceStrategy, weights ...int) *Route {
+	r := &Route{id: 1, strategy: strategy}
+	for i, weight := range weights {
+		insID := fmt.Sprintf("node-%d", i)
+		r.endpoints.Store(insID, &serviceEndpoint{
+			insID:  insID,
+			state:  cluster.Work,
+			weight: weight,
+			ep:     endpoint.NewEndpoint("grpc", insID, false),
+			conns:  new(int64),
+		})
+	}
+	r.rebuild()
+
+	return r
+}
+
+func dispatchCounts(t *testing.T, r *Route, n int) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		se, err := r.findServiceEndpoint("", int64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[se.insID]++
+	}
+
+	return counts
+}
+
+func TestRoute_RandomDispatch(t *testing.T) {
+	counts := dispatchCounts(t, newTestRoute(RandomStrategy, 1, 1, 1), 3000)
+
+	for insID, n := range counts {
+		if n < 800 || n > 1200 {
+			t.Fatalf("unbalanced random dispatch, instance: %s, count: %d", insID, n)
+		}
+	}
+}
+
+func TestRoute_RoundRobinDispatch(t *testing.T) {
+	r := newTestRoute(RoundRobinStrategy, 1, 1, 1)
+
+	for i := 0; i < 6; i++ {
+		se, _ := r.findServiceEndpoint("", 0)
+		if expected := fmt.Sprintf("node-%d", i%3); se.insID != expected {
+			t.Fatalf("round %d: expected %s, got %s", i, expected, se.insID)
+		}
+	}
+}
+
+func TestRoute_WeightRoundRobinDispatch(t *testing.T) {
+	r := newTestRoute(WeightRoundRobinStrategy, 5, 1, 1)
+
+	expected := []string{"node-0", "node-0", "node-1", "node-0", "node-2", "node-0", "node-0"}
+	for i, insID := range expected {
+		se, _ := r.findServiceEndpoint("", 0)
+		if se.insID != insID {
+			t.Fatalf("round %d: expected %s, got %s", i, insID, se.insID)
+		}
+	}
+
+	counts := dispatchCounts(t, r, 700)
+	if counts["node-0"] != 500 || counts["node-1"] != 100 || counts["node-2"] != 100 {
+		t.Fatalf("unexpected weighted dispatch: %v", counts)
+	}
+}
+
+func TestRoute_LeastConnDispatch(t *testing.T) {
+	r := newTestRoute(LeastConnStrategy, 1, 1, 1)
+
+	releases := make(map[string]func(), 3)
+	for i := 0; i < 3; i++ {
+		ep, release, err := r.Acquire("", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		releases[ep.Address()] = release
+	}
+
+	if len(releases) != 3 {
+		t.Fatalf("expected requests spread across all instances, got %d", len(releases))
+	}
+
+	releases["node-1"]()
+
+	for i := 0; i < 3; i++ {
+		ep, release, _ := r.Acquire("", 0)
+		if ep.Address() != "node-1" {
+			t.Fatalf("expected the least connected instance node-1, got %s", ep.Address())
+		}
+		release()
+	}
+}
+
+func TestRoute_PruneCurrents(t *testing.T) {
+	r := newTestRoute(WeightRoundRobinStrategy, 1, 1, 1)
+	dispatchCounts(t, r, 10)
+
+	r.endpoints.Delete("node-2")
+	r.rebuild()
+
+	if _, ok := r.currents["node-2"]; ok {
+		t.Fatal("the departed instance was not pruned from the weighted round robin state")
+	}
+}
+
+func testNodeService(id, group string, routes ...int32) *registry.ServiceInstance {
+	service := &registry.ServiceInstance{
+		ID:       id,
+		Kind:     cluster.Node,
+		Group:    group,
+		State:    cluster.Work,
+		Endpoint: endpoint.NewEndpoint("grpc", id, false).String(),
+	}
+	for _, route := range routes {
+		service.Routes = append(service.Routes, registry.Route{ID: route})
+	}
+
+	return service
+}
+
+func TestRouter_ReplaceServices(t *testing.T) {
+	r := NewRouter()
+	r.ReplaceServices(testNodeService("node-0", "", 1), testNodeService("node-1", "", 1))
+
+	before, err := r.FindNodeRoute(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.ReplaceServices(testNodeService("node-1", "", 1), testNodeService("node-2", "", 1))
+
+	after, err := r.FindNodeRoute(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if before != after {
+		t.Fatal("the route was rebuilt and lost its balancing state")
+	}
+
+	if _, err = after.FindEndpoint("node-0", 0); err != ErrNotFoundEndpoint {
+		t.Fatalf("the departed instance was not pruned: %v", err)
+	}
+
+	for _, insID := range []string{"node-1", "node-2"} {
+		if _, err = after.FindEndpoint(insID, 0); err != nil {
+			t.Fatalf("instance %s not found: %v", insID, err)
+		}
+	}
+}
+
+func TestRouter_RemoveService(t *testing.T) {
+	r := NewRouter()
+	for _, service := range []*registry.ServiceInstance{testNodeService("node-0", "", 1), testNodeService("node-1", "", 1)} {
+		if err := r.AddService(service); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r.RemoveService(testNodeService("node-0", "", 1))
+
+	if _, ok := r.conns["node-0"]; ok {
+		t.Fatal("the request counter of the departed instance was not removed")
+	}
+
+	if _, ok := r.conns["node-1"]; !ok {
+		t.Fatal("the request counter of the remaining instance was removed")
+	}
+
+	route, err := r.FindNodeRoute(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = route.FindEndpoint("node-0", 0); err != ErrNotFoundEndpoint {
+		t.Fatalf("the departed instance was not removed: %v", err)
+	}
+}
+
+func TestRouter_RouteConflict(t *testing.T) {
+	services := []*registry.ServiceInstance{
+		testNodeService("node-0", "b", 1, 2),
+		testNodeService("node-1", "a", 1),
+	}
+
+	for i := 0; i < 2; i++ {
+		r := NewRouter()
+		if i == 0 {
+			r.ReplaceServices(services[0], services[1])
+		} else {
+			r.ReplaceServices(services[1], services[0])
+		}
+
+		route, err := r.FindNodeRoute(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if route.Group() != "a" {
+			t.Fatalf("the conflicting route should be owned by group a, got %q", route.Group())
+		}
+
+		if _, err = route.FindEndpoint("node-0", 0); err != ErrNotFoundEndpoint {
+			t.Fatalf("the instance of group b should not serve the conflicting route: %v", err)
+		}
+
+		if route, err = r.FindNodeRoute(2); err != nil || route.Group() != "b" {
+			t.Fatalf("the route of group b should be kept: %v", err)
+		}
+	}
+
+	r := NewRouter()
+	if err := r.AddService(services[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.AddService(services[0]); err != ErrRouteConflict {
+		t.Fatalf("expected route conflict, got %v", err)
+	}
+}
@@ -3,21 +3,40 @@ package router
 import (
 	"errors"
 	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/config"
 	"github.com/dobyte/due/internal/endpoint"
+	"github.com/dobyte/due/log"
 	"github.com/dobyte/due/registry"
+	"github.com/dobyte/due/utils/xconv"
+	"sort"
+	"strconv"
 	"sync"
 )
 
 var (
 	ErrNotFoundRoute    = errors.New("not found route")
 	ErrNotFoundEndpoint = errors.New("not found endpoint")
+	ErrRouteConflict    = errors.New("the route is already registered by another group")
 )
 
+const (
+	defaultStrategyKey        = "config.cluster.router.strategy"        // 全局负载均衡策略
+	defaultRouteStrategiesKey = "config.cluster.router.routeStrategies" // 路由负载均衡策略（路由ID -> 策略）
+)
+
+// RebalanceHandler 分片路由的哈希环变化处理器
+type RebalanceHandler func(route int32)
+
 type Router struct {
-	rw            sync.RWMutex
-	routes        map[int32]*Route              // 节点路由表
-	gateEndpoints map[string]*endpoint.Endpoint // 网关服务端口
-	nodeEndpoints map[string]*endpoint.Endpoint // 节点服务端口
+	rw                sync.RWMutex
+	routes            map[int32]*Route              // 节点路由表
+	gateEndpoints     map[string]*endpoint.Endpoint // 网关服务端口
+	nodeEndpoints     map[string]*endpoint.Endpoint // 节点服务端口
+	nodeGroups        map[string]string             // 节点分组（实例ID -> 分组）
+	shards            map[int32]string              // 分片路由的哈希环成员标识
+	conns             map[string]*int64             // 节点实例进行中的请求数
+	rules             *rules                        // 灰度路由规则
+	rebalanceHandlers []RebalanceHandler            // 哈希环变化处理器
 }
 
 func NewRouter() *Router {
@@ -25,50 +44,172 @@ func NewRouter() *Router {
 		routes:        make(map[int32]*Route),
 		gateEndpoints: make(map[string]*endpoint.Endpoint),
 		nodeEndpoints: make(map[string]*endpoint.Endpoint),
+		nodeGroups:    make(map[string]string),
+		shards:        make(map[int32]string),
+		conns:         make(map[string]*int64),
+		rules:         loadRules(),
 	}
 }
 
+// Close 关闭路由器，取消监听灰度路由规则的配置变化
+func (r *Router) Close() {
+	r.rules.close()
+}
+
+// OnRebalance 监听分片路由的哈希环变化
+func (r *Router) OnRebalance(handler RebalanceHandler) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.rebalanceHandlers = append(r.rebalanceHandlers, handler)
+}
+
 // ReplaceServices 替换服务实例
+// 服务实例按分组及实例ID排序后依次添加，多个分组注册同一路由时由排序在前的分组持有该路由，与服务实例的发现顺序无关；
+// 路由的注册信息未变化时沿用原路由，保留轮询及加权轮询的分配状态，并移除已下线实例的服务端口
 func (r *Router) ReplaceServices(services ...*registry.ServiceInstance) {
+	services = sortServices(services)
+
 	r.rw.Lock()
-	defer r.rw.Unlock()
 
+	routes := r.routes
 	r.routes = make(map[int32]*Route, len(services))
 	r.gateEndpoints = make(map[string]*endpoint.Endpoint, len(services))
 	r.nodeEndpoints = make(map[string]*endpoint.Endpoint, len(services))
+	r.nodeGroups = make(map[string]string, len(services))
 
 	for _, service := range services {
-		_ = r.addService(service)
+		_ = r.addService(service, routes)
 	}
+
+	r.pruneEndpoints(services)
+
+	for insID := range r.conns {
+		if _, ok := r.nodeEndpoints[insID]; !ok {
+			delete(r.conns, insID)
+		}
+	}
+
+	changed := r.rebalance()
+
+	r.rw.Unlock()
+
+	r.notifyRebalance(changed)
 }
 
 // AddService 添加服务实例
+// 路由已被其他分组注册时，该路由不添加此服务实例并返回ErrRouteConflict
 func (r *Router) AddService(service *registry.ServiceInstance) error {
 	r.rw.Lock()
-	defer r.rw.Unlock()
 
-	return r.addService(service)
+	err := r.addService(service, nil)
+	changed := r.rebalance()
+
+	r.rw.Unlock()
+
+	r.notifyRebalance(changed)
+
+	return err
 }
 
 // RemoveService 移除服务实例
 func (r *Router) RemoveService(service *registry.ServiceInstance) {
 	r.rw.Lock()
-	defer r.rw.Unlock()
 
 	switch service.Kind {
 	case cluster.Gate:
 		delete(r.gateEndpoints, service.ID)
 	case cluster.Node:
+		delete(r.nodeEndpoints, service.ID)
+		delete(r.nodeGroups, service.ID)
+		delete(r.conns, service.ID)
 		for _, item := range service.Routes {
 			if route, ok := r.routes[item.ID]; ok {
 				route.endpoints.Delete(service.ID)
 			}
 		}
 	}
+
+	changed := r.rebalance()
+
+	r.rw.Unlock()
+
+	r.notifyRebalance(changed)
+}
+
+// 重建路由的服务端口快照及分片路由的哈希环，返回哈希环发生变化的路由
+func (r *Router) rebalance() (changed []int32) {
+	shards := make(map[int32]string, len(r.shards))
+	for id, route := range r.routes {
+		rg := route.rebuild()
+		if rg == nil {
+			continue
+		}
+
+		shards[id] = rg.signature()
+		if sig, ok := r.shards[id]; !ok || sig != shards[id] {
+			changed = append(changed, id)
+		}
+	}
+
+	for id := range r.shards {
+		if _, ok := shards[id]; !ok {
+			changed = append(changed, id)
+		}
+	}
+
+	r.shards = shards
+
+	return
+}
+
+// 通知哈希环变化处理器
+func (r *Router) notifyRebalance(changed []int32) {
+	if len(changed) == 0 {
+		return
+	}
+
+	r.rw.RLock()
+	handlers := r.rebalanceHandlers
+	r.rw.RUnlock()
+
+	for _, id := range changed {
+		for _, handler := range handlers {
+			handler(id)
+		}
+	}
+}
+
+// 移除路由中已不再注册该路由的实例的服务端口
+func (r *Router) pruneEndpoints(services []*registry.ServiceInstance) {
+	registered := make(map[int32]map[string]struct{}, len(r.routes))
+	for _, service := range services {
+		if service.Kind != cluster.Node {
+			continue
+		}
+
+		for _, item := range service.Routes {
+			instances, ok := registered[item.ID]
+			if !ok {
+				instances = make(map[string]struct{})
+				registered[item.ID] = instances
+			}
+			instances[service.ID] = struct{}{}
+		}
+	}
+
+	for id, route := range r.routes {
+		route.endpoints.Range(func(key, _ interface{}) bool {
+			if _, ok := registered[id][key.(string)]; !ok {
+				route.endpoints.Delete(key)
+			}
+			return true
+		})
+	}
 }
 
-// 添加服务实例
-func (r *Router) addService(service *registry.ServiceInstance) error {
+// 添加服务实例，reuses 中注册信息未变化的路由将被沿用
+func (r *Router) addService(service *registry.ServiceInstance, reuses map[int32]*Route) error {
 	ep, err := endpoint.ParseEndpoint(service.Endpoint)
 	if err != nil {
 		return err
@@ -79,20 +220,66 @@ func (r *Router) addService(service *registry.ServiceInstance) error {
 		r.gateEndpoints[service.ID] = ep
 	case cluster.Node:
 		r.nodeEndpoints[service.ID] = ep
+		r.nodeGroups[service.ID] = service.Group
+
+		conns, ok := r.conns[service.ID]
+		if !ok {
+			conns = new(int64)
+			r.conns[service.ID] = conns
+		}
+
+		weight := service.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+
 		for _, item := range service.Routes {
 			route, ok := r.routes[item.ID]
 			if !ok {
 				route = &Route{
 					id:       item.ID,
+					group:    service.Group,
 					stateful: item.Stateful,
+					sharding: item.Stateful && item.Sharding,
+					strategy: loadStrategy(item.ID),
+					rules:    r.rules,
+				}
+				if reuse, ok := reuses[item.ID]; ok && reuse.compatible(route) {
+					route = reuse
 				}
 				r.routes[item.ID] = route
+			} else if route.group != service.Group {
+				log.Warnf("the route %d is already registered by group %q, ignored for instance %s of group %q", item.ID, route.group, service.ID, service.Group)
+				err = ErrRouteConflict
+				continue
 			}
-			route.endpoints.Store(service.ID, ep)
+			route.endpoints.Store(service.ID, &serviceEndpoint{
+				insID:   service.ID,
+				state:   service.State,
+				weight:  weight,
+				version: service.Version,
+				ep:      ep,
+				conns:   conns,
+			})
 		}
 	}
 
-	return nil
+	return err
+}
+
+// 按分组及实例ID排序服务实例
+func sortServices(services []*registry.ServiceInstance) []*registry.ServiceInstance {
+	sorted := make([]*registry.ServiceInstance, len(services))
+	copy(sorted, services)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Group != sorted[j].Group {
+			return sorted[i].Group < sorted[j].Group
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	return sorted
 }
 
 // FindGateEndpoint 查找网关服务端口
@@ -146,6 +333,36 @@ func (r *Router) FindNodeEndpoint(insID string) (*Endpoint, error) {
 	return ep, nil
 }
 
+// FindNodeGroup 查找节点实例所在分组
+func (r *Router) FindNodeGroup(insID string) (string, error) {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	group, ok := r.nodeGroups[insID]
+	if !ok {
+		return "", ErrNotFoundEndpoint
+	}
+
+	return group, nil
+}
+
+// NodeGroups 获取所有节点分组
+func (r *Router) NodeGroups() []string {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	set := make(map[string]struct{})
+	groups := make([]string, 0)
+	for _, group := range r.nodeGroups {
+		if _, ok := set[group]; !ok {
+			set[group] = struct{}{}
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
 // RangeNodeEndpoint 轮询网关服务端口
 func (r *Router) RangeNodeEndpoint(fn func(insID string, ep *Endpoint) bool) {
 	r.rw.RLock()
@@ -157,3 +374,12 @@ func (r *Router) RangeNodeEndpoint(fn func(insID string, ep *Endpoint) bool) {
 		}
 	}
 }
+
+// 加载路由的负载均衡策略，优先使用路由单独配置的策略，未配置时使用随机策略
+func loadStrategy(route int32) BalanceStrategy {
+	if v, ok := config.Get(defaultRouteStrategiesKey).Map()[strconv.Itoa(int(route))]; ok {
+		return BalanceStrategy(xconv.String(v))
+	}
+
+	return BalanceStrategy(config.Get(defaultStrategyKey, string(RandomStrategy)).String())
+}
@@ -0,0 +1,199 @@
+package router
+
+import (
+	"encoding/binary"
+	"github.com/dobyte/due/config"
+	"github.com/dobyte/due/log"
+	"github.com/dobyte/due/utils/xrand"
+	"hash/crc32"
+	"sync/atomic"
+)
+
+const defaultRulesKey = "config.cluster.router.rules" // 灰度路由规则
+
+// 灰度路由规则
+type rule struct {
+	Route   int32   `json:"route"`   // 路由ID，为0时作用于所有无状态路由
+	Version string  `json:"version"` // 目标版本
+	Percent int     `json:"percent"` // 按用户ID分流到目标版本的百分比，同一用户始终分流到相同版本
+	UIDs    []int64 `json:"uids"`    // 固定分流到目标版本的用户ID
+	uids    map[int64]struct{}
+}
+
+type ruleEntry struct {
+	rule  *rule
+	lower int // 分流区间下界（含）
+	upper int // 分流区间上界（不含）
+}
+
+// 单个路由的规则，路由单独配置的规则优先于全局规则，多条规则的分流区间依次排列互不重叠
+type rulePolicy struct {
+	entries  []ruleEntry
+	versions map[string]struct{} // 规则的目标版本
+}
+
+type ruleSet struct {
+	global *rulePolicy
+	routes map[int32]*rulePolicy
+}
+
+// 灰度路由规则，配置变化时重新加载
+type rules struct {
+	value  atomic.Value // *ruleSet
+	cancel func()       // 取消监听配置变化
+}
+
+// 加载灰度路由规则并监听配置变化，规则来自全局配置，每个路由器持有各自的规则，关闭路由器时取消监听
+func loadRules() *rules {
+	rs := &rules{}
+	rs.load()
+	rs.cancel = config.OnChange(rs.load)
+
+	return rs
+}
+
+// 取消监听配置变化
+func (rs *rules) close() {
+	rs.cancel()
+}
+
+// 加载规则
+func (rs *rules) load() {
+	var items []*rule
+	if err := config.Get(defaultRulesKey).Scan(&items); err != nil {
+		log.Warnf("load router rules failed: %v", err)
+		return
+	}
+
+	var (
+		global = make([]*rule, 0, len(items))
+		routes = make(map[int32][]*rule)
+	)
+
+	for _, item := range items {
+		if item == nil || item.Version == "" {
+			continue
+		}
+
+		item.uids = make(map[int64]struct{}, len(item.UIDs))
+		for _, uid := range item.UIDs {
+			item.uids[uid] = struct{}{}
+		}
+
+		if item.Route == 0 {
+			global = append(global, item)
+		} else {
+			routes[item.Route] = append(routes[item.Route], item)
+		}
+	}
+
+	set := &ruleSet{routes: make(map[int32]*rulePolicy, len(routes))}
+	if len(global) > 0 {
+		set.global = newRulePolicy(global)
+	}
+	for route, items := range routes {
+		set.routes[route] = newRulePolicy(append(items, global...))
+	}
+
+	rs.value.Store(set)
+}
+
+// 按规则筛选服务端口
+// 命中规则时返回目标版本的服务端口，目标版本不存在服务端口时继续匹配后续规则；
+// 未命中任何规则时返回非目标版本的服务端口，不存在非目标版本的服务端口时返回全部服务端口
+func (rs *rules) filter(route int32, uid int64, works []*serviceEndpoint) []*serviceEndpoint {
+	set, _ := rs.value.Load().(*ruleSet)
+	if set == nil {
+		return works
+	}
+
+	policy, ok := set.routes[route]
+	if !ok {
+		policy = set.global
+	}
+	if policy == nil {
+		return works
+	}
+
+	bucket := hashBucket(uid)
+
+	for _, entry := range policy.entries {
+		if !entry.hit(uid, bucket) {
+			continue
+		}
+
+		if matched := filterEndpoints(works, func(version string) bool {
+			return version == entry.rule.Version
+		}); len(matched) > 0 {
+			return matched
+		}
+	}
+
+	if others := filterEndpoints(works, func(version string) bool {
+		_, ok := policy.versions[version]
+		return !ok
+	}); len(others) > 0 {
+		return others
+	}
+
+	return works
+}
+
+func newRulePolicy(items []*rule) *rulePolicy {
+	policy := &rulePolicy{
+		entries:  make([]ruleEntry, 0, len(items)),
+		versions: make(map[string]struct{}, len(items)),
+	}
+
+	offset := 0
+	for _, item := range items {
+		entry := ruleEntry{rule: item, lower: offset, upper: offset}
+		if item.Percent > 0 {
+			entry.upper = offset + item.Percent
+			if entry.upper > 100 {
+				entry.upper = 100
+			}
+			offset = entry.upper
+		}
+
+		policy.entries = append(policy.entries, entry)
+		policy.versions[item.Version] = struct{}{}
+	}
+
+	return policy
+}
+
+// 是否命中规则
+func (e ruleEntry) hit(uid int64, bucket int) bool {
+	if uid != 0 {
+		if _, ok := e.rule.uids[uid]; ok {
+			return true
+		}
+	}
+
+	return bucket >= e.lower && bucket < e.upper
+}
+
+// 计算用户的分流桶，未指定用户时随机分配
+func hashBucket(uid int64) int {
+	if uid == 0 {
+		return xrand.Int(0, 99)
+	}
+
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], uint64(uid))
+
+	return int(crc32.ChecksumIEEE(key[:]) % 100)
+}
+
+// 按版本筛选服务端口
+func filterEndpoints(works []*serviceEndpoint, fn func(version string) bool) []*serviceEndpoint {
+	matched := make([]*serviceEndpoint, 0, len(works))
+	for _, se := range works {
+		if fn(se.version) {
+			matched = append(matched, se)
+		}
+	}
+
+	return matched
+}
@@ -0,0 +1,98 @@
+package router
+
+import (
+	"github.com/dobyte/due/cluster"
+	"github.com/dobyte/due/config"
+	"testing"
+)
+
+func versionEndpoints(versions ...string) []*serviceEndpoint {
+	works := make([]*serviceEndpoint, 0, len(versions))
+	for _, version := range versions {
+		works = append(works, &serviceEndpoint{insID: version, state: cluster.Work, version: version})
+	}
+
+	return works
+}
+
+func filterVersions(rs *rules, route int32, uid int64, works []*serviceEndpoint) map[string]bool {
+	versions := make(map[string]bool)
+	for _, se := range rs.filter(route, uid, works) {
+		versions[se.version] = true
+	}
+
+	return versions
+}
+
+func setRules(t *testing.T, items []map[string]interface{}) {
+	list := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		list = append(list, item)
+	}
+
+	if err := config.Set(defaultRulesKey, list); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRules_Filter(t *testing.T) {
+	setRules(t, []map[string]interface{}{
+		{"route": 1, "version": "v2", "uids": []interface{}{7}},
+		{"version": "v3", "percent": 100},
+	})
+	defer setRules(t, nil)
+
+	rs := loadRules()
+	defer rs.close()
+	works := versionEndpoints("v1", "v2", "v3")
+
+	if v := filterVersions(rs, 1, 7, works); len(v) != 1 || !v["v2"] {
+		t.Fatalf("the pinned user should be routed to v2, got %v", v)
+	}
+
+	if v := filterVersions(rs, 1, 8, works); len(v) != 1 || !v["v3"] {
+		t.Fatalf("the global rule should route all other users to v3, got %v", v)
+	}
+
+	if v := filterVersions(rs, 2, 7, works); len(v) != 1 || !v["v3"] {
+		t.Fatalf("the route rule should not apply to other routes, got %v", v)
+	}
+
+	if v := filterVersions(rs, 1, 8, versionEndpoints("v1", "v2")); len(v) != 1 || !v["v1"] {
+		t.Fatalf("users should fall back to non-target versions, got %v", v)
+	}
+}
+
+func TestRules_Reload(t *testing.T) {
+	rs := loadRules()
+	defer rs.close()
+	works := versionEndpoints("v1", "v2")
+
+	setRules(t, nil)
+	if v := filterVersions(rs, 1, 7, works); len(v) != 2 {
+		t.Fatalf("all versions should be routable without rules, got %v", v)
+	}
+
+	setRules(t, []map[string]interface{}{{"version": "v2", "percent": 100}})
+	defer setRules(t, nil)
+
+	if v := filterVersions(rs, 1, 7, works); len(v) != 1 || !v["v2"] {
+		t.Fatalf("the reloaded rule should route users to v2, got %v", v)
+	}
+
+}
+
+func TestRules_Close(t *testing.T) {
+	setRules(t, nil)
+
+	r := NewRouter()
+	r.Close()
+
+	setRules(t, []map[string]interface{}{{"version": "v2", "percent": 100}})
+	defer setRules(t, nil)
+
+	// 路由器关闭后不再重新加载规则
+	if v := filterVersions(r.rules, 1, 7, versionEndpoints("v1", "v2")); len(v) != 2 {
+		t.Fatalf("the closed router should not reload the rules, got %v", v)
+	}
+}
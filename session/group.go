@@ -9,7 +9,6 @@ package session
 
 import (
 	"errors"
-	"sync"
 )
 
 var (
@@ -24,90 +23,94 @@ const (
 
 type Kind int
 
+// Group 会话组
+// 连接会话与用户会话分别按ID分片存储，每个分片独立加锁，大量连接并发建立、断开及绑定时相互之间的锁竞争较小；
+// 遍历、组播及广播基于分片快照进行，推送消息时不持有分片锁
 type Group struct {
-	rw    sync.RWMutex       // 读写锁
-	conns map[int64]*Session // 连接会话（连接ID -> *Session）
-	users map[int64]*Session // 用户会话（用户ID -> *Session）
+	conns shards // 连接会话（连接ID -> *Session）
+	users shards // 用户会话（用户ID -> *Session）
 }
 
 func NewGroup() *Group {
 	return &Group{
-		conns: make(map[int64]*Session),
-		users: make(map[int64]*Session),
+		conns: newShards(defaultShardCount),
+		users: newShards(defaultShardCount),
 	}
 }
 
 // AddSession 添加会话
 func (g *Group) AddSession(sess *Session) {
-	g.rw.Lock()
-	defer g.rw.Unlock()
-
-	g.conns[sess.CID()] = sess
-	if uid := sess.UID(); uid > 0 {
-		g.users[uid] = sess
-	}
 	sess.addToGroups(g)
 }
 
 // RemSession 移除会话
 func (g *Group) RemSession(kind Kind, target int64) (*Session, error) {
-	g.rw.Lock()
-	defer g.rw.Unlock()
+	var (
+		e  entry
+		ok bool
+	)
 
 	switch kind {
 	case Conn:
-		sess, ok := g.conns[target]
-		if !ok {
-			return nil, ErrNotFoundSession
-		}
-
-		if uid := sess.UID(); uid > 0 {
-			delete(g.users, uid)
-		}
-		delete(g.conns, target)
-		sess.remFromGroups(g)
-		return sess, nil
+		e, ok = g.conns.delete(target)
 	case User:
-		sess, ok := g.users[target]
-		if !ok {
-			return nil, ErrNotFoundSession
+		if e, ok = g.users.delete(target); ok {
+			g.conns.compareAndDelete(e.cid, e.sess)
 		}
-
-		delete(g.users, target)
-		delete(g.conns, sess.CID())
-		sess.remFromGroups(g)
-		return sess, nil
 	default:
 		return nil, ErrInvalidSessionKind
 	}
+
+	if !ok {
+		return nil, ErrNotFoundSession
+	}
+
+	e.sess.remFromGroups(g)
+
+	return e.sess, nil
 }
 
 // GetSession 获取会话
 func (g *Group) GetSession(kind Kind, target int64) (*Session, error) {
-	g.rw.RLock()
-	defer g.rw.RUnlock()
+	sessions, err := g.sessions(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	e, ok := sessions.load(target)
+	if !ok {
+		return nil, ErrNotFoundSession
+	}
 
-	return g.getSession(kind, target)
+	return e.sess, nil
 }
 
-// 获取会话
-func (g *Group) getSession(kind Kind, target int64) (*Session, error) {
-	switch kind {
-	case Conn:
-		sess, ok := g.conns[target]
-		if !ok {
-			return nil, ErrNotFoundSession
-		}
-		return sess, nil
-	case User:
-		sess, ok := g.users[target]
-		if !ok {
-			return nil, ErrNotFoundSession
-		}
-		return sess, nil
-	default:
-		return nil, ErrInvalidSessionKind
+// Count 获取会话数量
+func (g *Group) Count(kind Kind) (int, error) {
+	sessions, err := g.sessions(kind)
+	if err != nil {
+		return 0, err
 	}
+
+	return sessions.count(), nil
+}
+
+// Range 遍历会话，回调函数返回false时终止遍历
+// 遍历基于分片快照进行，回调期间不持有分片锁，回调中可添加或移除会话；已移除的会话不再回调，但回调期间会话仍可能被并发移除
+func (g *Group) Range(kind Kind, fn func(sess *Session) bool) error {
+	sessions, err := g.sessions(kind)
+	if err != nil {
+		return err
+	}
+
+	sessions.rangeSnapshot(func(e entry) bool {
+		if !e.sess.is(e.cid) {
+			return true
+		}
+		return fn(e.sess)
+	})
+
+	return nil
 }
 
 // Send 发送消息（同步）
@@ -130,28 +133,19 @@ func (g *Group) Push(kind Kind, target int64, msg []byte, msgType ...int) error
 	return sess.Push(msg, msgType...)
 }
 
-// Multicast 推送组播消息（异步）
+// Multicast 推送组播消息（异步非阻塞），单个会话的写入队列已满时不会阻塞其他会话
 func (g *Group) Multicast(kind Kind, targets []int64, msg []byte, msgType ...int) (n int, err error) {
-	g.rw.RLock()
-	defer g.rw.RUnlock()
-
-	var sessions map[int64]*Session
-	switch kind {
-	case Conn:
-		sessions = g.conns
-	case User:
-		sessions = g.users
-	default:
-		err = ErrInvalidSessionKind
+	sessions, err := g.sessions(kind)
+	if err != nil {
 		return
 	}
 
 	for _, target := range targets {
-		session, ok := sessions[target]
+		e, ok := sessions.load(target)
 		if !ok {
 			continue
 		}
-		if session.Push(msg, msgType...) == nil {
+		if e.sess.tryPush(e.cid, msg, msgType...) == nil {
 			n++
 		}
 	}
@@ -159,73 +153,58 @@ func (g *Group) Multicast(kind Kind, targets []int64, msg []byte, msgType ...int
 	return
 }
 
-// Broadcast 推送广播消息（异步）
+// Broadcast 推送广播消息（异步非阻塞），单个会话的写入队列已满时不会阻塞其他会话
+// 广播基于分片快照进行，推送期间不阻塞会话的添加、移除及绑定；快照后已移除的会话不会收到消息
 func (g *Group) Broadcast(kind Kind, msg []byte, msgType ...int) (n int, err error) {
-	g.rw.RLock()
-	defer g.rw.RUnlock()
-
-	var sessions map[int64]*Session
-	switch kind {
-	case Conn:
-		sessions = g.conns
-	case User:
-		sessions = g.users
-	default:
-		err = ErrInvalidSessionKind
+	sessions, err := g.sessions(kind)
+	if err != nil {
 		return
 	}
 
-	for _, session := range sessions {
-		if session.Push(msg, msgType...) == nil {
+	sessions.rangeSnapshot(func(e entry) bool {
+		if e.sess.tryPush(e.cid, msg, msgType...) == nil {
 			n++
 		}
-	}
+		return true
+	})
 
 	return
 }
 
-// 添加会话
-func (g *Group) addSession(sess *Session) {
-	g.rw.Lock()
-	defer g.rw.Unlock()
-
-	g.conns[sess.CID()] = sess
-	if uid := sess.UID(); uid > 0 {
-		g.users[uid] = sess
+// 获取指定类型的会话集合
+func (g *Group) sessions(kind Kind) (shards, error) {
+	switch kind {
+	case Conn:
+		return g.conns, nil
+	case User:
+		return g.users, nil
+	default:
+		return nil, ErrInvalidSessionKind
 	}
 }
 
-// 移除会话
-func (g *Group) remSession(cid int64) error {
-	g.rw.Lock()
-	defer g.rw.Unlock()
-
-	sess, ok := g.conns[cid]
-	if !ok {
-		return ErrNotFoundSession
+// 添加会话
+func (g *Group) addSession(cid, uid int64, sess *Session) {
+	g.conns.store(cid, entry{cid: cid, sess: sess})
+	if uid > 0 {
+		g.users.store(uid, entry{cid: cid, sess: sess})
 	}
+}
 
-	delete(g.conns, cid)
-
-	if uid := sess.UID(); uid > 0 {
-		delete(g.users, uid)
+// 移除会话，连接或用户已添加为其他会话时不移除
+func (g *Group) remSession(cid, uid int64, sess *Session) {
+	g.conns.compareAndDelete(cid, sess)
+	if uid > 0 {
+		g.users.compareAndDelete(uid, sess)
 	}
-
-	return nil
 }
 
 // 添加用户会话
-func (g *Group) addUserSession(uid int64, sess *Session) {
-	g.rw.Lock()
-	defer g.rw.Unlock()
-
-	g.users[uid] = sess
+func (g *Group) addUserSession(uid, cid int64, sess *Session) {
+	g.users.store(uid, entry{cid: cid, sess: sess})
 }
 
-// 移除用户会话
-func (g *Group) remUserSession(uid int64) {
-	g.rw.Lock()
-	defer g.rw.Unlock()
-
-	delete(g.users, uid)
+// 移除用户会话，用户已绑定到其他会话时不移除
+func (g *Group) remUserSession(uid int64, sess *Session) {
+	g.users.compareAndDelete(uid, sess)
 }
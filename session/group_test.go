@@ -0,0 +1,164 @@
+package session_test
+
+import (
+	"github.com/dobyte/due/network"
+	"github.com/dobyte/due/session"
+	"net"
+	"sync/atomic"
+	"testing"
+)
+
+var cids int64
+
+type conn struct {
+	id     int64
+	uid    int64
+	pushes int64
+}
+
+func newConn() *conn {
+	return &conn{id: atomic.AddInt64(&cids, 1)}
+}
+
+func (c *conn) ID() int64 { return c.id }
+
+func (c *conn) UID() int64 { return atomic.LoadInt64(&c.uid) }
+
+func (c *conn) Bind(uid int64) { atomic.StoreInt64(&c.uid, uid) }
+
+func (c *conn) Unbind() { atomic.StoreInt64(&c.uid, 0) }
+
+func (c *conn) Send(msg []byte, msgType ...int) error { return c.TryPush(msg, msgType...) }
+
+func (c *conn) Push(msg []byte, msgType ...int) error { return c.TryPush(msg, msgType...) }
+
+func (c *conn) TryPush(msg []byte, msgType ...int) error {
+	atomic.AddInt64(&c.pushes, 1)
+	return nil
+}
+
+func (c *conn) Overflows() int64 { return 0 }
+
+func (c *conn) State() network.ConnState { return network.ConnOpened }
+
+func (c *conn) Close(isForce ...bool) error { return nil }
+
+func (c *conn) LocalIP() (string, error) { return "127.0.0.1", nil }
+
+func (c *conn) LocalAddr() (net.Addr, error) { return &net.TCPAddr{}, nil }
+
+func (c *conn) RemoteIP() (string, error) { return "127.0.0.1", nil }
+
+func (c *conn) RemoteAddr() (net.Addr, error) { return &net.TCPAddr{}, nil }
+
+func newSession() *session.Session {
+	s := session.NewSession()
+	s.Init(newConn())
+	return s
+}
+
+func TestGroup(t *testing.T) {
+	g := session.NewGroup()
+
+	s1, s2 := newSession(), newSession()
+	g.AddSession(s1)
+	g.AddSession(s2)
+	s1.Bind(1)
+	s2.Bind(1)
+
+	if n, _ := g.Count(session.Conn); n != 2 {
+		t.Fatalf("conn count: %d", n)
+	}
+
+	if sess, err := g.GetSession(session.User, 1); err != nil || sess != s2 {
+		t.Fatalf("user session: %v", err)
+	}
+
+	s1.Unbind(1)
+	if sess, err := g.GetSession(session.User, 1); err != nil || sess != s2 {
+		t.Fatalf("user session after unbind: %v", err)
+	}
+
+	if n, _ := g.Broadcast(session.Conn, []byte("hello")); n != 2 {
+		t.Fatalf("broadcast: %d", n)
+	}
+
+	if _, err := g.RemSession(session.Conn, s2.CID()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.GetSession(session.User, 1); err != session.ErrNotFoundSession {
+		t.Fatalf("user session after remove: %v", err)
+	}
+
+	s1.Reset()
+	s1.Init(newConn())
+	if n, _ := g.Broadcast(session.Conn, []byte("hello")); n != 0 {
+		t.Fatalf("broadcast to recycled session: %d", n)
+	}
+}
+
+func Benchmark_Group_AddSession(b *testing.B) {
+	g := session.NewGroup()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s := newSession()
+			g.AddSession(s)
+			_, _ = g.RemSession(session.Conn, s.CID())
+		}
+	})
+}
+
+func Benchmark_Group_Bind(b *testing.B) {
+	g := session.NewGroup()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		s := newSession()
+		g.AddSession(s)
+
+		for pb.Next() {
+			uid := s.CID()
+			s.Bind(uid)
+			s.Unbind(uid)
+		}
+	})
+}
+
+func Benchmark_Group_Broadcast(b *testing.B) {
+	g := session.NewGroup()
+	for i := 0; i < 10000; i++ {
+		s := newSession()
+		g.AddSession(s)
+		s.Bind(s.CID())
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				s := newSession()
+				g.AddSession(s)
+				s.Bind(s.CID())
+				_, _ = g.RemSession(session.Conn, s.CID())
+			}
+		}
+	}()
+
+	msg := []byte("hello")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = g.Broadcast(session.User, msg)
+		}
+	})
+}
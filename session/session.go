@@ -18,6 +18,8 @@ type Session struct {
 	rw     sync.RWMutex        // 读写锁
 	conn   network.Conn        // 连接
 	groups map[*Group]struct{} // 所在组
+	claims map[string]string   // 鉴权声明
+	attrs  map[string]string   // 会话属性
 }
 
 func NewSession() *Session {
@@ -40,6 +42,8 @@ func (s *Session) Reset() {
 
 	s.conn = nil
 	s.groups = nil
+	s.claims = nil
+	s.attrs = nil
 }
 
 // CID 获取连接ID
@@ -64,8 +68,9 @@ func (s *Session) Bind(uid int64) {
 	defer s.rw.Unlock()
 
 	s.conn.Bind(uid)
+	cid := s.conn.ID()
 	for group := range s.groups {
-		group.addUserSession(uid, s)
+		group.addUserSession(uid, cid, s)
 	}
 }
 
@@ -75,11 +80,75 @@ func (s *Session) Unbind(uid int64) {
 	defer s.rw.Unlock()
 
 	s.conn.Unbind()
+	s.claims = nil
 	for group := range s.groups {
-		group.remUserSession(uid)
+		group.remUserSession(uid, s)
 	}
 }
 
+// SetClaims 设置鉴权声明
+func (s *Session) SetClaims(claims map[string]string) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	s.claims = claims
+}
+
+// Claims 获取鉴权声明
+func (s *Session) Claims() map[string]string {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+
+	claims := make(map[string]string, len(s.claims))
+	for k, v := range s.claims {
+		claims[k] = v
+	}
+
+	return claims
+}
+
+// Set 设置会话属性
+func (s *Session) Set(key, value string) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	if s.attrs == nil {
+		s.attrs = make(map[string]string)
+	}
+	s.attrs[key] = value
+}
+
+// Get 获取会话属性
+func (s *Session) Get(key string) (string, bool) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+
+	value, ok := s.attrs[key]
+
+	return value, ok
+}
+
+// Delete 删除会话属性
+func (s *Session) Delete(key string) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	delete(s.attrs, key)
+}
+
+// Attrs 获取全部会话属性
+func (s *Session) Attrs() map[string]string {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+
+	attrs := make(map[string]string, len(s.attrs))
+	for k, v := range s.attrs {
+		attrs[k] = v
+	}
+
+	return attrs
+}
+
 // Close 关闭会话
 func (s *Session) Close(isForce ...bool) error {
 	s.rw.Lock()
@@ -136,16 +205,58 @@ func (s *Session) Push(msg []byte, msgType ...int) error {
 	return s.conn.Push(msg, msgType...)
 }
 
-// AddToGroups 添加到会话组
-func (s *Session) AddToGroups(groups ...*Group) {
-	s.rw.Lock()
-	defer s.rw.Unlock()
+// TryPush 发送消息（异步非阻塞），写入队列已满时不等待；连接未实现network.OverflowConn时等同于Push
+func (s *Session) TryPush(msg []byte, msgType ...int) error {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
 
-	for i := range groups {
-		group := groups[i]
-		group.addSession(s)
-		s.groups[group] = struct{}{}
+	return tryPush(s.conn, msg, msgType...)
+}
+
+// 会话仍为指定连接时发送消息（异步非阻塞），会话已重置或被其他连接复用时返回ErrNotFoundSession
+func (s *Session) tryPush(cid int64, msg []byte, msgType ...int) error {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+
+	if s.conn == nil || s.conn.ID() != cid {
+		return ErrNotFoundSession
 	}
+
+	return tryPush(s.conn, msg, msgType...)
+}
+
+// 会话是否仍为指定连接
+func (s *Session) is(cid int64) bool {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+
+	return s.conn != nil && s.conn.ID() == cid
+}
+
+// Overflows 获取写入队列溢出次数，连接未实现network.OverflowConn时为0
+func (s *Session) Overflows() int64 {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+
+	if oc, ok := s.conn.(network.OverflowConn); ok {
+		return oc.Overflows()
+	}
+
+	return 0
+}
+
+// 非阻塞发送消息，连接未实现network.OverflowConn时使用Push
+func tryPush(conn network.Conn, msg []byte, msgType ...int) error {
+	if oc, ok := conn.(network.OverflowConn); ok {
+		return oc.TryPush(msg, msgType...)
+	}
+
+	return conn.Push(msg, msgType...)
+}
+
+// AddToGroups 添加到会话组
+func (s *Session) AddToGroups(groups ...*Group) {
+	s.addToGroups(groups...)
 }
 
 // 添加到会话组
@@ -153,20 +264,16 @@ func (s *Session) addToGroups(groups ...*Group) {
 	s.rw.Lock()
 	defer s.rw.Unlock()
 
-	for i := range groups {
-		s.groups[groups[i]] = struct{}{}
+	cid, uid := s.conn.ID(), s.conn.UID()
+	for _, group := range groups {
+		group.addSession(cid, uid, s)
+		s.groups[group] = struct{}{}
 	}
 }
 
 // RemFromGroups 从会话组移除
 func (s *Session) RemFromGroups(groups ...*Group) {
-	s.rw.Lock()
-	defer s.rw.Unlock()
-
-	for _, group := range groups {
-		_ = group.remSession(s.CID())
-		delete(s.groups, group)
-	}
+	s.remFromGroups(groups...)
 }
 
 // 从会话组移除
@@ -174,7 +281,13 @@ func (s *Session) remFromGroups(groups ...*Group) {
 	s.rw.Lock()
 	defer s.rw.Unlock()
 
+	if s.conn == nil {
+		return
+	}
+
+	cid, uid := s.conn.ID(), s.conn.UID()
 	for _, group := range groups {
+		group.remSession(cid, uid, s)
 		delete(s.groups, group)
 	}
 }
@@ -0,0 +1,50 @@
+package session_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSession_Attrs(t *testing.T) {
+	s := newSession()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("key-%d", i)
+			for j := 0; j < 100; j++ {
+				s.Set(key, fmt.Sprintf("%d", j))
+				s.Get(key)
+				// 快照为副本，修改快照不影响会话属性
+				attrs := s.Attrs()
+				attrs[key] = "snapshot"
+				if j%2 == 0 {
+					s.Delete(key)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	attrs := s.Attrs()
+	if len(attrs) != 8 {
+		t.Fatalf("expected 8 attrs, got %v", attrs)
+	}
+
+	for key, value := range attrs {
+		if value != "99" {
+			t.Fatalf("unexpected attr %s: %s", key, value)
+		}
+	}
+
+	s.Reset()
+	s.Init(newConn())
+
+	if _, ok := s.Get("key-0"); ok || len(s.Attrs()) != 0 {
+		t.Fatal("the attrs should be cleared after the session is reset")
+	}
+}
@@ -0,0 +1,126 @@
+package session
+
+import "sync"
+
+const defaultShardCount = 32 // 默认分片数，须为2的幂
+
+// 遍历快照的缓冲区
+var snapshots = sync.Pool{New: func() interface{} { return new([]entry) }}
+
+// 会话条目，记录加入时的连接ID，用于识别已被移除并复用的会话
+type entry struct {
+	cid  int64    // 连接ID
+	sess *Session // 会话
+}
+
+// 会话分片
+type shard struct {
+	rw      sync.RWMutex    // 读写锁
+	entries map[int64]entry // 会话条目
+}
+
+// 分片的会话集合，按ID分散到各个分片，每个分片独立加锁
+type shards []*shard
+
+func newShards(n int) shards {
+	ss := make(shards, n)
+	for i := range ss {
+		ss[i] = &shard{entries: make(map[int64]entry)}
+	}
+
+	return ss
+}
+
+// 获取ID所在的分片
+func (ss shards) shard(id int64) *shard {
+	return ss[uint64(id)&uint64(len(ss)-1)]
+}
+
+// 加载会话条目
+func (ss shards) load(id int64) (entry, bool) {
+	s := ss.shard(id)
+	s.rw.RLock()
+	e, ok := s.entries[id]
+	s.rw.RUnlock()
+
+	return e, ok
+}
+
+// 存储会话条目
+func (ss shards) store(id int64, e entry) {
+	s := ss.shard(id)
+	s.rw.Lock()
+	s.entries[id] = e
+	s.rw.Unlock()
+}
+
+// 删除会话条目并返回被删除的条目
+func (ss shards) delete(id int64) (entry, bool) {
+	s := ss.shard(id)
+	s.rw.Lock()
+	e, ok := s.entries[id]
+	if ok {
+		delete(s.entries, id)
+	}
+	s.rw.Unlock()
+
+	return e, ok
+}
+
+// 会话条目为指定会话时删除
+func (ss shards) compareAndDelete(id int64, sess *Session) bool {
+	s := ss.shard(id)
+	s.rw.Lock()
+	ok := s.entries[id].sess == sess
+	if ok {
+		delete(s.entries, id)
+	}
+	s.rw.Unlock()
+
+	return ok
+}
+
+// 会话数量
+func (ss shards) count() (n int) {
+	for _, s := range ss {
+		s.rw.RLock()
+		n += len(s.entries)
+		s.rw.RUnlock()
+	}
+
+	return
+}
+
+// 遍历会话条目
+// 逐个分片复制条目快照后释放分片锁再回调，回调期间不阻塞会话的添加、移除及绑定；回调函数返回false时终止遍历
+func (ss shards) rangeSnapshot(fn func(e entry) bool) {
+	buf := snapshots.Get().(*[]entry)
+	list := (*buf)[:0]
+
+	defer func() {
+		for i := range list {
+			list[i] = entry{}
+		}
+		*buf = list[:0]
+		snapshots.Put(buf)
+	}()
+
+	for _, s := range ss {
+		for i := range list {
+			list[i] = entry{}
+		}
+		list = list[:0]
+
+		s.rw.RLock()
+		for _, e := range s.entries {
+			list = append(list, e)
+		}
+		s.rw.RUnlock()
+
+		for _, e := range list {
+			if !fn(e) {
+				return
+			}
+		}
+	}
+}
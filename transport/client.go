@@ -5,11 +5,14 @@ import (
 	"github.com/dobyte/due/session"
 )
 
+// NodeClient 节点客户端，调用超时的错误可通过errors.Is(err, context.DeadlineExceeded)判断
 type NodeClient interface {
 	// Trigger 触发事件
 	Trigger(ctx context.Context, args *TriggerArgs) (miss bool, err error)
 	// Deliver 投递消息
 	Deliver(ctx context.Context, args *DeliverArgs) (miss bool, err error)
+	// Invoke 调用路由并等待响应
+	Invoke(ctx context.Context, args *DeliverArgs) (reply *Message, miss bool, err error)
 }
 
 type GateClient interface {
@@ -19,14 +22,36 @@ type GateClient interface {
 	Unbind(ctx context.Context, uid int64) (miss bool, err error)
 	// GetIP 获取客户端IP
 	GetIP(ctx context.Context, kind session.Kind, target int64) (ip string, miss bool, err error)
+	// GetAttrs 获取会话属性
+	GetAttrs(ctx context.Context, kind session.Kind, target int64) (attrs map[string]string, miss bool, err error)
+	// SetAttrs 设置会话属性
+	SetAttrs(ctx context.Context, kind session.Kind, target int64, attrs map[string]string) (miss bool, err error)
+	// DelAttrs 删除会话属性
+	DelAttrs(ctx context.Context, kind session.Kind, target int64, keys []string) (miss bool, err error)
+	// Resume 恢复断线的用户会话，会话移交给指定网关
+	Resume(ctx context.Context, uid int64, nonce string, gid string) (reply *ResumeReply, miss bool, err error)
 	// Disconnect 断开连接
 	Disconnect(ctx context.Context, kind session.Kind, target int64, isForce bool) (miss bool, err error)
+	// Kick 踢下线，reason为踢下线原因的错误码
+	Kick(ctx context.Context, kind session.Kind, target int64, reason int32) (miss bool, err error)
 	// Push 推送消息
 	Push(ctx context.Context, kind session.Kind, target int64, message *Message) (miss bool, err error)
 	// Multicast 推送组播消息
 	Multicast(ctx context.Context, kind session.Kind, targets []int64, message *Message) (total int64, err error)
 	// Broadcast 推送广播消息
 	Broadcast(ctx context.Context, kind session.Kind, message *Message) (total int64, err error)
+	// JoinChannel 加入频道
+	JoinChannel(ctx context.Context, channel string, uids []int64) (miss bool, err error)
+	// LeaveChannel 离开频道，用户为空时解散频道
+	LeaveChannel(ctx context.Context, channel string, uids []int64) error
+	// PublishChannel 发布频道消息
+	PublishChannel(ctx context.Context, channel string, message *Message) (total int64, err error)
+}
+
+type ResumeReply struct {
+	Messages []*Message        // 断线期间缓存的消息
+	Attrs    map[string]string // 会话属性
+	Claims   map[string]string // 鉴权声明
 }
 
 type Message struct {
@@ -79,6 +79,48 @@ func (c *client) GetIP(ctx context.Context, kind session.Kind, target int64) (ip
 	return
 }
 
+// GetAttrs 获取会话属性
+func (c *client) GetAttrs(ctx context.Context, kind session.Kind, target int64) (attrs map[string]string, miss bool, err error) {
+	reply, err := c.client.GetAttrs(ctx, &pb.GetAttrsRequest{
+		Kind:   int32(kind),
+		Target: target,
+	})
+	if err != nil {
+		miss = status.Code(err) == code.NotFoundSession
+		return
+	}
+
+	attrs = reply.Attrs
+
+	return
+}
+
+// SetAttrs 设置会话属性
+func (c *client) SetAttrs(ctx context.Context, kind session.Kind, target int64, attrs map[string]string) (miss bool, err error) {
+	_, err = c.client.SetAttrs(ctx, &pb.SetAttrsRequest{
+		Kind:   int32(kind),
+		Target: target,
+		Attrs:  attrs,
+	})
+
+	miss = status.Code(err) == code.NotFoundSession
+
+	return
+}
+
+// DelAttrs 删除会话属性
+func (c *client) DelAttrs(ctx context.Context, kind session.Kind, target int64, keys []string) (miss bool, err error) {
+	_, err = c.client.DelAttrs(ctx, &pb.DelAttrsRequest{
+		Kind:   int32(kind),
+		Target: target,
+		Keys:   keys,
+	})
+
+	miss = status.Code(err) == code.NotFoundSession
+
+	return
+}
+
 // Push 推送消息
 func (c *client) Push(ctx context.Context, kind session.Kind, target int64, message *transport.Message) (miss bool, err error) {
 	_, err = c.client.Push(ctx, &pb.PushRequest{
@@ -131,6 +173,35 @@ func (c *client) Broadcast(ctx context.Context, kind session.Kind, message *tran
 	return reply.Total, nil
 }
 
+// Resume 恢复断线的用户会话
+func (c *client) Resume(ctx context.Context, uid int64, nonce string, gid string) (reply *transport.ResumeReply, miss bool, err error) {
+	res, err := c.client.Resume(ctx, &pb.ResumeRequest{
+		UID:   uid,
+		Nonce: nonce,
+		GID:   gid,
+	}, grpc.UseCompressor(gzip.Name))
+	if err != nil {
+		miss = status.Code(err) == code.NotFoundSession
+		return
+	}
+
+	reply = &transport.ResumeReply{
+		Messages: make([]*transport.Message, 0, len(res.Messages)),
+		Attrs:    res.Attrs,
+		Claims:   res.Claims,
+	}
+
+	for _, message := range res.Messages {
+		reply.Messages = append(reply.Messages, &transport.Message{
+			Seq:    message.Seq,
+			Route:  message.Route,
+			Buffer: message.Buffer,
+		})
+	}
+
+	return
+}
+
 // Disconnect 断开连接
 func (c *client) Disconnect(ctx context.Context, kind session.Kind, target int64, isForce bool) (miss bool, err error) {
 	_, err = c.client.Disconnect(ctx, &pb.DisconnectRequest{
@@ -143,3 +214,55 @@ func (c *client) Disconnect(ctx context.Context, kind session.Kind, target int64
 
 	return
 }
+
+// Kick 踢下线
+func (c *client) Kick(ctx context.Context, kind session.Kind, target int64, reason int32) (miss bool, err error) {
+	_, err = c.client.Kick(ctx, &pb.KickRequest{
+		Kind:   int32(kind),
+		Target: target,
+		Reason: reason,
+	})
+
+	miss = status.Code(err) == code.NotFoundSession
+
+	return
+}
+
+// JoinChannel 加入频道
+func (c *client) JoinChannel(ctx context.Context, channel string, uids []int64) (miss bool, err error) {
+	_, err = c.client.JoinChannel(ctx, &pb.JoinChannelRequest{
+		Channel: channel,
+		UIDs:    uids,
+	})
+
+	miss = status.Code(err) == code.NotFoundSession
+
+	return
+}
+
+// LeaveChannel 离开频道
+func (c *client) LeaveChannel(ctx context.Context, channel string, uids []int64) error {
+	_, err := c.client.LeaveChannel(ctx, &pb.LeaveChannelRequest{
+		Channel: channel,
+		UIDs:    uids,
+	})
+
+	return err
+}
+
+// PublishChannel 发布频道消息
+func (c *client) PublishChannel(ctx context.Context, channel string, message *transport.Message) (int64, error) {
+	reply, err := c.client.PublishChannel(ctx, &pb.PublishChannelRequest{
+		Channel: channel,
+		Message: &pb.Message{
+			Seq:    message.Seq,
+			Route:  message.Route,
+			Buffer: message.Buffer,
+		},
+	}, grpc.UseCompressor(gzip.Name))
+	if err != nil {
+		return 0, err
+	}
+
+	return reply.Total, nil
+}
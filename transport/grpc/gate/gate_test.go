@@ -0,0 +1,114 @@
+package gate_test
+
+import (
+	"context"
+	"github.com/dobyte/due/session"
+	"github.com/dobyte/due/transport"
+	"github.com/dobyte/due/transport/grpc/gate"
+	"github.com/dobyte/due/transport/grpc/internal/client"
+	"github.com/dobyte/due/transport/grpc/internal/server"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 仅持有连接1会话属性的网关提供者
+type attrsProvider struct {
+	transport.GateProvider
+	mu    sync.Mutex
+	attrs map[string]string
+}
+
+func (p *attrsProvider) GetAttrs(kind session.Kind, target int64) (map[string]string, error) {
+	if kind != session.Conn || target != 1 {
+		return nil, session.ErrNotFoundSession
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	attrs := make(map[string]string, len(p.attrs))
+	for k, v := range p.attrs {
+		attrs[k] = v
+	}
+
+	return attrs, nil
+}
+
+func (p *attrsProvider) SetAttrs(kind session.Kind, target int64, attrs map[string]string) error {
+	if kind != session.Conn || target != 1 {
+		return session.ErrNotFoundSession
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for k, v := range attrs {
+		p.attrs[k] = v
+	}
+
+	return nil
+}
+
+func (p *attrsProvider) DelAttrs(kind session.Kind, target int64, keys []string) error {
+	if kind != session.Conn || target != 1 {
+		return session.ErrNotFoundSession
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range keys {
+		delete(p.attrs, k)
+	}
+
+	return nil
+}
+
+func TestClient_Attrs(t *testing.T) {
+	s, err := gate.NewServer(&attrsProvider{attrs: make(map[string]string)}, &server.Options{
+		Addr: "127.0.0.1:3562",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	c, err := gate.NewClient(s.Endpoint(), &client.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if miss, err := c.SetAttrs(ctx, session.Conn, 1, map[string]string{"level": "1", "guild": "a"}); err != nil || miss {
+		t.Fatalf("set attrs failed, miss: %v, err: %v", miss, err)
+	}
+
+	if miss, err := c.DelAttrs(ctx, session.Conn, 1, []string{"guild"}); err != nil || miss {
+		t.Fatalf("delete attrs failed, miss: %v, err: %v", miss, err)
+	}
+
+	attrs, miss, err := c.GetAttrs(ctx, session.Conn, 1)
+	if err != nil || miss {
+		t.Fatalf("get attrs failed, miss: %v, err: %v", miss, err)
+	}
+
+	if !reflect.DeepEqual(attrs, map[string]string{"level": "1"}) {
+		t.Fatalf("unexpected attrs: %v", attrs)
+	}
+
+	if miss, _ := c.SetAttrs(ctx, session.Conn, 2, map[string]string{"level": "1"}); !miss {
+		t.Fatal("setting the attrs of a missing session should miss")
+	}
+
+	if miss, _ := c.DelAttrs(ctx, session.Conn, 2, []string{"level"}); !miss {
+		t.Fatal("deleting the attrs of a missing session should miss")
+	}
+}
@@ -79,6 +79,57 @@ func (e *endpoint) GetIP(_ context.Context, req *pb.GetIPRequest) (*pb.GetIPRepl
 	return &pb.GetIPReply{IP: ip}, nil
 }
 
+// GetAttrs 获取会话属性
+func (e *endpoint) GetAttrs(_ context.Context, req *pb.GetAttrsRequest) (*pb.GetAttrsReply, error) {
+	attrs, err := e.provider.GetAttrs(session.Kind(req.Kind), req.Target)
+	if err != nil {
+		switch err {
+		case session.ErrNotFoundSession:
+			return nil, status.New(code.NotFoundSession, err.Error()).Err()
+		case session.ErrInvalidSessionKind:
+			return nil, status.New(codes.InvalidArgument, err.Error()).Err()
+		default:
+			return nil, status.New(codes.Internal, err.Error()).Err()
+		}
+	}
+
+	return &pb.GetAttrsReply{Attrs: attrs}, nil
+}
+
+// SetAttrs 设置会话属性
+func (e *endpoint) SetAttrs(_ context.Context, req *pb.SetAttrsRequest) (*pb.SetAttrsReply, error) {
+	err := e.provider.SetAttrs(session.Kind(req.Kind), req.Target, req.Attrs)
+	if err != nil {
+		switch err {
+		case session.ErrNotFoundSession:
+			return nil, status.New(code.NotFoundSession, err.Error()).Err()
+		case session.ErrInvalidSessionKind:
+			return nil, status.New(codes.InvalidArgument, err.Error()).Err()
+		default:
+			return nil, status.New(codes.Internal, err.Error()).Err()
+		}
+	}
+
+	return &pb.SetAttrsReply{}, nil
+}
+
+// DelAttrs 删除会话属性
+func (e *endpoint) DelAttrs(_ context.Context, req *pb.DelAttrsRequest) (*pb.DelAttrsReply, error) {
+	err := e.provider.DelAttrs(session.Kind(req.Kind), req.Target, req.Keys)
+	if err != nil {
+		switch err {
+		case session.ErrNotFoundSession:
+			return nil, status.New(code.NotFoundSession, err.Error()).Err()
+		case session.ErrInvalidSessionKind:
+			return nil, status.New(codes.InvalidArgument, err.Error()).Err()
+		default:
+			return nil, status.New(codes.Internal, err.Error()).Err()
+		}
+	}
+
+	return &pb.DelAttrsReply{}, nil
+}
+
 // Push 推送消息给连接
 func (e *endpoint) Push(_ context.Context, req *pb.PushRequest) (*pb.PushReply, error) {
 	err := e.provider.Push(session.Kind(req.Kind), req.Target, &packet.Message{
@@ -138,6 +189,30 @@ func (e *endpoint) Broadcast(_ context.Context, req *pb.BroadcastRequest) (*pb.B
 	return &pb.BroadcastReply{Total: total}, nil
 }
 
+// Resume 恢复断线的用户会话
+func (e *endpoint) Resume(ctx context.Context, req *pb.ResumeRequest) (*pb.ResumeReply, error) {
+	reply, err := e.provider.Resume(ctx, req.UID, req.Nonce, req.GID)
+	if err != nil {
+		switch err {
+		case session.ErrNotFoundSession:
+			return nil, status.New(code.NotFoundSession, err.Error()).Err()
+		default:
+			return nil, status.New(codes.Internal, err.Error()).Err()
+		}
+	}
+
+	messages := make([]*pb.Message, 0, len(reply.Messages))
+	for _, message := range reply.Messages {
+		messages = append(messages, &pb.Message{
+			Seq:    message.Seq,
+			Route:  message.Route,
+			Buffer: message.Buffer,
+		})
+	}
+
+	return &pb.ResumeReply{Messages: messages, Attrs: reply.Attrs, Claims: reply.Claims}, nil
+}
+
 // Disconnect 断开连接
 func (e *endpoint) Disconnect(_ context.Context, req *pb.DisconnectRequest) (*pb.DisconnectReply, error) {
 	err := e.provider.Disconnect(session.Kind(req.Kind), req.Target, req.IsForce)
@@ -154,3 +229,59 @@ func (e *endpoint) Disconnect(_ context.Context, req *pb.DisconnectRequest) (*pb
 
 	return &pb.DisconnectReply{}, nil
 }
+
+// Kick 踢下线
+func (e *endpoint) Kick(_ context.Context, req *pb.KickRequest) (*pb.KickReply, error) {
+	err := e.provider.Kick(session.Kind(req.Kind), req.Target, req.Reason)
+	if err != nil {
+		switch err {
+		case session.ErrNotFoundSession:
+			return nil, status.New(code.NotFoundSession, err.Error()).Err()
+		case session.ErrInvalidSessionKind:
+			return nil, status.New(codes.InvalidArgument, err.Error()).Err()
+		default:
+			return nil, status.New(codes.Internal, err.Error()).Err()
+		}
+	}
+
+	return &pb.KickReply{}, nil
+}
+
+// JoinChannel 加入频道
+func (e *endpoint) JoinChannel(_ context.Context, req *pb.JoinChannelRequest) (*pb.JoinChannelReply, error) {
+	err := e.provider.JoinChannel(req.Channel, req.UIDs)
+	if err != nil {
+		switch err {
+		case session.ErrNotFoundSession:
+			return nil, status.New(code.NotFoundSession, err.Error()).Err()
+		default:
+			return nil, status.New(codes.Internal, err.Error()).Err()
+		}
+	}
+
+	return &pb.JoinChannelReply{}, nil
+}
+
+// LeaveChannel 离开频道
+func (e *endpoint) LeaveChannel(_ context.Context, req *pb.LeaveChannelRequest) (*pb.LeaveChannelReply, error) {
+	err := e.provider.LeaveChannel(req.Channel, req.UIDs)
+	if err != nil {
+		return nil, status.New(codes.Internal, err.Error()).Err()
+	}
+
+	return &pb.LeaveChannelReply{}, nil
+}
+
+// PublishChannel 发布频道消息
+func (e *endpoint) PublishChannel(_ context.Context, req *pb.PublishChannelRequest) (*pb.PublishChannelReply, error) {
+	total, err := e.provider.PublishChannel(req.Channel, &packet.Message{
+		Seq:    req.Message.Seq,
+		Route:  req.Message.Route,
+		Buffer: req.Message.Buffer,
+	})
+	if err != nil {
+		return nil, status.New(codes.Internal, err.Error()).Err()
+	}
+
+	return &pb.PublishChannelReply{Total: total}, nil
+}
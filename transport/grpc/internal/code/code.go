@@ -1,7 +1,33 @@
 package code
 
-import "google.golang.org/grpc/codes"
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
 
 const (
 	NotFoundSession codes.Code = 100 + iota // NotFoundSession
 )
+
+// 调用超时的错误
+type timeoutError struct {
+	error
+}
+
+func (e *timeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+func (e *timeoutError) Unwrap() error {
+	return e.error
+}
+
+// Error 转换调用错误，调用超时的错误可通过errors.Is(err, context.DeadlineExceeded)判断
+func Error(err error) error {
+	if status.Code(err) == codes.DeadlineExceeded {
+		return &timeoutError{err}
+	}
+
+	return err
+}
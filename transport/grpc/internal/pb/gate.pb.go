@@ -300,18 +300,17 @@ func (x *GetIPReply) GetIP() string {
 	return ""
 }
 
-type DisconnectRequest struct {
+type GetAttrsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Kind    int32 `protobuf:"varint,1,opt,name=Kind,proto3" json:"Kind,omitempty"`       // 推送类型 1：CID 2：UID
-	Target  int64 `protobuf:"varint,2,opt,name=Target,proto3" json:"Target,omitempty"`   // 推送目标
-	IsForce bool  `protobuf:"varint,3,opt,name=IsForce,proto3" json:"IsForce,omitempty"` // 是否强制断开连接
+	Kind   int32 `protobuf:"varint,1,opt,name=Kind,proto3" json:"Kind,omitempty"`     // 会话类型 1：CID 2：UID
+	Target int64 `protobuf:"varint,2,opt,name=Target,proto3" json:"Target,omitempty"` // 会话目标
 }
 
-func (x *DisconnectRequest) Reset() {
-	*x = DisconnectRequest{}
+func (x *GetAttrsRequest) Reset() {
+	*x = GetAttrsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_gate_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -319,13 +318,13 @@ func (x *DisconnectRequest) Reset() {
 	}
 }
 
-func (x *DisconnectRequest) String() string {
+func (x *GetAttrsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DisconnectRequest) ProtoMessage() {}
+func (*GetAttrsRequest) ProtoMessage() {}
 
-func (x *DisconnectRequest) ProtoReflect() protoreflect.Message {
+func (x *GetAttrsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_gate_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -337,40 +336,35 @@ func (x *DisconnectRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DisconnectRequest.ProtoReflect.Descriptor instead.
-func (*DisconnectRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetAttrsRequest.ProtoReflect.Descriptor instead.
+func (*GetAttrsRequest) Descriptor() ([]byte, []int) {
 	return file_gate_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *DisconnectRequest) GetKind() int32 {
+func (x *GetAttrsRequest) GetKind() int32 {
 	if x != nil {
 		return x.Kind
 	}
 	return 0
 }
 
-func (x *DisconnectRequest) GetTarget() int64 {
+func (x *GetAttrsRequest) GetTarget() int64 {
 	if x != nil {
 		return x.Target
 	}
 	return 0
 }
 
-func (x *DisconnectRequest) GetIsForce() bool {
-	if x != nil {
-		return x.IsForce
-	}
-	return false
-}
-
-type DisconnectReply struct {
+type GetAttrsReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Attrs map[string]string `protobuf:"bytes,1,rep,name=Attrs,proto3" json:"Attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // 会话属性
 }
 
-func (x *DisconnectReply) Reset() {
-	*x = DisconnectReply{}
+func (x *GetAttrsReply) Reset() {
+	*x = GetAttrsReply{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_gate_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -378,13 +372,13 @@ func (x *DisconnectReply) Reset() {
 	}
 }
 
-func (x *DisconnectReply) String() string {
+func (x *GetAttrsReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DisconnectReply) ProtoMessage() {}
+func (*GetAttrsReply) ProtoMessage() {}
 
-func (x *DisconnectReply) ProtoReflect() protoreflect.Message {
+func (x *GetAttrsReply) ProtoReflect() protoreflect.Message {
 	mi := &file_gate_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -396,23 +390,30 @@ func (x *DisconnectReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DisconnectReply.ProtoReflect.Descriptor instead.
-func (*DisconnectReply) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetAttrsReply.ProtoReflect.Descriptor instead.
+func (*GetAttrsReply) Descriptor() ([]byte, []int) {
 	return file_gate_proto_rawDescGZIP(), []int{7}
 }
 
-type PushRequest struct {
+func (x *GetAttrsReply) GetAttrs() map[string]string {
+	if x != nil {
+		return x.Attrs
+	}
+	return nil
+}
+
+type SetAttrsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Kind    int32    `protobuf:"varint,1,opt,name=Kind,proto3" json:"Kind,omitempty"`      // 推送类型 1：CID 2：UID
-	Target  int64    `protobuf:"varint,2,opt,name=Target,proto3" json:"Target,omitempty"`  // 推送目标
-	Message *Message `protobuf:"bytes,3,opt,name=Message,proto3" json:"Message,omitempty"` // 消息
+	Kind   int32             `protobuf:"varint,1,opt,name=Kind,proto3" json:"Kind,omitempty"`                                                                                          // 会话类型 1：CID 2：UID
+	Target int64             `protobuf:"varint,2,opt,name=Target,proto3" json:"Target,omitempty"`                                                                                      // 会话目标
+	Attrs  map[string]string `protobuf:"bytes,3,rep,name=Attrs,proto3" json:"Attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // 会话属性
 }
 
-func (x *PushRequest) Reset() {
-	*x = PushRequest{}
+func (x *SetAttrsRequest) Reset() {
+	*x = SetAttrsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_gate_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -420,13 +421,13 @@ func (x *PushRequest) Reset() {
 	}
 }
 
-func (x *PushRequest) String() string {
+func (x *SetAttrsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PushRequest) ProtoMessage() {}
+func (*SetAttrsRequest) ProtoMessage() {}
 
-func (x *PushRequest) ProtoReflect() protoreflect.Message {
+func (x *SetAttrsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_gate_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -438,40 +439,40 @@ func (x *PushRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PushRequest.ProtoReflect.Descriptor instead.
-func (*PushRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetAttrsRequest.ProtoReflect.Descriptor instead.
+func (*SetAttrsRequest) Descriptor() ([]byte, []int) {
 	return file_gate_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *PushRequest) GetKind() int32 {
+func (x *SetAttrsRequest) GetKind() int32 {
 	if x != nil {
 		return x.Kind
 	}
 	return 0
 }
 
-func (x *PushRequest) GetTarget() int64 {
+func (x *SetAttrsRequest) GetTarget() int64 {
 	if x != nil {
 		return x.Target
 	}
 	return 0
 }
 
-func (x *PushRequest) GetMessage() *Message {
+func (x *SetAttrsRequest) GetAttrs() map[string]string {
 	if x != nil {
-		return x.Message
+		return x.Attrs
 	}
 	return nil
 }
 
-type PushReply struct {
+type SetAttrsReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *PushReply) Reset() {
-	*x = PushReply{}
+func (x *SetAttrsReply) Reset() {
+	*x = SetAttrsReply{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_gate_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -479,13 +480,13 @@ func (x *PushReply) Reset() {
 	}
 }
 
-func (x *PushReply) String() string {
+func (x *SetAttrsReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PushReply) ProtoMessage() {}
+func (*SetAttrsReply) ProtoMessage() {}
 
-func (x *PushReply) ProtoReflect() protoreflect.Message {
+func (x *SetAttrsReply) ProtoReflect() protoreflect.Message {
 	mi := &file_gate_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -497,38 +498,872 @@ func (x *PushReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PushReply.ProtoReflect.Descriptor instead.
-func (*PushReply) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetAttrsReply.ProtoReflect.Descriptor instead.
+func (*SetAttrsReply) Descriptor() ([]byte, []int) {
 	return file_gate_proto_rawDescGZIP(), []int{9}
 }
 
-type MulticastRequest struct {
+type DelAttrsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind   int32    `protobuf:"varint,1,opt,name=Kind,proto3" json:"Kind,omitempty"`     // 会话类型 1：CID 2：UID
+	Target int64    `protobuf:"varint,2,opt,name=Target,proto3" json:"Target,omitempty"` // 会话目标
+	Keys   []string `protobuf:"bytes,3,rep,name=Keys,proto3" json:"Keys,omitempty"`      // 属性键
+}
+
+func (x *DelAttrsRequest) Reset() {
+	*x = DelAttrsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelAttrsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelAttrsRequest) ProtoMessage() {}
+
+func (x *DelAttrsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelAttrsRequest.ProtoReflect.Descriptor instead.
+func (*DelAttrsRequest) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DelAttrsRequest) GetKind() int32 {
+	if x != nil {
+		return x.Kind
+	}
+	return 0
+}
+
+func (x *DelAttrsRequest) GetTarget() int64 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *DelAttrsRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type DelAttrsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DelAttrsReply) Reset() {
+	*x = DelAttrsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelAttrsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelAttrsReply) ProtoMessage() {}
+
+func (x *DelAttrsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelAttrsReply.ProtoReflect.Descriptor instead.
+func (*DelAttrsReply) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{11}
+}
+
+type ResumeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UID   int64  `protobuf:"varint,1,opt,name=UID,proto3" json:"UID,omitempty"`    // 用户ID
+	Nonce string `protobuf:"bytes,2,opt,name=Nonce,proto3" json:"Nonce,omitempty"` // 恢复令牌随机串
+	GID   string `protobuf:"bytes,3,opt,name=GID,proto3" json:"GID,omitempty"`     // 接管会话的网关ID
+}
+
+func (x *ResumeRequest) Reset() {
+	*x = ResumeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeRequest) ProtoMessage() {}
+
+func (x *ResumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeRequest.ProtoReflect.Descriptor instead.
+func (*ResumeRequest) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ResumeRequest) GetUID() int64 {
+	if x != nil {
+		return x.UID
+	}
+	return 0
+}
+
+func (x *ResumeRequest) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *ResumeRequest) GetGID() string {
+	if x != nil {
+		return x.GID
+	}
+	return ""
+}
+
+type ResumeReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Messages []*Message        `protobuf:"bytes,1,rep,name=Messages,proto3" json:"Messages,omitempty"`                                                                                     // 断线期间缓存的消息
+	Attrs    map[string]string `protobuf:"bytes,2,rep,name=Attrs,proto3" json:"Attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`   // 会话属性
+	Claims   map[string]string `protobuf:"bytes,3,rep,name=Claims,proto3" json:"Claims,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // 鉴权声明
+}
+
+func (x *ResumeReply) Reset() {
+	*x = ResumeReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResumeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeReply) ProtoMessage() {}
+
+func (x *ResumeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeReply.ProtoReflect.Descriptor instead.
+func (*ResumeReply) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ResumeReply) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ResumeReply) GetAttrs() map[string]string {
+	if x != nil {
+		return x.Attrs
+	}
+	return nil
+}
+
+func (x *ResumeReply) GetClaims() map[string]string {
+	if x != nil {
+		return x.Claims
+	}
+	return nil
+}
+
+type DisconnectRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind    int32 `protobuf:"varint,1,opt,name=Kind,proto3" json:"Kind,omitempty"`       // 推送类型 1：CID 2：UID
+	Target  int64 `protobuf:"varint,2,opt,name=Target,proto3" json:"Target,omitempty"`   // 推送目标
+	IsForce bool  `protobuf:"varint,3,opt,name=IsForce,proto3" json:"IsForce,omitempty"` // 是否强制断开连接
+}
+
+func (x *DisconnectRequest) Reset() {
+	*x = DisconnectRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisconnectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisconnectRequest) ProtoMessage() {}
+
+func (x *DisconnectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisconnectRequest.ProtoReflect.Descriptor instead.
+func (*DisconnectRequest) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DisconnectRequest) GetKind() int32 {
+	if x != nil {
+		return x.Kind
+	}
+	return 0
+}
+
+func (x *DisconnectRequest) GetTarget() int64 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *DisconnectRequest) GetIsForce() bool {
+	if x != nil {
+		return x.IsForce
+	}
+	return false
+}
+
+type DisconnectReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DisconnectReply) Reset() {
+	*x = DisconnectReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisconnectReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisconnectReply) ProtoMessage() {}
+
+func (x *DisconnectReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisconnectReply.ProtoReflect.Descriptor instead.
+func (*DisconnectReply) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{15}
+}
+
+type KickRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind   int32 `protobuf:"varint,1,opt,name=Kind,proto3" json:"Kind,omitempty"`     // 踢下线类型 1：CID 2：UID
+	Target int64 `protobuf:"varint,2,opt,name=Target,proto3" json:"Target,omitempty"` // 踢下线目标
+	Reason int32 `protobuf:"varint,3,opt,name=Reason,proto3" json:"Reason,omitempty"` // 踢下线原因的错误码
+}
+
+func (x *KickRequest) Reset() {
+	*x = KickRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KickRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KickRequest) ProtoMessage() {}
+
+func (x *KickRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KickRequest.ProtoReflect.Descriptor instead.
+func (*KickRequest) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *KickRequest) GetKind() int32 {
+	if x != nil {
+		return x.Kind
+	}
+	return 0
+}
+
+func (x *KickRequest) GetTarget() int64 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *KickRequest) GetReason() int32 {
+	if x != nil {
+		return x.Reason
+	}
+	return 0
+}
+
+type KickReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *KickReply) Reset() {
+	*x = KickReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KickReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KickReply) ProtoMessage() {}
+
+func (x *KickReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KickReply.ProtoReflect.Descriptor instead.
+func (*KickReply) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{17}
+}
+
+type PushRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind    int32    `protobuf:"varint,1,opt,name=Kind,proto3" json:"Kind,omitempty"`      // 推送类型 1：CID 2：UID
+	Target  int64    `protobuf:"varint,2,opt,name=Target,proto3" json:"Target,omitempty"`  // 推送目标
+	Message *Message `protobuf:"bytes,3,opt,name=Message,proto3" json:"Message,omitempty"` // 消息
+}
+
+func (x *PushRequest) Reset() {
+	*x = PushRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PushRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushRequest) ProtoMessage() {}
+
+func (x *PushRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushRequest.ProtoReflect.Descriptor instead.
+func (*PushRequest) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PushRequest) GetKind() int32 {
+	if x != nil {
+		return x.Kind
+	}
+	return 0
+}
+
+func (x *PushRequest) GetTarget() int64 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *PushRequest) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type PushReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PushReply) Reset() {
+	*x = PushReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PushReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushReply) ProtoMessage() {}
+
+func (x *PushReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushReply.ProtoReflect.Descriptor instead.
+func (*PushReply) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{19}
+}
+
+type MulticastRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind    int32    `protobuf:"varint,1,opt,name=Kind,proto3" json:"Kind,omitempty"`              // 推送类型 1：CID 2：UID
+	Targets []int64  `protobuf:"varint,2,rep,packed,name=Targets,proto3" json:"Targets,omitempty"` // 推送目标
+	Message *Message `protobuf:"bytes,3,opt,name=Message,proto3" json:"Message,omitempty"`         // 消息
+}
+
+func (x *MulticastRequest) Reset() {
+	*x = MulticastRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MulticastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MulticastRequest) ProtoMessage() {}
+
+func (x *MulticastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MulticastRequest.ProtoReflect.Descriptor instead.
+func (*MulticastRequest) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MulticastRequest) GetKind() int32 {
+	if x != nil {
+		return x.Kind
+	}
+	return 0
+}
+
+func (x *MulticastRequest) GetTargets() []int64 {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *MulticastRequest) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type MulticastReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Total int64 `protobuf:"varint,1,opt,name=Total,proto3" json:"Total,omitempty"` // 组播数量
+}
+
+func (x *MulticastReply) Reset() {
+	*x = MulticastReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MulticastReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MulticastReply) ProtoMessage() {}
+
+func (x *MulticastReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MulticastReply.ProtoReflect.Descriptor instead.
+func (*MulticastReply) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *MulticastReply) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type BroadcastRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind    int32    `protobuf:"varint,1,opt,name=Kind,proto3" json:"Kind,omitempty"`      // 推送类型 1：CID 2：UID
+	Message *Message `protobuf:"bytes,2,opt,name=Message,proto3" json:"Message,omitempty"` // 消息
+}
+
+func (x *BroadcastRequest) Reset() {
+	*x = BroadcastRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BroadcastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastRequest) ProtoMessage() {}
+
+func (x *BroadcastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastRequest.ProtoReflect.Descriptor instead.
+func (*BroadcastRequest) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BroadcastRequest) GetKind() int32 {
+	if x != nil {
+		return x.Kind
+	}
+	return 0
+}
+
+func (x *BroadcastRequest) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type BroadcastReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Total int64 `protobuf:"varint,1,opt,name=Total,proto3" json:"Total,omitempty"` // 广播数量
+}
+
+func (x *BroadcastReply) Reset() {
+	*x = BroadcastReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BroadcastReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastReply) ProtoMessage() {}
+
+func (x *BroadcastReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastReply.ProtoReflect.Descriptor instead.
+func (*BroadcastReply) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BroadcastReply) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type JoinChannelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel string  `protobuf:"bytes,1,opt,name=Channel,proto3" json:"Channel,omitempty"`   // 频道
+	UIDs    []int64 `protobuf:"varint,2,rep,packed,name=UIDs,proto3" json:"UIDs,omitempty"` // 用户ID
+}
+
+func (x *JoinChannelRequest) Reset() {
+	*x = JoinChannelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JoinChannelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinChannelRequest) ProtoMessage() {}
+
+func (x *JoinChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinChannelRequest.ProtoReflect.Descriptor instead.
+func (*JoinChannelRequest) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *JoinChannelRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *JoinChannelRequest) GetUIDs() []int64 {
+	if x != nil {
+		return x.UIDs
+	}
+	return nil
+}
+
+type JoinChannelReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *JoinChannelReply) Reset() {
+	*x = JoinChannelReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gate_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JoinChannelReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinChannelReply) ProtoMessage() {}
+
+func (x *JoinChannelReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinChannelReply.ProtoReflect.Descriptor instead.
+func (*JoinChannelReply) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{25}
+}
+
+type LeaveChannelRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Kind    int32    `protobuf:"varint,1,opt,name=Kind,proto3" json:"Kind,omitempty"`              // 推送类型 1：CID 2：UID
-	Targets []int64  `protobuf:"varint,2,rep,packed,name=Targets,proto3" json:"Targets,omitempty"` // 推送目标
-	Message *Message `protobuf:"bytes,3,opt,name=Message,proto3" json:"Message,omitempty"`         // 消息
+	Channel string  `protobuf:"bytes,1,opt,name=Channel,proto3" json:"Channel,omitempty"`   // 频道
+	UIDs    []int64 `protobuf:"varint,2,rep,packed,name=UIDs,proto3" json:"UIDs,omitempty"` // 用户ID，为空时解散频道
 }
 
-func (x *MulticastRequest) Reset() {
-	*x = MulticastRequest{}
+func (x *LeaveChannelRequest) Reset() {
+	*x = LeaveChannelRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gate_proto_msgTypes[10]
+		mi := &file_gate_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MulticastRequest) String() string {
+func (x *LeaveChannelRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MulticastRequest) ProtoMessage() {}
+func (*LeaveChannelRequest) ProtoMessage() {}
 
-func (x *MulticastRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_gate_proto_msgTypes[10]
+func (x *LeaveChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -539,57 +1374,48 @@ func (x *MulticastRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MulticastRequest.ProtoReflect.Descriptor instead.
-func (*MulticastRequest) Descriptor() ([]byte, []int) {
-	return file_gate_proto_rawDescGZIP(), []int{10}
-}
-
-func (x *MulticastRequest) GetKind() int32 {
-	if x != nil {
-		return x.Kind
-	}
-	return 0
+// Deprecated: Use LeaveChannelRequest.ProtoReflect.Descriptor instead.
+func (*LeaveChannelRequest) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *MulticastRequest) GetTargets() []int64 {
+func (x *LeaveChannelRequest) GetChannel() string {
 	if x != nil {
-		return x.Targets
+		return x.Channel
 	}
-	return nil
+	return ""
 }
 
-func (x *MulticastRequest) GetMessage() *Message {
+func (x *LeaveChannelRequest) GetUIDs() []int64 {
 	if x != nil {
-		return x.Message
+		return x.UIDs
 	}
 	return nil
 }
 
-type MulticastReply struct {
+type LeaveChannelReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	Total int64 `protobuf:"varint,1,opt,name=Total,proto3" json:"Total,omitempty"` // 组播数量
 }
 
-func (x *MulticastReply) Reset() {
-	*x = MulticastReply{}
+func (x *LeaveChannelReply) Reset() {
+	*x = LeaveChannelReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gate_proto_msgTypes[11]
+		mi := &file_gate_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MulticastReply) String() string {
+func (x *LeaveChannelReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MulticastReply) ProtoMessage() {}
+func (*LeaveChannelReply) ProtoMessage() {}
 
-func (x *MulticastReply) ProtoReflect() protoreflect.Message {
-	mi := &file_gate_proto_msgTypes[11]
+func (x *LeaveChannelReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -600,44 +1426,37 @@ func (x *MulticastReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MulticastReply.ProtoReflect.Descriptor instead.
-func (*MulticastReply) Descriptor() ([]byte, []int) {
-	return file_gate_proto_rawDescGZIP(), []int{11}
-}
-
-func (x *MulticastReply) GetTotal() int64 {
-	if x != nil {
-		return x.Total
-	}
-	return 0
+// Deprecated: Use LeaveChannelReply.ProtoReflect.Descriptor instead.
+func (*LeaveChannelReply) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{27}
 }
 
-type BroadcastRequest struct {
+type PublishChannelRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Kind    int32    `protobuf:"varint,1,opt,name=Kind,proto3" json:"Kind,omitempty"`      // 推送类型 1：CID 2：UID
+	Channel string   `protobuf:"bytes,1,opt,name=Channel,proto3" json:"Channel,omitempty"` // 频道
 	Message *Message `protobuf:"bytes,2,opt,name=Message,proto3" json:"Message,omitempty"` // 消息
 }
 
-func (x *BroadcastRequest) Reset() {
-	*x = BroadcastRequest{}
+func (x *PublishChannelRequest) Reset() {
+	*x = PublishChannelRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gate_proto_msgTypes[12]
+		mi := &file_gate_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *BroadcastRequest) String() string {
+func (x *PublishChannelRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BroadcastRequest) ProtoMessage() {}
+func (*PublishChannelRequest) ProtoMessage() {}
 
-func (x *BroadcastRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_gate_proto_msgTypes[12]
+func (x *PublishChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -648,50 +1467,50 @@ func (x *BroadcastRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BroadcastRequest.ProtoReflect.Descriptor instead.
-func (*BroadcastRequest) Descriptor() ([]byte, []int) {
-	return file_gate_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use PublishChannelRequest.ProtoReflect.Descriptor instead.
+func (*PublishChannelRequest) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *BroadcastRequest) GetKind() int32 {
+func (x *PublishChannelRequest) GetChannel() string {
 	if x != nil {
-		return x.Kind
+		return x.Channel
 	}
-	return 0
+	return ""
 }
 
-func (x *BroadcastRequest) GetMessage() *Message {
+func (x *PublishChannelRequest) GetMessage() *Message {
 	if x != nil {
 		return x.Message
 	}
 	return nil
 }
 
-type BroadcastReply struct {
+type PublishChannelReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Total int64 `protobuf:"varint,1,opt,name=Total,proto3" json:"Total,omitempty"` // 广播数量
+	Total int64 `protobuf:"varint,1,opt,name=Total,proto3" json:"Total,omitempty"` // 推送数量
 }
 
-func (x *BroadcastReply) Reset() {
-	*x = BroadcastReply{}
+func (x *PublishChannelReply) Reset() {
+	*x = PublishChannelReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gate_proto_msgTypes[13]
+		mi := &file_gate_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *BroadcastReply) String() string {
+func (x *PublishChannelReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BroadcastReply) ProtoMessage() {}
+func (*PublishChannelReply) ProtoMessage() {}
 
-func (x *BroadcastReply) ProtoReflect() protoreflect.Message {
-	mi := &file_gate_proto_msgTypes[13]
+func (x *PublishChannelReply) ProtoReflect() protoreflect.Message {
+	mi := &file_gate_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -702,12 +1521,12 @@ func (x *BroadcastReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BroadcastReply.ProtoReflect.Descriptor instead.
-func (*BroadcastReply) Descriptor() ([]byte, []int) {
-	return file_gate_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use PublishChannelReply.ProtoReflect.Descriptor instead.
+func (*PublishChannelReply) Descriptor() ([]byte, []int) {
+	return file_gate_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *BroadcastReply) GetTotal() int64 {
+func (x *PublishChannelReply) GetTotal() int64 {
 	if x != nil {
 		return x.Total
 	}
@@ -731,61 +1550,167 @@ var file_gate_proto_rawDesc = []byte{
 	0x04, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18,
 	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x1c, 0x0a,
 	0x0a, 0x47, 0x65, 0x74, 0x49, 0x50, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x49,
-	0x50, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x50, 0x22, 0x59, 0x0a, 0x11, 0x44,
-	0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x12, 0x0a, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
-	0x4b, 0x69, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07,
-	0x49, 0x73, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x49,
-	0x73, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x22, 0x11, 0x0a, 0x0f, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e,
-	0x6e, 0x65, 0x63, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x60, 0x0a, 0x0b, 0x50, 0x75, 0x73,
-	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x4b, 0x69, 0x6e, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06,
-	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x54, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x12, 0x25, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x0b, 0x0a, 0x09, 0x50,
-	0x75, 0x73, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x67, 0x0a, 0x10, 0x4d, 0x75, 0x6c, 0x74,
-	0x69, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
-	0x4b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x4b, 0x69, 0x6e, 0x64,
-	0x12, 0x18, 0x0a, 0x07, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x03, 0x52, 0x07, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x07, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x62,
-	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x22, 0x26, 0x0a, 0x0e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x05, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x4d, 0x0a, 0x10, 0x42, 0x72, 0x6f,
-	0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x50, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x50, 0x22, 0x3d, 0x0a, 0x0f, 0x47,
+	0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x4b, 0x69,
+	0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x7d, 0x0a, 0x0d, 0x47, 0x65,
+	0x74, 0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x32, 0x0a, 0x05, 0x41,
+	0x74, 0x74, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x62, 0x2e,
+	0x47, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x41, 0x74,
+	0x74, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x41, 0x74, 0x74, 0x72, 0x73, 0x1a,
+	0x38, 0x0a, 0x0a, 0x41, 0x74, 0x74, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xad, 0x01, 0x0a, 0x0f, 0x53, 0x65,
+	0x74, 0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
 	0x04, 0x4b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x4b, 0x69, 0x6e,
-	0x64, 0x12, 0x25, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52,
-	0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x26, 0x0a, 0x0e, 0x42, 0x72, 0x6f, 0x61,
-	0x64, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x6f,
-	0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x54, 0x6f, 0x74, 0x61, 0x6c,
-	0x32, 0xe5, 0x02, 0x0a, 0x04, 0x47, 0x61, 0x74, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x42, 0x69, 0x6e,
-	0x64, 0x12, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x12, 0x2e, 0x0a, 0x06, 0x55, 0x6e, 0x62, 0x69, 0x6e, 0x64, 0x12, 0x11, 0x2e,
-	0x70, 0x62, 0x2e, 0x55, 0x6e, 0x62, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x55, 0x6e, 0x62, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x12, 0x2b, 0x0a, 0x05, 0x47, 0x65, 0x74, 0x49, 0x50, 0x12, 0x10, 0x2e, 0x70,
-	0x62, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e,
-	0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x50, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
-	0x12, 0x3a, 0x0a, 0x0a, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x15,
-	0x2e, 0x70, 0x62, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f,
-	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x28, 0x0a, 0x04,
-	0x50, 0x75, 0x73, 0x68, 0x12, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x52,
-	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x09, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x63,
-	0x61, 0x73, 0x74, 0x12, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x63, 0x61,
-	0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x4d,
-	0x75, 0x6c, 0x74, 0x69, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
-	0x37, 0x0a, 0x09, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x12, 0x14, 0x2e, 0x70,
-	0x62, 0x2e, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73,
-	0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x06, 0x5a, 0x04, 0x2e, 0x2f, 0x70, 0x62,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x34, 0x0a, 0x05, 0x41, 0x74, 0x74,
+	0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x65,
+	0x74, 0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x74,
+	0x74, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x41, 0x74, 0x74, 0x72, 0x73, 0x1a,
+	0x38, 0x0a, 0x0a, 0x41, 0x74, 0x74, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x0f, 0x0a, 0x0d, 0x53, 0x65, 0x74,
+	0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x51, 0x0a, 0x0f, 0x44, 0x65,
+	0x6c, 0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x4b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x4b, 0x69, 0x6e,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x4b, 0x65, 0x79,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x4b, 0x65, 0x79, 0x73, 0x22, 0x0f, 0x0a,
+	0x0d, 0x44, 0x65, 0x6c, 0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x49,
+	0x0a, 0x0d, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x55, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x55, 0x49,
+	0x44, 0x12, 0x14, 0x0a, 0x05, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x47, 0x49, 0x44, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x47, 0x49, 0x44, 0x22, 0x92, 0x02, 0x0a, 0x0b, 0x52, 0x65,
+	0x73, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x27, 0x0a, 0x08, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x62,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x08, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x12, 0x30, 0x0a, 0x05, 0x41, 0x74, 0x74, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x41,
+	0x74, 0x74, 0x72, 0x73, 0x12, 0x33, 0x0a, 0x06, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x06, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x73, 0x1a, 0x38, 0x0a, 0x0a, 0x41, 0x74, 0x74,
+	0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x39, 0x0a, 0x0b, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x59,
+	0x0a, 0x11, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x49, 0x73, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x49, 0x73, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x22, 0x11, 0x0a, 0x0f, 0x44, 0x69, 0x73,
+	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x51, 0x0a, 0x0b,
+	0x4b, 0x69, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x4b,
+	0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22,
+	0x0b, 0x0a, 0x09, 0x4b, 0x69, 0x63, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x60, 0x0a, 0x0b,
+	0x50, 0x75, 0x73, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x4b,
+	0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x25, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x0b,
+	0x0a, 0x09, 0x50, 0x75, 0x73, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x67, 0x0a, 0x10, 0x4d,
+	0x75, 0x6c, 0x74, 0x69, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x4b,
+	0x69, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x03, 0x52, 0x07, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x25, 0x0a,
+	0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b,
+	0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x22, 0x26, 0x0a, 0x0e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x63, 0x61, 0x73,
+	0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x4d, 0x0a, 0x10,
+	0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x4b, 0x69, 0x6e, 0x64, 0x12, 0x25, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x26, 0x0a, 0x0e, 0x42,
+	0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x54, 0x6f,
+	0x74, 0x61, 0x6c, 0x22, 0x42, 0x0a, 0x12, 0x4a, 0x6f, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x55, 0x49, 0x44, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x03, 0x52, 0x04, 0x55, 0x49, 0x44, 0x73, 0x22, 0x12, 0x0a, 0x10, 0x4a, 0x6f, 0x69, 0x6e, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x43, 0x0a, 0x13, 0x4c,
+	0x65, 0x61, 0x76, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04,
+	0x55, 0x49, 0x44, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x03, 0x52, 0x04, 0x55, 0x49, 0x44, 0x73,
+	0x22, 0x13, 0x0a, 0x11, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x58, 0x0a, 0x15, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x25, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x62, 0x2e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
+	0x2b, 0x0a, 0x13, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x32, 0xaa, 0x06, 0x0a,
+	0x04, 0x47, 0x61, 0x74, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x42, 0x69, 0x6e, 0x64, 0x12, 0x0f, 0x2e,
+	0x70, 0x62, 0x2e, 0x42, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d,
+	0x2e, 0x70, 0x62, 0x2e, 0x42, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
+	0x2e, 0x0a, 0x06, 0x55, 0x6e, 0x62, 0x69, 0x6e, 0x64, 0x12, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x55,
+	0x6e, 0x62, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x70,
+	0x62, 0x2e, 0x55, 0x6e, 0x62, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
+	0x2b, 0x0a, 0x05, 0x47, 0x65, 0x74, 0x49, 0x50, 0x12, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65,
+	0x74, 0x49, 0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x62, 0x2e,
+	0x47, 0x65, 0x74, 0x49, 0x50, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x08,
+	0x47, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x73, 0x12, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65,
+	0x74, 0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e,
+	0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x12, 0x34, 0x0a, 0x08, 0x53, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x73, 0x12, 0x13,
+	0x2e, 0x70, 0x62, 0x2e, 0x53, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72,
+	0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x08, 0x44, 0x65, 0x6c, 0x41,
+	0x74, 0x74, 0x72, 0x73, 0x12, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x44, 0x65, 0x6c, 0x41, 0x74, 0x74,
+	0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x44,
+	0x65, 0x6c, 0x41, 0x74, 0x74, 0x72, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x2e,
+	0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x12, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65,
+	0x73, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x70, 0x62,
+	0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a,
+	0x0a, 0x0a, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x15, 0x2e, 0x70,
+	0x62, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x28, 0x0a, 0x04, 0x4b, 0x69,
+	0x63, 0x6b, 0x12, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x4b, 0x69, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x4b, 0x69, 0x63, 0x6b, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x12, 0x28, 0x0a, 0x04, 0x50, 0x75, 0x73, 0x68, 0x12, 0x0f, 0x2e, 0x70,
+	0x62, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e,
+	0x70, 0x62, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x37,
+	0x0a, 0x09, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x63, 0x61, 0x73, 0x74, 0x12, 0x14, 0x2e, 0x70, 0x62,
+	0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x63, 0x61, 0x73, 0x74,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x09, 0x42, 0x72, 0x6f, 0x61, 0x64,
+	0x63, 0x61, 0x73, 0x74, 0x12, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63,
+	0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x62, 0x2e,
+	0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x3d, 0x0a, 0x0b, 0x4a, 0x6f, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12,
+	0x16, 0x2e, 0x70, 0x62, 0x2e, 0x4a, 0x6f, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x4a, 0x6f, 0x69,
+	0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
+	0x40, 0x0a, 0x0c, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12,
+	0x17, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x65,
+	0x61, 0x76, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
+	0x00, 0x12, 0x46, 0x0a, 0x0e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x12, 0x19, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17,
+	0x2e, 0x70, 0x62, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x06, 0x5a, 0x04, 0x2e, 0x2f, 0x70,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -800,47 +1725,89 @@ func file_gate_proto_rawDescGZIP() []byte {
 	return file_gate_proto_rawDescData
 }
 
-var file_gate_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_gate_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
 var file_gate_proto_goTypes = []interface{}{
-	(*BindRequest)(nil),       // 0: pb.BindRequest
-	(*BindReply)(nil),         // 1: pb.BindReply
-	(*UnbindRequest)(nil),     // 2: pb.UnbindRequest
-	(*UnbindReply)(nil),       // 3: pb.UnbindReply
-	(*GetIPRequest)(nil),      // 4: pb.GetIPRequest
-	(*GetIPReply)(nil),        // 5: pb.GetIPReply
-	(*DisconnectRequest)(nil), // 6: pb.DisconnectRequest
-	(*DisconnectReply)(nil),   // 7: pb.DisconnectReply
-	(*PushRequest)(nil),       // 8: pb.PushRequest
-	(*PushReply)(nil),         // 9: pb.PushReply
-	(*MulticastRequest)(nil),  // 10: pb.MulticastRequest
-	(*MulticastReply)(nil),    // 11: pb.MulticastReply
-	(*BroadcastRequest)(nil),  // 12: pb.BroadcastRequest
-	(*BroadcastReply)(nil),    // 13: pb.BroadcastReply
-	(*Message)(nil),           // 14: pb.Message
+	(*BindRequest)(nil),           // 0: pb.BindRequest
+	(*BindReply)(nil),             // 1: pb.BindReply
+	(*UnbindRequest)(nil),         // 2: pb.UnbindRequest
+	(*UnbindReply)(nil),           // 3: pb.UnbindReply
+	(*GetIPRequest)(nil),          // 4: pb.GetIPRequest
+	(*GetIPReply)(nil),            // 5: pb.GetIPReply
+	(*GetAttrsRequest)(nil),       // 6: pb.GetAttrsRequest
+	(*GetAttrsReply)(nil),         // 7: pb.GetAttrsReply
+	(*SetAttrsRequest)(nil),       // 8: pb.SetAttrsRequest
+	(*SetAttrsReply)(nil),         // 9: pb.SetAttrsReply
+	(*DelAttrsRequest)(nil),       // 10: pb.DelAttrsRequest
+	(*DelAttrsReply)(nil),         // 11: pb.DelAttrsReply
+	(*ResumeRequest)(nil),         // 12: pb.ResumeRequest
+	(*ResumeReply)(nil),           // 13: pb.ResumeReply
+	(*DisconnectRequest)(nil),     // 14: pb.DisconnectRequest
+	(*DisconnectReply)(nil),       // 15: pb.DisconnectReply
+	(*KickRequest)(nil),           // 16: pb.KickRequest
+	(*KickReply)(nil),             // 17: pb.KickReply
+	(*PushRequest)(nil),           // 18: pb.PushRequest
+	(*PushReply)(nil),             // 19: pb.PushReply
+	(*MulticastRequest)(nil),      // 20: pb.MulticastRequest
+	(*MulticastReply)(nil),        // 21: pb.MulticastReply
+	(*BroadcastRequest)(nil),      // 22: pb.BroadcastRequest
+	(*BroadcastReply)(nil),        // 23: pb.BroadcastReply
+	(*JoinChannelRequest)(nil),    // 24: pb.JoinChannelRequest
+	(*JoinChannelReply)(nil),      // 25: pb.JoinChannelReply
+	(*LeaveChannelRequest)(nil),   // 26: pb.LeaveChannelRequest
+	(*LeaveChannelReply)(nil),     // 27: pb.LeaveChannelReply
+	(*PublishChannelRequest)(nil), // 28: pb.PublishChannelRequest
+	(*PublishChannelReply)(nil),   // 29: pb.PublishChannelReply
+	nil,                           // 30: pb.GetAttrsReply.AttrsEntry
+	nil,                           // 31: pb.SetAttrsRequest.AttrsEntry
+	nil,                           // 32: pb.ResumeReply.AttrsEntry
+	nil,                           // 33: pb.ResumeReply.ClaimsEntry
+	(*Message)(nil),               // 34: pb.Message
 }
 var file_gate_proto_depIdxs = []int32{
-	14, // 0: pb.PushRequest.Message:type_name -> pb.Message
-	14, // 1: pb.MulticastRequest.Message:type_name -> pb.Message
-	14, // 2: pb.BroadcastRequest.Message:type_name -> pb.Message
-	0,  // 3: pb.Gate.Bind:input_type -> pb.BindRequest
-	2,  // 4: pb.Gate.Unbind:input_type -> pb.UnbindRequest
-	4,  // 5: pb.Gate.GetIP:input_type -> pb.GetIPRequest
-	6,  // 6: pb.Gate.Disconnect:input_type -> pb.DisconnectRequest
-	8,  // 7: pb.Gate.Push:input_type -> pb.PushRequest
-	10, // 8: pb.Gate.Multicast:input_type -> pb.MulticastRequest
-	12, // 9: pb.Gate.Broadcast:input_type -> pb.BroadcastRequest
-	1,  // 10: pb.Gate.Bind:output_type -> pb.BindReply
-	3,  // 11: pb.Gate.Unbind:output_type -> pb.UnbindReply
-	5,  // 12: pb.Gate.GetIP:output_type -> pb.GetIPReply
-	7,  // 13: pb.Gate.Disconnect:output_type -> pb.DisconnectReply
-	9,  // 14: pb.Gate.Push:output_type -> pb.PushReply
-	11, // 15: pb.Gate.Multicast:output_type -> pb.MulticastReply
-	13, // 16: pb.Gate.Broadcast:output_type -> pb.BroadcastReply
-	10, // [10:17] is the sub-list for method output_type
-	3,  // [3:10] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	30, // 0: pb.GetAttrsReply.Attrs:type_name -> pb.GetAttrsReply.AttrsEntry
+	31, // 1: pb.SetAttrsRequest.Attrs:type_name -> pb.SetAttrsRequest.AttrsEntry
+	34, // 2: pb.ResumeReply.Messages:type_name -> pb.Message
+	32, // 3: pb.ResumeReply.Attrs:type_name -> pb.ResumeReply.AttrsEntry
+	33, // 4: pb.ResumeReply.Claims:type_name -> pb.ResumeReply.ClaimsEntry
+	34, // 5: pb.PushRequest.Message:type_name -> pb.Message
+	34, // 6: pb.MulticastRequest.Message:type_name -> pb.Message
+	34, // 7: pb.BroadcastRequest.Message:type_name -> pb.Message
+	34, // 8: pb.PublishChannelRequest.Message:type_name -> pb.Message
+	0,  // 9: pb.Gate.Bind:input_type -> pb.BindRequest
+	2,  // 10: pb.Gate.Unbind:input_type -> pb.UnbindRequest
+	4,  // 11: pb.Gate.GetIP:input_type -> pb.GetIPRequest
+	6,  // 12: pb.Gate.GetAttrs:input_type -> pb.GetAttrsRequest
+	8,  // 13: pb.Gate.SetAttrs:input_type -> pb.SetAttrsRequest
+	10, // 14: pb.Gate.DelAttrs:input_type -> pb.DelAttrsRequest
+	12, // 15: pb.Gate.Resume:input_type -> pb.ResumeRequest
+	14, // 16: pb.Gate.Disconnect:input_type -> pb.DisconnectRequest
+	16, // 17: pb.Gate.Kick:input_type -> pb.KickRequest
+	18, // 18: pb.Gate.Push:input_type -> pb.PushRequest
+	20, // 19: pb.Gate.Multicast:input_type -> pb.MulticastRequest
+	22, // 20: pb.Gate.Broadcast:input_type -> pb.BroadcastRequest
+	24, // 21: pb.Gate.JoinChannel:input_type -> pb.JoinChannelRequest
+	26, // 22: pb.Gate.LeaveChannel:input_type -> pb.LeaveChannelRequest
+	28, // 23: pb.Gate.PublishChannel:input_type -> pb.PublishChannelRequest
+	1,  // 24: pb.Gate.Bind:output_type -> pb.BindReply
+	3,  // 25: pb.Gate.Unbind:output_type -> pb.UnbindReply
+	5,  // 26: pb.Gate.GetIP:output_type -> pb.GetIPReply
+	7,  // 27: pb.Gate.GetAttrs:output_type -> pb.GetAttrsReply
+	9,  // 28: pb.Gate.SetAttrs:output_type -> pb.SetAttrsReply
+	11, // 29: pb.Gate.DelAttrs:output_type -> pb.DelAttrsReply
+	13, // 30: pb.Gate.Resume:output_type -> pb.ResumeReply
+	15, // 31: pb.Gate.Disconnect:output_type -> pb.DisconnectReply
+	17, // 32: pb.Gate.Kick:output_type -> pb.KickReply
+	19, // 33: pb.Gate.Push:output_type -> pb.PushReply
+	21, // 34: pb.Gate.Multicast:output_type -> pb.MulticastReply
+	23, // 35: pb.Gate.Broadcast:output_type -> pb.BroadcastReply
+	25, // 36: pb.Gate.JoinChannel:output_type -> pb.JoinChannelReply
+	27, // 37: pb.Gate.LeaveChannel:output_type -> pb.LeaveChannelReply
+	29, // 38: pb.Gate.PublishChannel:output_type -> pb.PublishChannelReply
+	24, // [24:39] is the sub-list for method output_type
+	9,  // [9:24] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_gate_proto_init() }
@@ -923,7 +1890,7 @@ func file_gate_proto_init() {
 			}
 		}
 		file_gate_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DisconnectRequest); i {
+			switch v := v.(*GetAttrsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -935,7 +1902,7 @@ func file_gate_proto_init() {
 			}
 		}
 		file_gate_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DisconnectReply); i {
+			switch v := v.(*GetAttrsReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -947,7 +1914,7 @@ func file_gate_proto_init() {
 			}
 		}
 		file_gate_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PushRequest); i {
+			switch v := v.(*SetAttrsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -959,7 +1926,7 @@ func file_gate_proto_init() {
 			}
 		}
 		file_gate_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PushReply); i {
+			switch v := v.(*SetAttrsReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -971,7 +1938,7 @@ func file_gate_proto_init() {
 			}
 		}
 		file_gate_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MulticastRequest); i {
+			switch v := v.(*DelAttrsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -983,7 +1950,7 @@ func file_gate_proto_init() {
 			}
 		}
 		file_gate_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MulticastReply); i {
+			switch v := v.(*DelAttrsReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -995,7 +1962,7 @@ func file_gate_proto_init() {
 			}
 		}
 		file_gate_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BroadcastRequest); i {
+			switch v := v.(*ResumeRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1007,6 +1974,126 @@ func file_gate_proto_init() {
 			}
 		}
 		file_gate_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResumeReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DisconnectRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DisconnectReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KickRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KickReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PushRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PushReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MulticastRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MulticastReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BroadcastRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BroadcastReply); i {
 			case 0:
 				return &v.state
@@ -1018,6 +2105,78 @@ func file_gate_proto_init() {
 				return nil
 			}
 		}
+		file_gate_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JoinChannelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JoinChannelReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaveChannelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaveChannelReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishChannelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gate_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishChannelReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1025,7 +2184,7 @@ func file_gate_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_gate_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
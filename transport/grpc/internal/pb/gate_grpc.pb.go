@@ -28,14 +28,30 @@ type GateClient interface {
 	Unbind(ctx context.Context, in *UnbindRequest, opts ...grpc.CallOption) (*UnbindReply, error)
 	// 获取客户端IP
 	GetIP(ctx context.Context, in *GetIPRequest, opts ...grpc.CallOption) (*GetIPReply, error)
+	// 获取会话属性
+	GetAttrs(ctx context.Context, in *GetAttrsRequest, opts ...grpc.CallOption) (*GetAttrsReply, error)
+	// 设置会话属性
+	SetAttrs(ctx context.Context, in *SetAttrsRequest, opts ...grpc.CallOption) (*SetAttrsReply, error)
+	// 删除会话属性
+	DelAttrs(ctx context.Context, in *DelAttrsRequest, opts ...grpc.CallOption) (*DelAttrsReply, error)
+	// 恢复断线的用户会话
+	Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeReply, error)
 	// 断开连接
 	Disconnect(ctx context.Context, in *DisconnectRequest, opts ...grpc.CallOption) (*DisconnectReply, error)
+	// 踢下线
+	Kick(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*KickReply, error)
 	// 推送消息
 	Push(ctx context.Context, in *PushRequest, opts ...grpc.CallOption) (*PushReply, error)
 	// 推送组播消息
 	Multicast(ctx context.Context, in *MulticastRequest, opts ...grpc.CallOption) (*MulticastReply, error)
 	// 推送广播消息
 	Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*BroadcastReply, error)
+	// 加入频道
+	JoinChannel(ctx context.Context, in *JoinChannelRequest, opts ...grpc.CallOption) (*JoinChannelReply, error)
+	// 离开频道
+	LeaveChannel(ctx context.Context, in *LeaveChannelRequest, opts ...grpc.CallOption) (*LeaveChannelReply, error)
+	// 发布频道消息
+	PublishChannel(ctx context.Context, in *PublishChannelRequest, opts ...grpc.CallOption) (*PublishChannelReply, error)
 }
 
 type gateClient struct {
@@ -73,6 +89,42 @@ func (c *gateClient) GetIP(ctx context.Context, in *GetIPRequest, opts ...grpc.C
 	return out, nil
 }
 
+func (c *gateClient) GetAttrs(ctx context.Context, in *GetAttrsRequest, opts ...grpc.CallOption) (*GetAttrsReply, error) {
+	out := new(GetAttrsReply)
+	err := c.cc.Invoke(ctx, "/pb.Gate/GetAttrs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gateClient) SetAttrs(ctx context.Context, in *SetAttrsRequest, opts ...grpc.CallOption) (*SetAttrsReply, error) {
+	out := new(SetAttrsReply)
+	err := c.cc.Invoke(ctx, "/pb.Gate/SetAttrs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gateClient) DelAttrs(ctx context.Context, in *DelAttrsRequest, opts ...grpc.CallOption) (*DelAttrsReply, error) {
+	out := new(DelAttrsReply)
+	err := c.cc.Invoke(ctx, "/pb.Gate/DelAttrs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gateClient) Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeReply, error) {
+	out := new(ResumeReply)
+	err := c.cc.Invoke(ctx, "/pb.Gate/Resume", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gateClient) Disconnect(ctx context.Context, in *DisconnectRequest, opts ...grpc.CallOption) (*DisconnectReply, error) {
 	out := new(DisconnectReply)
 	err := c.cc.Invoke(ctx, "/pb.Gate/Disconnect", in, out, opts...)
@@ -82,6 +134,15 @@ func (c *gateClient) Disconnect(ctx context.Context, in *DisconnectRequest, opts
 	return out, nil
 }
 
+func (c *gateClient) Kick(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*KickReply, error) {
+	out := new(KickReply)
+	err := c.cc.Invoke(ctx, "/pb.Gate/Kick", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gateClient) Push(ctx context.Context, in *PushRequest, opts ...grpc.CallOption) (*PushReply, error) {
 	out := new(PushReply)
 	err := c.cc.Invoke(ctx, "/pb.Gate/Push", in, out, opts...)
@@ -109,6 +170,33 @@ func (c *gateClient) Broadcast(ctx context.Context, in *BroadcastRequest, opts .
 	return out, nil
 }
 
+func (c *gateClient) JoinChannel(ctx context.Context, in *JoinChannelRequest, opts ...grpc.CallOption) (*JoinChannelReply, error) {
+	out := new(JoinChannelReply)
+	err := c.cc.Invoke(ctx, "/pb.Gate/JoinChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gateClient) LeaveChannel(ctx context.Context, in *LeaveChannelRequest, opts ...grpc.CallOption) (*LeaveChannelReply, error) {
+	out := new(LeaveChannelReply)
+	err := c.cc.Invoke(ctx, "/pb.Gate/LeaveChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gateClient) PublishChannel(ctx context.Context, in *PublishChannelRequest, opts ...grpc.CallOption) (*PublishChannelReply, error) {
+	out := new(PublishChannelReply)
+	err := c.cc.Invoke(ctx, "/pb.Gate/PublishChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GateServer is the server API for Gate service.
 // All implementations must embed UnimplementedGateServer
 // for forward compatibility
@@ -119,14 +207,30 @@ type GateServer interface {
 	Unbind(context.Context, *UnbindRequest) (*UnbindReply, error)
 	// 获取客户端IP
 	GetIP(context.Context, *GetIPRequest) (*GetIPReply, error)
+	// 获取会话属性
+	GetAttrs(context.Context, *GetAttrsRequest) (*GetAttrsReply, error)
+	// 设置会话属性
+	SetAttrs(context.Context, *SetAttrsRequest) (*SetAttrsReply, error)
+	// 删除会话属性
+	DelAttrs(context.Context, *DelAttrsRequest) (*DelAttrsReply, error)
+	// 恢复断线的用户会话
+	Resume(context.Context, *ResumeRequest) (*ResumeReply, error)
 	// 断开连接
 	Disconnect(context.Context, *DisconnectRequest) (*DisconnectReply, error)
+	// 踢下线
+	Kick(context.Context, *KickRequest) (*KickReply, error)
 	// 推送消息
 	Push(context.Context, *PushRequest) (*PushReply, error)
 	// 推送组播消息
 	Multicast(context.Context, *MulticastRequest) (*MulticastReply, error)
 	// 推送广播消息
 	Broadcast(context.Context, *BroadcastRequest) (*BroadcastReply, error)
+	// 加入频道
+	JoinChannel(context.Context, *JoinChannelRequest) (*JoinChannelReply, error)
+	// 离开频道
+	LeaveChannel(context.Context, *LeaveChannelRequest) (*LeaveChannelReply, error)
+	// 发布频道消息
+	PublishChannel(context.Context, *PublishChannelRequest) (*PublishChannelReply, error)
 	mustEmbedUnimplementedGateServer()
 }
 
@@ -143,9 +247,24 @@ func (UnimplementedGateServer) Unbind(context.Context, *UnbindRequest) (*UnbindR
 func (UnimplementedGateServer) GetIP(context.Context, *GetIPRequest) (*GetIPReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetIP not implemented")
 }
+func (UnimplementedGateServer) GetAttrs(context.Context, *GetAttrsRequest) (*GetAttrsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAttrs not implemented")
+}
+func (UnimplementedGateServer) SetAttrs(context.Context, *SetAttrsRequest) (*SetAttrsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAttrs not implemented")
+}
+func (UnimplementedGateServer) DelAttrs(context.Context, *DelAttrsRequest) (*DelAttrsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DelAttrs not implemented")
+}
+func (UnimplementedGateServer) Resume(context.Context, *ResumeRequest) (*ResumeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
+}
 func (UnimplementedGateServer) Disconnect(context.Context, *DisconnectRequest) (*DisconnectReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Disconnect not implemented")
 }
+func (UnimplementedGateServer) Kick(context.Context, *KickRequest) (*KickReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Kick not implemented")
+}
 func (UnimplementedGateServer) Push(context.Context, *PushRequest) (*PushReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Push not implemented")
 }
@@ -155,6 +274,15 @@ func (UnimplementedGateServer) Multicast(context.Context, *MulticastRequest) (*M
 func (UnimplementedGateServer) Broadcast(context.Context, *BroadcastRequest) (*BroadcastReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Broadcast not implemented")
 }
+func (UnimplementedGateServer) JoinChannel(context.Context, *JoinChannelRequest) (*JoinChannelReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinChannel not implemented")
+}
+func (UnimplementedGateServer) LeaveChannel(context.Context, *LeaveChannelRequest) (*LeaveChannelReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaveChannel not implemented")
+}
+func (UnimplementedGateServer) PublishChannel(context.Context, *PublishChannelRequest) (*PublishChannelReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishChannel not implemented")
+}
 func (UnimplementedGateServer) mustEmbedUnimplementedGateServer() {}
 
 // UnsafeGateServer may be embedded to opt out of forward compatibility for this service.
@@ -222,6 +350,78 @@ func _Gate_GetIP_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Gate_GetAttrs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAttrsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GateServer).GetAttrs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Gate/GetAttrs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GateServer).GetAttrs(ctx, req.(*GetAttrsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gate_SetAttrs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAttrsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GateServer).SetAttrs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Gate/SetAttrs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GateServer).SetAttrs(ctx, req.(*SetAttrsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gate_DelAttrs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelAttrsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GateServer).DelAttrs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Gate/DelAttrs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GateServer).DelAttrs(ctx, req.(*DelAttrsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gate_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GateServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Gate/Resume",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GateServer).Resume(ctx, req.(*ResumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Gate_Disconnect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DisconnectRequest)
 	if err := dec(in); err != nil {
@@ -240,6 +440,24 @@ func _Gate_Disconnect_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Gate_Kick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GateServer).Kick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Gate/Kick",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GateServer).Kick(ctx, req.(*KickRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Gate_Push_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PushRequest)
 	if err := dec(in); err != nil {
@@ -294,6 +512,60 @@ func _Gate_Broadcast_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Gate_JoinChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GateServer).JoinChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Gate/JoinChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GateServer).JoinChannel(ctx, req.(*JoinChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gate_LeaveChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaveChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GateServer).LeaveChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Gate/LeaveChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GateServer).LeaveChannel(ctx, req.(*LeaveChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gate_PublishChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GateServer).PublishChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Gate/PublishChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GateServer).PublishChannel(ctx, req.(*PublishChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Gate_ServiceDesc is the grpc.ServiceDesc for Gate service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -313,10 +585,30 @@ var Gate_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetIP",
 			Handler:    _Gate_GetIP_Handler,
 		},
+		{
+			MethodName: "GetAttrs",
+			Handler:    _Gate_GetAttrs_Handler,
+		},
+		{
+			MethodName: "SetAttrs",
+			Handler:    _Gate_SetAttrs_Handler,
+		},
+		{
+			MethodName: "DelAttrs",
+			Handler:    _Gate_DelAttrs_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _Gate_Resume_Handler,
+		},
 		{
 			MethodName: "Disconnect",
 			Handler:    _Gate_Disconnect_Handler,
 		},
+		{
+			MethodName: "Kick",
+			Handler:    _Gate_Kick_Handler,
+		},
 		{
 			MethodName: "Push",
 			Handler:    _Gate_Push_Handler,
@@ -329,6 +621,18 @@ var Gate_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Broadcast",
 			Handler:    _Gate_Broadcast_Handler,
 		},
+		{
+			MethodName: "JoinChannel",
+			Handler:    _Gate_JoinChannel_Handler,
+		},
+		{
+			MethodName: "LeaveChannel",
+			Handler:    _Gate_LeaveChannel_Handler,
+		},
+		{
+			MethodName: "PublishChannel",
+			Handler:    _Gate_PublishChannel_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "gate.proto",
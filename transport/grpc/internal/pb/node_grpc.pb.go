@@ -26,6 +26,8 @@ type NodeClient interface {
 	Trigger(ctx context.Context, in *TriggerRequest, opts ...grpc.CallOption) (*TriggerReply, error)
 	// 投递消息
 	Deliver(ctx context.Context, in *DeliverRequest, opts ...grpc.CallOption) (*DeliverReply, error)
+	// 调用路由并等待响应
+	Invoke(ctx context.Context, in *DeliverRequest, opts ...grpc.CallOption) (*InvokeReply, error)
 }
 
 type nodeClient struct {
@@ -54,6 +56,15 @@ func (c *nodeClient) Deliver(ctx context.Context, in *DeliverRequest, opts ...gr
 	return out, nil
 }
 
+func (c *nodeClient) Invoke(ctx context.Context, in *DeliverRequest, opts ...grpc.CallOption) (*InvokeReply, error) {
+	out := new(InvokeReply)
+	err := c.cc.Invoke(ctx, "/pb.Node/Invoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NodeServer is the server API for Node service.
 // All implementations must embed UnimplementedNodeServer
 // for forward compatibility
@@ -62,6 +73,8 @@ type NodeServer interface {
 	Trigger(context.Context, *TriggerRequest) (*TriggerReply, error)
 	// 投递消息
 	Deliver(context.Context, *DeliverRequest) (*DeliverReply, error)
+	// 调用路由并等待响应
+	Invoke(context.Context, *DeliverRequest) (*InvokeReply, error)
 	mustEmbedUnimplementedNodeServer()
 }
 
@@ -75,6 +88,9 @@ func (UnimplementedNodeServer) Trigger(context.Context, *TriggerRequest) (*Trigg
 func (UnimplementedNodeServer) Deliver(context.Context, *DeliverRequest) (*DeliverReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Deliver not implemented")
 }
+func (UnimplementedNodeServer) Invoke(context.Context, *DeliverRequest) (*InvokeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Invoke not implemented")
+}
 func (UnimplementedNodeServer) mustEmbedUnimplementedNodeServer() {}
 
 // UnsafeNodeServer may be embedded to opt out of forward compatibility for this service.
@@ -124,6 +140,24 @@ func _Node_Deliver_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Node_Invoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeliverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).Invoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Node/Invoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).Invoke(ctx, req.(*DeliverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Node_ServiceDesc is the grpc.ServiceDesc for Node service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -139,6 +173,10 @@ var Node_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Deliver",
 			Handler:    _Node_Deliver_Handler,
 		},
+		{
+			MethodName: "Invoke",
+			Handler:    _Node_Invoke_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "node.proto",
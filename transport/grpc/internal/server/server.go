@@ -2,6 +2,8 @@ package server
 
 import (
 	"github.com/dobyte/due/internal/endpoint"
+	"github.com/dobyte/due/internal/graceful"
+	"github.com/dobyte/due/log"
 	"github.com/dobyte/due/utils/xnet"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -81,18 +83,25 @@ func (s *Server) Endpoint() *endpoint.Endpoint {
 }
 
 // Start 启动服务器
+// 监听成功后在后台提供服务，监听失败时直接返回错误
 func (s *Server) Start() error {
 	addr, err := net.ResolveTCPAddr("tcp", s.addr)
 	if err != nil {
 		return err
 	}
 
-	s.lis, err = net.Listen(addr.Network(), addr.String())
+	s.lis, err = graceful.Listen(addr.Network(), addr.String())
 	if err != nil {
 		return err
 	}
 
-	return s.server.Serve(s.lis)
+	go func() {
+		if err := s.server.Serve(s.lis); err != nil {
+			log.Errorf("the grpc server serve failed: %v", err)
+		}
+	}()
+
+	return nil
 }
 
 // Stop 停止服务器
@@ -55,10 +55,12 @@ func (c *client) Trigger(ctx context.Context, args *transport.TriggerArgs) (miss
 // Deliver 投递消息
 func (c *client) Deliver(ctx context.Context, args *transport.DeliverArgs) (miss bool, err error) {
 	_, err = c.client.Deliver(ctx, &pb.DeliverRequest{
-		GID: args.GID,
-		NID: args.NID,
-		CID: args.CID,
-		UID: args.UID,
+		GID:    args.GID,
+		NID:    args.NID,
+		CID:    args.CID,
+		UID:    args.UID,
+		Attrs:  args.Attrs,
+		Claims: args.Claims,
 		Message: &pb.Message{
 			Seq:    args.Message.Seq,
 			Route:  args.Message.Route,
@@ -67,6 +69,37 @@ func (c *client) Deliver(ctx context.Context, args *transport.DeliverArgs) (miss
 	}, grpc.UseCompressor(gzip.Name))
 
 	miss = status.Code(err) == code.NotFoundSession
+	err = code.Error(err)
+
+	return
+}
+
+// Invoke 调用路由并等待响应
+func (c *client) Invoke(ctx context.Context, args *transport.DeliverArgs) (reply *transport.Message, miss bool, err error) {
+	res, err := c.client.Invoke(ctx, &pb.DeliverRequest{
+		GID:    args.GID,
+		NID:    args.NID,
+		CID:    args.CID,
+		UID:    args.UID,
+		Attrs:  args.Attrs,
+		Claims: args.Claims,
+		Message: &pb.Message{
+			Seq:    args.Message.Seq,
+			Route:  args.Message.Route,
+			Buffer: args.Message.Buffer,
+		},
+	}, grpc.UseCompressor(gzip.Name))
+	if err != nil {
+		miss = status.Code(err) == code.NotFoundSession
+		err = code.Error(err)
+		return
+	}
+
+	reply = &transport.Message{
+		Seq:    res.Message.Seq,
+		Route:  res.Message.Route,
+		Buffer: res.Message.Buffer,
+	}
 
 	return
 }
@@ -2,6 +2,7 @@ package node
 
 import (
 	"context"
+	"errors"
 	"github.com/dobyte/due/cluster"
 	"github.com/dobyte/due/transport"
 	"github.com/dobyte/due/transport/grpc/internal/code"
@@ -48,10 +49,12 @@ func (e *endpoint) Trigger(ctx context.Context, req *pb.TriggerRequest) (*pb.Tri
 // Deliver 投递消息
 func (e *endpoint) Deliver(ctx context.Context, req *pb.DeliverRequest) (*pb.DeliverReply, error) {
 	miss, err := e.provider.Deliver(ctx, &transport.DeliverArgs{
-		GID: req.GID,
-		NID: req.NID,
-		CID: req.CID,
-		UID: req.UID,
+		GID:    req.GID,
+		NID:    req.NID,
+		CID:    req.CID,
+		UID:    req.UID,
+		Attrs:  req.Attrs,
+		Claims: req.Claims,
 		Message: &transport.Message{
 			Seq:    req.Message.Seq,
 			Route:  req.Message.Route,
@@ -59,12 +62,48 @@ func (e *endpoint) Deliver(ctx context.Context, req *pb.DeliverRequest) (*pb.Del
 		},
 	})
 	if err != nil {
-		if miss {
+		switch {
+		case miss:
 			return nil, status.New(code.NotFoundSession, err.Error()).Err()
-		} else {
+		case errors.Is(err, context.DeadlineExceeded):
+			return nil, status.New(codes.DeadlineExceeded, err.Error()).Err()
+		default:
 			return nil, status.New(codes.Internal, err.Error()).Err()
 		}
 	}
 
 	return &pb.DeliverReply{}, nil
 }
+
+// Invoke 调用路由并等待响应
+func (e *endpoint) Invoke(ctx context.Context, req *pb.DeliverRequest) (*pb.InvokeReply, error) {
+	reply, miss, err := e.provider.Invoke(ctx, &transport.DeliverArgs{
+		GID:    req.GID,
+		NID:    req.NID,
+		CID:    req.CID,
+		UID:    req.UID,
+		Attrs:  req.Attrs,
+		Claims: req.Claims,
+		Message: &transport.Message{
+			Seq:    req.Message.Seq,
+			Route:  req.Message.Route,
+			Buffer: req.Message.Buffer,
+		},
+	})
+	if err != nil {
+		switch {
+		case miss:
+			return nil, status.New(code.NotFoundSession, err.Error()).Err()
+		case errors.Is(err, context.DeadlineExceeded):
+			return nil, status.New(codes.DeadlineExceeded, err.Error()).Err()
+		default:
+			return nil, status.New(codes.Internal, err.Error()).Err()
+		}
+	}
+
+	return &pb.InvokeReply{Message: &pb.Message{
+		Seq:    reply.Seq,
+		Route:  reply.Route,
+		Buffer: reply.Buffer,
+	}}, nil
+}
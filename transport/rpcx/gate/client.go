@@ -72,6 +72,61 @@ func (c *client) GetIP(ctx context.Context, kind session.Kind, target int64) (ip
 	return
 }
 
+// GetAttrs 获取会话属性
+func (c *client) GetAttrs(ctx context.Context, kind session.Kind, target int64) (attrs map[string]string, miss bool, err error) {
+	req := &protocol.GetAttrsRequest{Kind: kind, Target: target}
+	reply := &protocol.GetAttrsReply{}
+	err = c.client.Call(ctx, serviceMethodGetAttrs, req, reply)
+	attrs = reply.Attrs
+	miss = reply.Code == code.NotFoundSession
+	return
+}
+
+// SetAttrs 设置会话属性
+func (c *client) SetAttrs(ctx context.Context, kind session.Kind, target int64, attrs map[string]string) (miss bool, err error) {
+	req := &protocol.SetAttrsRequest{Kind: kind, Target: target, Attrs: attrs}
+	reply := &protocol.SetAttrsReply{}
+	err = c.client.Call(ctx, serviceMethodSetAttrs, req, reply)
+	miss = reply.Code == code.NotFoundSession
+	return
+}
+
+// DelAttrs 删除会话属性
+func (c *client) DelAttrs(ctx context.Context, kind session.Kind, target int64, keys []string) (miss bool, err error) {
+	req := &protocol.DelAttrsRequest{Kind: kind, Target: target, Keys: keys}
+	reply := &protocol.DelAttrsReply{}
+	err = c.client.Call(ctx, serviceMethodDelAttrs, req, reply)
+	miss = reply.Code == code.NotFoundSession
+	return
+}
+
+// Resume 恢复断线的用户会话
+func (c *client) Resume(ctx context.Context, uid int64, nonce string, gid string) (reply *transport.ResumeReply, miss bool, err error) {
+	req := &protocol.ResumeRequest{UID: uid, Nonce: nonce, GID: gid}
+	res := &protocol.ResumeReply{}
+	err = c.client.Call(ctx, serviceMethodResume, req, res)
+	miss = res.Code == code.NotFoundSession
+	if err != nil {
+		return
+	}
+
+	reply = &transport.ResumeReply{
+		Messages: make([]*transport.Message, 0, len(res.Messages)),
+		Attrs:    res.Attrs,
+		Claims:   res.Claims,
+	}
+
+	for _, message := range res.Messages {
+		reply.Messages = append(reply.Messages, &transport.Message{
+			Seq:    message.Seq,
+			Route:  message.Route,
+			Buffer: message.Buffer,
+		})
+	}
+
+	return
+}
+
 // Disconnect 断开连接
 func (c *client) Disconnect(ctx context.Context, kind session.Kind, target int64, isForce bool) (miss bool, err error) {
 	req := &protocol.DisconnectRequest{Kind: kind, Target: target, IsForce: isForce}
@@ -81,6 +136,15 @@ func (c *client) Disconnect(ctx context.Context, kind session.Kind, target int64
 	return
 }
 
+// Kick 踢下线
+func (c *client) Kick(ctx context.Context, kind session.Kind, target int64, reason int32) (miss bool, err error) {
+	req := &protocol.KickRequest{Kind: kind, Target: target, Reason: reason}
+	reply := &protocol.KickReply{}
+	err = c.client.Call(ctx, serviceMethodKick, req, reply)
+	miss = reply.Code == code.NotFoundSession
+	return
+}
+
 // Push 推送消息
 func (c *client) Push(ctx context.Context, kind session.Kind, target int64, message *transport.Message) (miss bool, err error) {
 	req := &protocol.PushRequest{Kind: kind, Target: target, Message: &protocol.Message{
@@ -119,3 +183,32 @@ func (c *client) Broadcast(ctx context.Context, kind session.Kind, message *tran
 	total = reply.Total
 	return
 }
+
+// JoinChannel 加入频道
+func (c *client) JoinChannel(ctx context.Context, channel string, uids []int64) (miss bool, err error) {
+	req := &protocol.JoinChannelRequest{Channel: channel, UIDs: uids}
+	reply := &protocol.JoinChannelReply{}
+	err = c.client.Call(ctx, serviceMethodJoinChannel, req, reply)
+	miss = reply.Code == code.NotFoundSession
+	return
+}
+
+// LeaveChannel 离开频道
+func (c *client) LeaveChannel(ctx context.Context, channel string, uids []int64) error {
+	req := &protocol.LeaveChannelRequest{Channel: channel, UIDs: uids}
+	reply := &protocol.LeaveChannelReply{}
+	return c.client.Call(ctx, serviceMethodLeaveChannel, req, reply)
+}
+
+// PublishChannel 发布频道消息
+func (c *client) PublishChannel(ctx context.Context, channel string, message *transport.Message) (total int64, err error) {
+	req := &protocol.PublishChannelRequest{Channel: channel, Message: &protocol.Message{
+		Seq:    message.Seq,
+		Route:  message.Route,
+		Buffer: message.Buffer,
+	}}
+	reply := &protocol.PublishChannelReply{}
+	err = c.client.Call(ctx, serviceMethodPublishChannel, req, reply)
+	total = reply.Total
+	return
+}
@@ -16,10 +16,19 @@ const (
 	serviceMethodBind       = "Bind"
 	serviceMethodUnbind     = "Unbind"
 	serviceMethodGetIP      = "GetIP"
+	serviceMethodGetAttrs   = "GetAttrs"
+	serviceMethodSetAttrs   = "SetAttrs"
+	serviceMethodDelAttrs   = "DelAttrs"
 	serviceMethodPush       = "Push"
 	serviceMethodMulticast  = "Multicast"
 	serviceMethodBroadcast  = "Broadcast"
 	serviceMethodDisconnect = "Disconnect"
+	serviceMethodResume     = "Resume"
+	serviceMethodKick       = "Kick"
+
+	serviceMethodJoinChannel    = "JoinChannel"
+	serviceMethodLeaveChannel   = "LeaveChannel"
+	serviceMethodPublishChannel = "PublishChannel"
 )
 
 func NewServer(provider transport.GateProvider, opts *server.Options) (*server.Server, error) {
@@ -96,6 +105,65 @@ func (e *endpoint) GetIP(_ context.Context, req *protocol.GetIPRequest, reply *p
 	return err
 }
 
+// GetAttrs 获取会话属性
+func (e *endpoint) GetAttrs(_ context.Context, req *protocol.GetAttrsRequest, reply *protocol.GetAttrsReply) error {
+	attrs, err := e.provider.GetAttrs(req.Kind, req.Target)
+	if err != nil {
+		switch err {
+		case session.ErrNotFoundSession:
+			reply.Code = code.NotFoundSession
+		case session.ErrInvalidSessionKind:
+			reply.Code = code.InvalidArgument
+		case gate.ErrInvalidArgument:
+			reply.Code = code.InvalidArgument
+		default:
+			reply.Code = code.Internal
+		}
+	}
+
+	reply.Attrs = attrs
+
+	return err
+}
+
+// SetAttrs 设置会话属性
+func (e *endpoint) SetAttrs(_ context.Context, req *protocol.SetAttrsRequest, reply *protocol.SetAttrsReply) error {
+	err := e.provider.SetAttrs(req.Kind, req.Target, req.Attrs)
+	if err != nil {
+		switch err {
+		case session.ErrNotFoundSession:
+			reply.Code = code.NotFoundSession
+		case session.ErrInvalidSessionKind:
+			reply.Code = code.InvalidArgument
+		case gate.ErrInvalidArgument:
+			reply.Code = code.InvalidArgument
+		default:
+			reply.Code = code.Internal
+		}
+	}
+
+	return err
+}
+
+// DelAttrs 删除会话属性
+func (e *endpoint) DelAttrs(_ context.Context, req *protocol.DelAttrsRequest, reply *protocol.DelAttrsReply) error {
+	err := e.provider.DelAttrs(req.Kind, req.Target, req.Keys)
+	if err != nil {
+		switch err {
+		case session.ErrNotFoundSession:
+			reply.Code = code.NotFoundSession
+		case session.ErrInvalidSessionKind:
+			reply.Code = code.InvalidArgument
+		case gate.ErrInvalidArgument:
+			reply.Code = code.InvalidArgument
+		default:
+			reply.Code = code.Internal
+		}
+	}
+
+	return err
+}
+
 // Push 推送消息给连接
 func (e *endpoint) Push(_ context.Context, req *protocol.PushRequest, reply *protocol.PushReply) error {
 	err := e.provider.Push(req.Kind, req.Target, &packet.Message{
@@ -159,6 +227,33 @@ func (e *endpoint) Broadcast(_ context.Context, req *protocol.BroadcastRequest,
 	return err
 }
 
+// Resume 恢复断线的用户会话
+func (e *endpoint) Resume(ctx context.Context, req *protocol.ResumeRequest, reply *protocol.ResumeReply) error {
+	res, err := e.provider.Resume(ctx, req.UID, req.Nonce, req.GID)
+	if err != nil {
+		switch err {
+		case session.ErrNotFoundSession:
+			reply.Code = code.NotFoundSession
+		default:
+			reply.Code = code.Internal
+		}
+		return err
+	}
+
+	reply.Messages = make([]*protocol.Message, 0, len(res.Messages))
+	for _, message := range res.Messages {
+		reply.Messages = append(reply.Messages, &protocol.Message{
+			Seq:    message.Seq,
+			Route:  message.Route,
+			Buffer: message.Buffer,
+		})
+	}
+	reply.Attrs = res.Attrs
+	reply.Claims = res.Claims
+
+	return nil
+}
+
 // Disconnect 断开连接
 func (e *endpoint) Disconnect(_ context.Context, req *protocol.DisconnectRequest, reply *protocol.DisconnectReply) error {
 	err := e.provider.Disconnect(req.Kind, req.Target, req.IsForce)
@@ -175,3 +270,61 @@ func (e *endpoint) Disconnect(_ context.Context, req *protocol.DisconnectRequest
 
 	return err
 }
+
+// Kick 踢下线
+func (e *endpoint) Kick(_ context.Context, req *protocol.KickRequest, reply *protocol.KickReply) error {
+	err := e.provider.Kick(req.Kind, req.Target, req.Reason)
+	if err != nil {
+		switch err {
+		case session.ErrNotFoundSession:
+			reply.Code = code.NotFoundSession
+		case session.ErrInvalidSessionKind:
+			reply.Code = code.InvalidArgument
+		default:
+			reply.Code = code.Internal
+		}
+	}
+
+	return err
+}
+
+// JoinChannel 加入频道
+func (e *endpoint) JoinChannel(_ context.Context, req *protocol.JoinChannelRequest, reply *protocol.JoinChannelReply) error {
+	err := e.provider.JoinChannel(req.Channel, req.UIDs)
+	if err != nil {
+		switch err {
+		case session.ErrNotFoundSession:
+			reply.Code = code.NotFoundSession
+		default:
+			reply.Code = code.Internal
+		}
+	}
+
+	return err
+}
+
+// LeaveChannel 离开频道
+func (e *endpoint) LeaveChannel(_ context.Context, req *protocol.LeaveChannelRequest, reply *protocol.LeaveChannelReply) error {
+	err := e.provider.LeaveChannel(req.Channel, req.UIDs)
+	if err != nil {
+		reply.Code = code.Internal
+	}
+
+	return err
+}
+
+// PublishChannel 发布频道消息
+func (e *endpoint) PublishChannel(_ context.Context, req *protocol.PublishChannelRequest, reply *protocol.PublishChannelReply) error {
+	total, err := e.provider.PublishChannel(req.Channel, &packet.Message{
+		Seq:    req.Message.Seq,
+		Route:  req.Message.Route,
+		Buffer: req.Message.Buffer,
+	})
+	if err != nil {
+		reply.Code = code.Internal
+	}
+
+	reply.Total = total
+
+	return err
+}
@@ -1,8 +1,33 @@
 package code
 
+import "context"
+
 const (
 	InvalidArgument = iota + 1
 	Internal
 
 	NotFoundSession = iota + 100
+	Timeout
 )
+
+// 调用超时的错误
+type timeoutError struct {
+	error
+}
+
+func (e *timeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+func (e *timeoutError) Unwrap() error {
+	return e.error
+}
+
+// Error 转换调用错误，调用超时的错误可通过errors.Is(err, context.DeadlineExceeded)判断
+func Error(err error, code int) error {
+	if err != nil && code == Timeout {
+		return &timeoutError{err}
+	}
+
+	return err
+}
@@ -29,6 +29,36 @@ type GetIPReply struct {
 	IP   string
 }
 
+type GetAttrsRequest struct {
+	Kind   session.Kind
+	Target int64
+}
+
+type GetAttrsReply struct {
+	Code  int
+	Attrs map[string]string
+}
+
+type SetAttrsRequest struct {
+	Kind   session.Kind
+	Target int64
+	Attrs  map[string]string
+}
+
+type SetAttrsReply struct {
+	Code int
+}
+
+type DelAttrsRequest struct {
+	Kind   session.Kind
+	Target int64
+	Keys   []string
+}
+
+type DelAttrsReply struct {
+	Code int
+}
+
 type PushRequest struct {
 	Kind    session.Kind
 	Target  int64
@@ -60,6 +90,19 @@ type BroadcastReply struct {
 	Total int64
 }
 
+type ResumeRequest struct {
+	UID   int64
+	Nonce string
+	GID   string
+}
+
+type ResumeReply struct {
+	Code     int
+	Messages []*Message
+	Attrs    map[string]string
+	Claims   map[string]string
+}
+
 type DisconnectRequest struct {
 	Kind    session.Kind
 	Target  int64
@@ -69,3 +112,41 @@ type DisconnectRequest struct {
 type DisconnectReply struct {
 	Code int
 }
+
+type KickRequest struct {
+	Kind   session.Kind
+	Target int64
+	Reason int32
+}
+
+type KickReply struct {
+	Code int
+}
+
+type JoinChannelRequest struct {
+	Channel string
+	UIDs    []int64
+}
+
+type JoinChannelReply struct {
+	Code int
+}
+
+type LeaveChannelRequest struct {
+	Channel string
+	UIDs    []int64
+}
+
+type LeaveChannelReply struct {
+	Code int
+}
+
+type PublishChannelRequest struct {
+	Channel string
+	Message *Message
+}
+
+type PublishChannelReply struct {
+	Code  int
+	Total int64
+}
@@ -19,9 +19,16 @@ type DeliverRequest struct {
 	NID     string
 	CID     int64
 	UID     int64
+	Attrs   map[string]string
+	Claims  map[string]string
 	Message *Message
 }
 
 type DeliverReply struct {
 	Code int
 }
+
+type InvokeReply struct {
+	Code    int
+	Message *Message
+}
@@ -2,6 +2,8 @@ package server
 
 import (
 	"github.com/dobyte/due/internal/endpoint"
+	"github.com/dobyte/due/internal/graceful"
+	"github.com/dobyte/due/log"
 	"github.com/dobyte/due/utils/xnet"
 	"github.com/smallnest/rpcx/server"
 	"net"
@@ -62,8 +64,25 @@ func (s *Server) Endpoint() *endpoint.Endpoint {
 }
 
 // Start 启动服务器
+// 监听成功后在后台提供服务，监听失败时直接返回错误
 func (s *Server) Start() error {
-	return s.server.Serve("tcp", s.addr)
+	addr, err := net.ResolveTCPAddr("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	s.lis, err = graceful.Listen(addr.Network(), addr.String())
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.server.ServeListener("tcp", s.lis); err != nil && err != server.ErrServerClosed {
+			log.Errorf("the rpcx server serve failed: %v", err)
+		}
+	}()
+
+	return nil
 }
 
 // Stop 停止服务器
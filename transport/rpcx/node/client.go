@@ -55,7 +55,7 @@ func (c *client) Trigger(ctx context.Context, args *transport.TriggerArgs) (miss
 
 // Deliver 投递消息
 func (c *client) Deliver(ctx context.Context, args *transport.DeliverArgs) (miss bool, err error) {
-	req := &protocol.DeliverRequest{GID: args.GID, NID: args.NID, CID: args.CID, UID: args.UID, Message: &protocol.Message{
+	req := &protocol.DeliverRequest{GID: args.GID, NID: args.NID, CID: args.CID, UID: args.UID, Attrs: args.Attrs, Claims: args.Claims, Message: &protocol.Message{
 		Seq:    args.Message.Seq,
 		Route:  args.Message.Route,
 		Buffer: args.Message.Buffer,
@@ -63,6 +63,31 @@ func (c *client) Deliver(ctx context.Context, args *transport.DeliverArgs) (miss
 	reply := &protocol.DeliverReply{}
 	err = c.client.Call(ctx, serviceDeliverMethod, req, reply)
 	miss = reply.Code == code.NotFoundSession
+	err = code.Error(err, reply.Code)
+
+	return
+}
+
+// Invoke 调用路由并等待响应
+func (c *client) Invoke(ctx context.Context, args *transport.DeliverArgs) (reply *transport.Message, miss bool, err error) {
+	req := &protocol.DeliverRequest{GID: args.GID, NID: args.NID, CID: args.CID, UID: args.UID, Attrs: args.Attrs, Claims: args.Claims, Message: &protocol.Message{
+		Seq:    args.Message.Seq,
+		Route:  args.Message.Route,
+		Buffer: args.Message.Buffer,
+	}}
+	res := &protocol.InvokeReply{}
+	err = c.client.Call(ctx, serviceInvokeMethod, req, res)
+	miss = res.Code == code.NotFoundSession
+	err = code.Error(err, res.Code)
+	if err != nil {
+		return
+	}
+
+	reply = &transport.Message{
+		Seq:    res.Message.Seq,
+		Route:  res.Message.Route,
+		Buffer: res.Message.Buffer,
+	}
 
 	return
 }
@@ -2,6 +2,7 @@ package node
 
 import (
 	"context"
+	"errors"
 	"github.com/dobyte/due/transport"
 	"github.com/dobyte/due/transport/rpcx/internal/code"
 	"github.com/dobyte/due/transport/rpcx/internal/protocol"
@@ -12,6 +13,7 @@ const (
 	servicePath          = "Node"
 	serviceTriggerMethod = "Trigger"
 	serviceDeliverMethod = "Deliver"
+	serviceInvokeMethod  = "Invoke"
 )
 
 func NewServer(provider transport.NodeProvider, opts *server.Options) (*server.Server, error) {
@@ -50,10 +52,12 @@ func (e *endpoint) Trigger(ctx context.Context, req *protocol.TriggerRequest, re
 // Deliver 投递消息
 func (e *endpoint) Deliver(ctx context.Context, req *protocol.DeliverRequest, reply *protocol.DeliverReply) error {
 	miss, err := e.provider.Deliver(ctx, &transport.DeliverArgs{
-		GID: req.GID,
-		NID: req.NID,
-		CID: req.CID,
-		UID: req.UID,
+		GID:    req.GID,
+		NID:    req.NID,
+		CID:    req.CID,
+		UID:    req.UID,
+		Attrs:  req.Attrs,
+		Claims: req.Claims,
 		Message: &transport.Message{
 			Seq:    req.Message.Seq,
 			Route:  req.Message.Route,
@@ -61,12 +65,51 @@ func (e *endpoint) Deliver(ctx context.Context, req *protocol.DeliverRequest, re
 		},
 	})
 	if err != nil {
-		if miss {
+		switch {
+		case miss:
 			reply.Code = code.NotFoundSession
-		} else {
+		case errors.Is(err, context.DeadlineExceeded):
+			reply.Code = code.Timeout
+		default:
 			reply.Code = code.Internal
 		}
 	}
 
 	return err
 }
+
+// Invoke 调用路由并等待响应
+func (e *endpoint) Invoke(ctx context.Context, req *protocol.DeliverRequest, reply *protocol.InvokeReply) error {
+	res, miss, err := e.provider.Invoke(ctx, &transport.DeliverArgs{
+		GID:    req.GID,
+		NID:    req.NID,
+		CID:    req.CID,
+		UID:    req.UID,
+		Attrs:  req.Attrs,
+		Claims: req.Claims,
+		Message: &transport.Message{
+			Seq:    req.Message.Seq,
+			Route:  req.Message.Route,
+			Buffer: req.Message.Buffer,
+		},
+	})
+	if err != nil {
+		switch {
+		case miss:
+			reply.Code = code.NotFoundSession
+		case errors.Is(err, context.DeadlineExceeded):
+			reply.Code = code.Timeout
+		default:
+			reply.Code = code.Internal
+		}
+		return err
+	}
+
+	reply.Message = &protocol.Message{
+		Seq:    res.Seq,
+		Route:  res.Route,
+		Buffer: res.Buffer,
+	}
+
+	return nil
+}
@@ -15,7 +15,7 @@ type Server interface {
 	Scheme() string
 	// Endpoint 服务端口
 	Endpoint() *endpoint.Endpoint
-	// Start 启动服务器
+	// Start 启动服务器，监听成功后在后台提供服务
 	Start() error
 	// Stop 停止服务器
 	Stop() error
@@ -28,14 +28,30 @@ type GateProvider interface {
 	Unbind(ctx context.Context, uid int64) error
 	// GetIP 获取客户端IP地址
 	GetIP(kind session.Kind, target int64) (ip string, err error)
+	// GetAttrs 获取会话属性
+	GetAttrs(kind session.Kind, target int64) (attrs map[string]string, err error)
+	// SetAttrs 设置会话属性
+	SetAttrs(kind session.Kind, target int64, attrs map[string]string) error
+	// DelAttrs 删除会话属性
+	DelAttrs(kind session.Kind, target int64, keys []string) error
 	// Push 发送消息（异步）
 	Push(kind session.Kind, target int64, message *packet.Message) error
 	// Multicast 推送组播消息（异步）
 	Multicast(kind session.Kind, targets []int64, message *packet.Message) (total int64, err error)
 	// Broadcast 推送广播消息（异步）
 	Broadcast(kind session.Kind, message *packet.Message) (total int64, err error)
+	// Resume 恢复断线的用户会话，会话移交给指定网关
+	Resume(ctx context.Context, uid int64, nonce string, gid string) (*ResumeReply, error)
 	// Disconnect 断开连接
 	Disconnect(kind session.Kind, target int64, isForce bool) error
+	// Kick 踢下线，reason为踢下线原因的错误码
+	Kick(kind session.Kind, target int64, reason int32) error
+	// JoinChannel 加入频道，仅绑定到当前网关的用户可以加入
+	JoinChannel(channel string, uids []int64) error
+	// LeaveChannel 离开频道，用户为空时解散频道
+	LeaveChannel(channel string, uids []int64) error
+	// PublishChannel 发布频道消息（异步）
+	PublishChannel(channel string, message *packet.Message) (total int64, err error)
 }
 
 type NodeProvider interface {
@@ -47,6 +63,8 @@ type NodeProvider interface {
 	Trigger(ctx context.Context, args *TriggerArgs) (miss bool, err error)
 	// Deliver 投递消息
 	Deliver(ctx context.Context, args *DeliverArgs) (miss bool, err error)
+	// Invoke 调用路由并等待响应
+	Invoke(ctx context.Context, args *DeliverArgs) (reply *Message, miss bool, err error)
 }
 
 type DeliverArgs struct {
@@ -54,6 +72,8 @@ type DeliverArgs struct {
 	NID     string
 	CID     int64
 	UID     int64
+	Attrs   map[string]string
+	Claims  map[string]string
 	Message *Message
 }
 
@@ -0,0 +1,269 @@
+package xcron
+
+import (
+	"fmt"
+	"github.com/dobyte/due/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 调度计划
+type Schedule interface {
+	// Next 获取指定时间之后的下一次执行时间，不存在时返回零值
+	Next(t time.Time) time.Time
+}
+
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	seconds = bounds{0, 59, nil}
+	minutes = bounds{0, 59, nil}
+	hours   = bounds{0, 23, nil}
+	doms    = bounds{1, 31, nil}
+	months  = bounds{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dows = bounds{0, 6, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// 任意值标记，用于区分日期与星期字段是否受限
+const starBit = 1 << 63
+
+type schedule struct {
+	second, minute, hour, dom, month, dow uint64
+}
+
+// Parse 解析cron表达式
+// 支持5个字段（分 时 日 月 周）或6个字段（秒 分 时 日 月 周），
+// 字段支持 *、?、数值、名称、范围（a-b）、步长（*/n、a-b/n）及列表（a,b）；
+// 同时支持 @yearly、@monthly、@weekly、@daily、@hourly 等预定义表达式
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if v, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = v
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, errors.New(fmt.Sprintf("invalid cron spec %q: expected 5 or 6 fields, got %d", spec, len(fields)))
+	}
+
+	var (
+		s   = &schedule{}
+		err error
+	)
+
+	for i, item := range []struct {
+		field *uint64
+		b     bounds
+	}{
+		{&s.second, seconds},
+		{&s.minute, minutes},
+		{&s.hour, hours},
+		{&s.dom, doms},
+		{&s.month, months},
+		{&s.dow, dows},
+	} {
+		if *item.field, err = parseField(fields[i], item.b); err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid cron spec %q: %v", spec, err))
+		}
+	}
+
+	return s, nil
+}
+
+// 解析字段
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+
+	for _, expr := range strings.Split(field, ",") {
+		v, err := parseExpr(expr, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= v
+	}
+
+	return bits, nil
+}
+
+// 解析字段中的单个表达式
+func parseExpr(expr string, b bounds) (uint64, error) {
+	var (
+		start, end, step = b.min, b.max, 1
+		star             = false
+		err              error
+	)
+
+	rangeAndStep := strings.Split(expr, "/")
+	if len(rangeAndStep) > 2 {
+		return 0, errors.New(fmt.Sprintf("too many slashes: %s", expr))
+	}
+
+	switch lowAndHigh := strings.Split(rangeAndStep[0], "-"); {
+	case lowAndHigh[0] == "*" || lowAndHigh[0] == "?":
+		if len(lowAndHigh) > 1 {
+			return 0, errors.New(fmt.Sprintf("invalid range: %s", expr))
+		}
+		star = true
+	case len(lowAndHigh) == 1:
+		if start, err = parseValue(lowAndHigh[0], b); err != nil {
+			return 0, err
+		}
+		end = start
+		if len(rangeAndStep) == 2 {
+			end = b.max
+		}
+	case len(lowAndHigh) == 2:
+		if start, err = parseValue(lowAndHigh[0], b); err != nil {
+			return 0, err
+		}
+		if end, err = parseValue(lowAndHigh[1], b); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, errors.New(fmt.Sprintf("too many hyphens: %s", expr))
+	}
+
+	if len(rangeAndStep) == 2 {
+		if step, err = strconv.Atoi(rangeAndStep[1]); err != nil || step <= 0 {
+			return 0, errors.New(fmt.Sprintf("invalid step: %s", expr))
+		}
+		star = false
+	}
+
+	if start < b.min || end > b.max || start > end {
+		return 0, errors.New(fmt.Sprintf("out of range [%d, %d]: %s", b.min, b.max, expr))
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+
+	if star {
+		bits |= starBit
+	}
+
+	return bits, nil
+}
+
+// 解析数值或名称
+func parseValue(value string, b bounds) (int, error) {
+	if v, ok := b.names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("invalid value: %s", value))
+	}
+
+	return v, nil
+}
+
+// Next 获取指定时间之后的下一次执行时间，五年内无匹配时间时返回零值
+func (s *schedule) Next(t time.Time) time.Time {
+	t = t.Add(time.Second - time.Duration(t.Nanosecond()))
+
+	limit := t.Year() + 5
+
+	added := false
+
+WRAP:
+	if t.Year() > limit {
+		return time.Time{}
+	}
+
+	for 1<<uint(t.Month())&s.month == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		}
+		t = t.AddDate(0, 1, 0)
+
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !s.matchDay(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		}
+		t = t.AddDate(0, 0, 1)
+
+		if t.Day() == 1 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Hour())&s.hour == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+		}
+		t = t.Add(time.Hour)
+
+		if t.Hour() == 0 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Minute())&s.minute == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Minute)
+		}
+		t = t.Add(time.Minute)
+
+		if t.Minute() == 0 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Second())&s.second == 0 {
+		t = t.Add(time.Second)
+
+		if t.Second() == 0 {
+			goto WRAP
+		}
+	}
+
+	return t
+}
+
+// 日期与星期均受限时满足其一即可，否则须同时满足
+func (s *schedule) matchDay(t time.Time) bool {
+	domMatch := 1<<uint(t.Day())&s.dom > 0
+	dowMatch := 1<<uint(t.Weekday())&s.dow > 0
+
+	if s.dom&starBit > 0 || s.dow&starBit > 0 {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
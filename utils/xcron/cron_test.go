@@ -0,0 +1,44 @@
+package xcron_test
+
+import (
+	"github.com/dobyte/due/utils/xcron"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := xcron.Parse(spec); err == nil {
+			t.Errorf("spec %q: expected error", spec)
+		}
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	base := time.Date(2022, 6, 19, 12, 20, 30, 500, time.Local)
+
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2022, 6, 19, 12, 21, 0, 0, time.Local)},
+		{"*/15 * * * * *", time.Date(2022, 6, 19, 12, 20, 45, 0, time.Local)},
+		{"30 2 * * *", time.Date(2022, 6, 20, 2, 30, 0, 0, time.Local)},
+		{"0 0 1 jan *", time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)},
+		{"0 9 * * mon-fri", time.Date(2022, 6, 20, 9, 0, 0, 0, time.Local)},
+		{"0 0 13 * 5", time.Date(2022, 6, 24, 0, 0, 0, 0, time.Local)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local)},
+		{"@hourly", time.Date(2022, 6, 19, 13, 0, 0, 0, time.Local)},
+	}
+
+	for _, c := range cases {
+		s, err := xcron.Parse(c.spec)
+		if err != nil {
+			t.Fatalf("spec %q: %v", c.spec, err)
+		}
+
+		if got := s.Next(base); !got.Equal(c.want) {
+			t.Errorf("spec %q: got %v, want %v", c.spec, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package xrate
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Bucket 令牌桶，按固定速率生成令牌，桶中最多保留burst个令牌
+type Bucket struct {
+	mu     sync.Mutex
+	rate   float64   // 每秒生成的令牌数
+	burst  float64   // 桶容量
+	tokens float64   // 当前令牌数
+	last   time.Time // 上次生成令牌的时间
+}
+
+// NewBucket 创建令牌桶，rate不大于0时不限制；burst不大于0时桶容量为rate向上取整
+func NewBucket(rate float64, burst int) *Bucket {
+	b := &Bucket{rate: rate, burst: float64(burst)}
+	if b.burst <= 0 {
+		b.burst = math.Max(1, math.Ceil(rate))
+	}
+	b.tokens = b.burst
+
+	return b
+}
+
+// Allow 获取一个令牌
+func (b *Bucket) Allow() bool {
+	return b.AllowAt(time.Now())
+}
+
+// AllowAt 在指定时间获取一个令牌
+func (b *Bucket) AllowAt(now time.Time) bool {
+	if b.rate <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	if now.After(b.last) {
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
@@ -0,0 +1,42 @@
+package xrate_test
+
+import (
+	"github.com/dobyte/due/utils/xrate"
+	"testing"
+	"time"
+)
+
+func TestBucket_AllowAt(t *testing.T) {
+	var (
+		now    = time.Now()
+		bucket = xrate.NewBucket(2, 3)
+	)
+
+	for i := 0; i < 3; i++ {
+		if !bucket.AllowAt(now) {
+			t.Fatalf("expected token %d to be allowed", i)
+		}
+	}
+
+	if bucket.AllowAt(now) {
+		t.Fatal("expected bucket to be exhausted")
+	}
+
+	if !bucket.AllowAt(now.Add(500 * time.Millisecond)) {
+		t.Fatal("expected token to be refilled")
+	}
+
+	if bucket.AllowAt(now.Add(500 * time.Millisecond)) {
+		t.Fatal("expected bucket to be exhausted")
+	}
+}
+
+func TestBucket_Unlimited(t *testing.T) {
+	bucket := xrate.NewBucket(0, 0)
+
+	for i := 0; i < 100; i++ {
+		if !bucket.Allow() {
+			t.Fatal("expected unlimited bucket to always allow")
+		}
+	}
+}